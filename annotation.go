@@ -0,0 +1,131 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const (
+	annotationDuration float64 = 80 * dt // simulated time an annotation stays on screen in s
+	annotationFontSize float64 = 20      // font size of the annotations
+)
+
+var annotationColor = color.RGBA{255, 255, 160, 255}
+
+// determines what makes an annotation appear
+type annotationTrigger int
+
+const (
+	triggerTime      annotationTrigger = iota // the simulated time reaches the time of the annotation
+	triggerSOIEntry                           // the body becomes the one pulling strongest on the spacecraft
+	triggerSOIExit                            // the body stops being the one pulling strongest on the spacecraft
+	triggerPeriapsis                          // the spacecraft passes its closest point to the body
+)
+
+// Annotation is a text a scene shows once when its trigger fires, e.g. to explain what is happening
+type Annotation struct {
+	text    string
+	trigger annotationTrigger
+	time    float64 // simulated time in s for triggerTime
+	body    string  // name of the body for the other triggers
+	fired   bool
+	firedAt float64 // simulated time the annotation appeared at in s
+}
+
+// an event of the spacecraft the annotations can react to
+type spacecraftEvent struct {
+	trigger annotationTrigger
+	body    string
+}
+
+// remembers the state of the previous step needed to detect spacecraft events
+type eventTracker struct {
+	dominant       *SpaceObject // body pulling strongest on the spacecraft
+	radialVelocity float64      // speed towards (negative) or away from (positive) the dominant body in m/s
+}
+
+// registers an annotation, scenes call this to script their explanations
+func (g *Game) addAnnotation(a Annotation) {
+	g.annotations = append(g.annotations, &a)
+}
+
+// returns the events of the spacecraft since the previous step
+func (g *Game) detectEvents() []spacecraftEvent {
+	craft, body := g.spacecraftAndDominantBody()
+	if craft == nil {
+		g.events = eventTracker{}
+		return nil
+	}
+
+	var events []spacecraftEvent
+	previous := g.events
+	if body != previous.dominant {
+		if previous.dominant != nil {
+			events = append(events, spacecraftEvent{triggerSOIExit, previous.dominant.name})
+		}
+		if body != nil {
+			events = append(events, spacecraftEvent{triggerSOIEntry, body.name})
+		}
+	}
+
+	radialVelocity := 0.0
+	if body != nil {
+		r := craft.position.Translate(-body.position.X, -body.position.Y)
+		v := craft.velocity.Translate(-body.velocity.X, -body.velocity.Y)
+		radialVelocity, _ = radialTangential(r, v)
+
+		// the distance stopped shrinking and grows again
+		if body == previous.dominant && previous.radialVelocity < 0 && radialVelocity >= 0 {
+			events = append(events, spacecraftEvent{triggerPeriapsis, body.name})
+		}
+	}
+
+	g.events = eventTracker{dominant: body, radialVelocity: radialVelocity}
+	return events
+}
+
+// fires the annotations whose trigger is among the events of the last step
+func (g *Game) updateAnnotations(events []spacecraftEvent) {
+	for _, a := range g.annotations {
+		if a.fired {
+			continue
+		}
+		switch a.trigger {
+		case triggerTime:
+			a.fired = g.time >= a.time
+		default:
+			for _, event := range events {
+				if event.trigger == a.trigger && event.body == a.body {
+					a.fired = true
+				}
+			}
+		}
+		if a.fired {
+			a.firedAt = g.time
+		}
+	}
+}
+
+// draws the annotations that fired recently centered at the bottom of the viewport, the newest one at the bottom
+func (g *Game) drawAnnotations(screen *ebiten.Image) {
+	viewport := g.viewport()
+	face := &text.GoTextFace{Source: mplusFaceSource, Size: annotationFontSize}
+
+	var visible []*Annotation
+	for _, a := range g.annotations {
+		if a.fired && g.time-a.firedAt < annotationDuration {
+			visible = append(visible, a)
+		}
+	}
+
+	for i, a := range visible {
+		op := &text.DrawOptions{}
+		line := float64(len(visible)-i) * annotationFontSize * 1.5
+		op.GeoM.Translate(float64(viewport.Min.X+viewport.Max.X)/2, float64(viewport.Max.Y)-line)
+		op.PrimaryAlign = text.AlignCenter
+		op.ColorScale.ScaleWithColor(annotationColor)
+		text.Draw(screen, a.text, face, op)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import "testing"
+
+func TestTimedAnnotationsFireAtTheirTime(t *testing.T) {
+	g := circularOrbitGame(testOrbitRadius)
+	g.addAnnotation(Annotation{text: "start", trigger: triggerTime})
+	g.addAnnotation(Annotation{text: "later", trigger: triggerTime, time: 5 * dt})
+	g.addAnnotation(Annotation{text: "between steps", trigger: triggerTime, time: 7.5 * dt})
+
+	for range 10 {
+		g.Step()
+	}
+	// a step fires the annotations due at the time it starts from
+	for i, want := range []float64{0, 5 * dt, 8 * dt} {
+		a := g.annotations[i]
+		if !a.fired || a.firedAt != want {
+			t.Errorf("%q fired %v at %v s, want at %v s", a.text, a.fired, a.firedAt, want)
+		}
+	}
+}
+
+func TestSlingshotAnnotationsFireInOrder(t *testing.T) {
+	g := NewSlingshotGame()
+	for steps := 0; ; steps++ {
+		fired := 0
+		for _, a := range g.annotations {
+			if a.fired {
+				fired++
+			}
+		}
+		if fired == len(g.annotations) {
+			break
+		}
+		if steps > 500 {
+			t.Fatalf("only %d of %d annotations fired after %v s", fired, len(g.annotations), g.time)
+		}
+		g.Step()
+	}
+
+	// the start, the approach, the closest approach and the departure appear one after another
+	for i := 1; i < len(g.annotations); i++ {
+		if g.annotations[i].firedAt <= g.annotations[i-1].firedAt {
+			t.Errorf("%q fired at %v s, not after %q at %v s",
+				g.annotations[i].text, g.annotations[i].firedAt, g.annotations[i-1].text, g.annotations[i-1].firedAt)
+		}
+	}
+}
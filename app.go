@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const (
+	menuFontSize   float64 = 16 // font size of the menu entries
+	menuLineHeight float64 = 24 // distance between two menu entries in pixel
+	menuTop        float64 = 40 // position of the first menu entry in pixel
+)
+
+// App shows the scene menu until a scene is picked and then runs its game
+type App struct {
+	game         *Game       // running game, nil while the menu is shown
+	scene        Scene       // scene the running game was started from
+	configure    func(*Game) // applies the settings every started game should have
+	menuIndex    int         // highlighted menu entry
+	screenWidth  int
+	screenHeight int
+}
+
+// returns an app showing the scene menu
+func NewApp(configure func(*Game)) *App {
+	return &App{configure: configure}
+}
+
+// starts the game of the given scene, leaving the menu
+func (a *App) Start(scene Scene) error {
+	game := scene.create()
+	a.configure(game)
+	if err := game.checkBodyCount(); err != nil {
+		return fmt.Errorf("scene %s: %w", scene.name, err)
+	}
+	game.screenWidth = a.screenWidth
+	game.screenHeight = a.screenHeight
+	a.game = game
+	a.scene = scene
+	return nil
+}
+
+// starts the running scene again; the trails of the old run stay as ghosts if the game keeps any
+func (a *App) reset() {
+	old := a.game
+	if err := a.Start(a.scene); err != nil {
+		log.Printf("reset failed: %v\n", err)
+		return
+	}
+	if a.game.maxGhosts > 0 {
+		a.game.ghosts = pushGhost(old.ghosts, old.combinedTrails(), a.game.maxGhosts)
+	}
+}
+
+// starts the scene picked in the menu, the menu stays if it cannot be started
+func (a *App) startFromMenu(scene Scene) {
+	if err := a.Start(scene); err != nil {
+		log.Printf("starting failed: %v\n", err)
+	}
+}
+
+// returns the menu entry under the given screen position, -1 if there is none
+func menuEntryAt(x, y int) int {
+	index := int((float64(y) - menuTop) / menuLineHeight)
+	if float64(y) < menuTop || index >= len(scenes) || x < 0 {
+		return -1
+	}
+	return index
+}
+
+// picks a scene with the number keys, arrow keys and enter, or with a click
+func (a *App) updateMenu() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyDown) {
+		a.menuIndex = (a.menuIndex + 1) % len(scenes)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+		a.menuIndex = (a.menuIndex + len(scenes) - 1) % len(scenes)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		a.startFromMenu(scenes[a.menuIndex])
+		return
+	}
+
+	for i := range scenes {
+		if i < 9 && inpututil.IsKeyJustPressed(ebiten.Key1+ebiten.Key(i)) {
+			a.startFromMenu(scenes[i])
+			return
+		}
+	}
+
+	x, y := ebiten.CursorPosition()
+	if index := menuEntryAt(x, y); index >= 0 {
+		a.menuIndex = index
+		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+			a.startFromMenu(scenes[index])
+		}
+	}
+}
+
+func (a *App) Update() error {
+	if a.game == nil {
+		a.updateMenu()
+		return nil
+	}
+	if err := a.game.Update(); err != nil {
+		return err
+	}
+	if a.game.resetRequested {
+		a.reset()
+	}
+	return nil
+}
+
+func (a *App) Draw(screen *ebiten.Image) {
+	if a.game != nil {
+		a.game.Draw(screen)
+		return
+	}
+
+	face := &text.GoTextFace{Source: mplusFaceSource, Size: menuFontSize}
+
+	title := &text.DrawOptions{}
+	title.GeoM.Translate(20, 10)
+	text.Draw(screen, "Pick a scene:", face, title)
+
+	for i, scene := range scenes {
+		entry := strconv.Itoa(i+1) + ". " + scene.description
+		if i == a.menuIndex {
+			entry = "> " + entry
+		}
+		op := &text.DrawOptions{}
+		op.GeoM.Translate(20, menuTop+float64(i)*menuLineHeight)
+		text.Draw(screen, entry, face, op)
+	}
+}
+
+func (a *App) Layout(outsideWidth, outsideHeight int) (int, int) {
+	a.screenWidth = outsideWidth
+	a.screenHeight = outsideHeight
+	if a.game != nil {
+		return a.game.Layout(outsideWidth, outsideHeight)
+	}
+	return outsideWidth, outsideHeight
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const bannerFontSize float64 = 28 // font size of the auto-pause banner
+
+var bannerColor = color.RGBA{255, 200, 80, 255}
+
+// pauses the simulation when something happens to the spacecraft, for demos that run unattended
+// every trigger is disabled by its zero value
+type AutoPause struct {
+	onCrash  bool // pause when the spacecraft merged with another body
+	onEscape bool // pause when the orbit of the spacecraft becomes hyperbolic
+
+	banner        string // explanation shown while paused, empty if the pause was not automatic
+	hadSpacecraft bool   // the spacecraft existed before the current step
+	escaping      bool   // the orbit of the spacecraft was hyperbolic in the previous step
+}
+
+// pauses the simulation after a step in which an enabled trigger fired and says why
+// the escape trigger fires when the orbit becomes hyperbolic, so resuming does not pause again right away
+func (g *Game) checkAutoPause() {
+	p := &g.autoPause
+	if g.spacecraftIndex() >= 0 {
+		p.hadSpacecraft = true
+	} else if p.hadSpacecraft {
+		p.hadSpacecraft = false
+		if p.onCrash {
+			g.pauseWithBanner("The spacecraft crashed")
+		}
+	}
+
+	escaping := g.OrbitClassification() == orbitEscape
+	if escaping && !p.escaping && p.onEscape {
+		_, body := g.spacecraftAndDominantBody()
+		g.pauseWithBanner("The spacecraft is escaping " + body.name + " on a hyperbolic orbit")
+	}
+	p.escaping = escaping
+}
+
+// pauses the simulation and shows the banner until it is resumed
+func (g *Game) pauseWithBanner(banner string) {
+	g.paused = true
+	g.autoPause.banner = banner
+}
+
+// draws the banner of an automatic pause centered in the viewport
+func (g *Game) drawBanner(screen *ebiten.Image) {
+	if !g.paused || g.autoPause.banner == "" {
+		return
+	}
+	viewport := g.viewport()
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(float64(viewport.Min.X+viewport.Max.X)/2, float64(viewport.Min.Y+viewport.Max.Y)/2)
+	op.PrimaryAlign = text.AlignCenter
+	op.SecondaryAlign = text.AlignCenter
+	op.LineSpacing = bannerFontSize * 1.5
+	op.ColorScale.ScaleWithColor(bannerColor)
+	text.Draw(screen, g.autoPause.banner+"\nPaused, press space to continue", &text.GoTextFace{Source: mplusFaceSource, Size: bannerFontSize}, op)
+}
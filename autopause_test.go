@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAutoPause(t *testing.T) {
+	falling := func() *Game {
+		g := circularOrbitGame(2 * testStarRadius)
+		g.spaceObjects[1].velocity = Vector{0, 0}
+		return g
+	}
+	escaping := func() *Game {
+		g := circularOrbitGame(testOrbitRadius)
+		g.spaceObjects[1].velocity = Vector{0, 2 * g.config.EscapeVelocity(testStarMass, testOrbitRadius)}
+		return g
+	}
+	tests := []struct {
+		name    string
+		create  func() *Game
+		trigger AutoPause
+		paused  bool
+		banner  string // part of the banner
+	}{
+		{"crash", falling, AutoPause{onCrash: true}, true, "crashed"},
+		{"crash without the trigger", falling, AutoPause{onEscape: true}, false, ""},
+		{"escape", escaping, AutoPause{onEscape: true}, true, "escaping star"},
+		{"escape without the trigger", escaping, AutoPause{onCrash: true}, false, ""},
+		{"bound orbit", func() *Game { return circularOrbitGame(testOrbitRadius) }, AutoPause{onCrash: true, onEscape: true}, false, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := test.create()
+			g.autoPause = test.trigger
+			for step := 0; step < 5 && !g.paused; step++ {
+				g.Step()
+			}
+			if g.paused != test.paused || !strings.Contains(g.autoPause.banner, test.banner) {
+				t.Errorf("paused %v with the banner %q, want paused %v with a banner containing %q", g.paused, g.autoPause.banner, test.paused, test.banner)
+			}
+		})
+	}
+}
+
+func TestResumingAnEscapeDoesNotPauseAgain(t *testing.T) {
+	g := circularOrbitGame(testOrbitRadius)
+	g.spaceObjects[1].velocity = Vector{0, 2 * g.config.EscapeVelocity(testStarMass, testOrbitRadius)}
+	g.autoPause.onEscape = true
+	g.Step()
+	if !g.paused {
+		t.Fatal("the escape did not pause")
+	}
+
+	g.paused = false
+	for range 5 {
+		g.Step()
+	}
+	if g.paused {
+		t.Error("paused again on the same escape")
+	}
+}
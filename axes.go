@@ -0,0 +1,45 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const axisFontSize float64 = 12 // font size of the axis labels
+
+var axisColor = color.RGBA{255, 255, 255, 60}
+
+// returns the screen endpoints of the x and y axis through the world origin, spanning the viewport
+// the axes are straight lines in world space, so only their position along the other axis depends on the camera
+func (g *Game) axisEndpoints() (xFrom, xTo, yFrom, yTo Vector) {
+	viewport := g.viewport()
+	origin := g.worldToScreen(Vector{})
+	xFrom = Vector{float64(viewport.Min.X), origin.Y}
+	xTo = Vector{float64(viewport.Max.X), origin.Y}
+	yFrom = Vector{origin.X, float64(viewport.Min.Y)}
+	yTo = Vector{origin.X, float64(viewport.Max.Y)}
+	return xFrom, xTo, yFrom, yTo
+}
+
+// draws faint x and y axes through the world origin, labeled at their positive ends
+// screen y points the same way as world y, so the positive y axis is at the bottom
+func (g *Game) drawAxes(screen *ebiten.Image) {
+	xFrom, xTo, yFrom, yTo := g.axisEndpoints()
+	vector.StrokeLine(screen, float32(xFrom.X), float32(xFrom.Y), float32(xTo.X), float32(xTo.Y), 1, axisColor, false)
+	vector.StrokeLine(screen, float32(yFrom.X), float32(yFrom.Y), float32(yTo.X), float32(yTo.Y), 1, axisColor, false)
+
+	face := &text.GoTextFace{Source: mplusFaceSource, Size: axisFontSize}
+	label := func(s string, x, y float64, align text.Align) {
+		op := &text.DrawOptions{}
+		op.GeoM.Translate(x, y)
+		op.PrimaryAlign = align
+		op.ColorScale.ScaleWithColor(axisColor)
+		text.Draw(screen, s, face, op)
+	}
+	label("x", xTo.X-4, xTo.Y+2, text.AlignEnd)
+	label("y", yTo.X+4, yTo.Y-axisFontSize-4, text.AlignStart)
+	label("0", yFrom.X+4, xFrom.Y+2, text.AlignStart)
+}
@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestAxisEndpoints(t *testing.T) {
+	// at zoom 1 a pixel is 1e7 m, the center of the 800x600 screen shows the camera position
+	tests := []struct {
+		name                   string
+		offset                 Vector
+		zoom                   float64
+		aspectRatio            float64
+		xFrom, xTo, yFrom, yTo Vector
+	}{
+		{"origin in the center", Vector{}, 1, 0, Vector{0, 300}, Vector{800, 300}, Vector{400, 0}, Vector{400, 600}},
+		{"panned and zoomed", Vector{5e8, -1e8}, 2, 0, Vector{0, 320}, Vector{800, 320}, Vector{300, 0}, Vector{300, 600}},
+		{"origin off the screen", Vector{5e9, 0}, 1, 0, Vector{0, 300}, Vector{800, 300}, Vector{-100, 0}, Vector{-100, 600}},
+		{"letterboxed", Vector{}, 1, 1, Vector{100, 300}, Vector{700, 300}, Vector{400, 0}, Vector{400, 600}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := newGame()
+			g.screenWidth, g.screenHeight = 800, 600
+			g.camera.offset, g.camera.zoom, g.aspectRatio = test.offset, test.zoom, test.aspectRatio
+			xFrom, xTo, yFrom, yTo := g.axisEndpoints()
+			if xFrom != test.xFrom || xTo != test.xTo || yFrom != test.yFrom || yTo != test.yTo {
+				t.Errorf("x axis runs from %v to %v and y axis from %v to %v, want %v to %v and %v to %v",
+					xFrom, xTo, yFrom, yTo, test.xFrom, test.xTo, test.yFrom, test.yTo)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"os"
+	"strconv"
+)
+
+// columns a body file has to start with, in this order
+var bodiesCSVHeader = []string{"name", "mass", "x", "y", "vx", "vy"}
+
+// colors the bodies of a body file are drawn in, one after another
+var bodyColors = []color.Color{
+	color.RGBA{0, 0, 255, 255},
+	color.RGBA{255, 200, 0, 255},
+	color.RGBA{200, 80, 40, 255},
+	color.RGBA{120, 200, 120, 255},
+	color.RGBA{200, 200, 200, 255},
+}
+
+// returns the radius of a sphere of the given mass with the density of earth (5514 kg/m^3)
+func radiusFromMass(mass float64) float64 {
+	return math.Cbrt(3 * mass / (4 * math.Pi * 5514))
+}
+
+// reads spaceobjects from a csv file with the columns name, mass (kg), x, y (m) and vx, vy (m/s)
+// the first line is the header, at least one body must follow; the radius is derived from the mass assuming the density of earth
+func LoadBodiesCSV(path string) ([]*SpaceObject, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	r := csv.NewReader(file)
+	r.FieldsPerRecord = len(bodiesCSVHeader)
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%s: reading header: %w", path, err)
+	}
+	for i, column := range bodiesCSVHeader {
+		if header[i] != column {
+			return nil, fmt.Errorf("%s:1: column %d is %q, expected %q", path, i+1, header[i], column)
+		}
+	}
+
+	var spaceObjects []*SpaceObject
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// csv errors already carry the line number
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		line, _ := r.FieldPos(0)
+
+		values := make([]float64, len(record)-1)
+		for i, field := range record[1:] {
+			value, err := strconv.ParseFloat(field, 64)
+			if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+				return nil, fmt.Errorf("%s:%d: %s is not a number: %q", path, line, bodiesCSVHeader[i+1], field)
+			}
+			values[i] = value
+		}
+
+		mass := values[0]
+		if mass <= 0 {
+			return nil, fmt.Errorf("%s:%d: mass of %s must be positive, got %g", path, line, record[0], mass)
+		}
+
+		c := bodyColors[len(spaceObjects)%len(bodyColors)]
+		spaceObjects = append(spaceObjects, &SpaceObject{
+			name:     record[0],
+			mass:     mass,
+			radius:   radiusFromMass(mass),
+			position: Vector{values[1], values[2]},
+			velocity: Vector{values[3], values[4]},
+			img:      createEmptyColoredImage(2, 2, c),
+			color:    c,
+		})
+	}
+	if len(spaceObjects) == 0 {
+		return nil, fmt.Errorf("%s: no bodies after the header", path)
+	}
+	return spaceObjects, nil
+}
+
+// returns a scene with the bodies of the given csv file
+// the file is read once, every game created from the scene starts with fresh copies of the bodies
+func newBodiesScene(path string) (Scene, error) {
+	spaceObjects, err := LoadBodiesCSV(path)
+	if err != nil {
+		return Scene{}, err
+	}
+
+	create := func() *Game {
+		game := newGame()
+		for _, so := range spaceObjects {
+			copied := *so
+			game.spaceObjects = append(game.spaceObjects, &copied)
+		}
+		return game
+	}
+	return Scene{name: "csv", description: path, create: create}, nil
+}
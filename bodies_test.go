@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writes the given content to a csv file in a temporary directory and returns its path
+func writeBodiesCSV(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "bodies.csv")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadBodiesCSV(t *testing.T) {
+	path := writeBodiesCSV(t, "name,mass,x,y,vx,vy\nSun,2e30,0,0,0,0\nEarth,5.97e24,1.5e11,0,0,29780\n")
+	spaceObjects, err := LoadBodiesCSV(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spaceObjects) != 2 {
+		t.Fatalf("got %d bodies, want 2", len(spaceObjects))
+	}
+	earth := spaceObjects[1]
+	if earth.name != "Earth" || earth.mass != 5.97e24 || earth.position != (Vector{1.5e11, 0}) || earth.velocity != (Vector{0, 29780}) {
+		t.Errorf("second body is %q %v kg at %v moving %v", earth.name, earth.mass, earth.position, earth.velocity)
+	}
+	if earth.radius != radiusFromMass(earth.mass) {
+		t.Errorf("radius is %v m, want the one derived from the mass %v", earth.radius, radiusFromMass(earth.mass))
+	}
+}
+
+func TestLoadBodiesCSVRejectsBadFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string // part of the error
+	}{
+		{"empty", "", "reading header"},
+		{"header only", "name,mass,x,y,vx,vy\n", "no bodies"},
+		{"wrong header", "name,mass,x,y,vy,vx\nSun,2e30,0,0,0,0\n", `expected "vx"`},
+		{"missing column", "name,mass,x,y,vx,vy\nSun,2e30,0,0,0\n", "wrong number of fields"},
+		{"not a number", "name,mass,x,y,vx,vy\nSun,2e30,0,zero,0,0\n", ":2: y is not a number"},
+		{"negative mass", "name,mass,x,y,vx,vy\nSun,-1,0,0,0,0\n", "must be positive"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := LoadBodiesCSV(writeBodiesCSV(t, test.content))
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("LoadBodiesCSV() = %v, want an error containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import "math"
+
+const (
+	zoomToFitMargin float64 = 0.1 // part of the viewport left empty around the bodies when zooming to fit
+)
+
+// Camera determines which part of the world is shown
+// the offset is relative to the focus frame, so the focused object stays centered at offset 0
+type Camera struct {
+	offset     Vector  // world position shown in the center of the viewport in m
+	zoom       float64 // magnification on top of XScale and YScale
+	zoomFactor float64 // factor the zoom is multiplied or divided by per mouse wheel notch
+	minZoom    float64 // smallest zoom the mouse wheel can reach
+	maxZoom    float64 // largest zoom the mouse wheel can reach
+
+	// fraction of the way to the focus the camera moves per frame, 1 snaps to it
+	// lower values let the camera lag behind and glide, which hides jitter of fast objects
+	smoothing float64
+	target    Vector // eased position of the focus frame the camera follows in m
+	targetSet bool   // false until the camera followed the focus once
+}
+
+// returns a camera centered on the focus frame without magnification
+func NewCamera() Camera {
+	return Camera{
+		zoom:       1,
+		zoomFactor: 1.25,
+		minZoom:    1e-3,
+		maxZoom:    1e6,
+		smoothing:  1,
+	}
+}
+
+// zooms in (positive notches) or out (negative notches) by zoomFactor per notch
+// the steps are geometric, so every notch changes the visible scale by the same ratio
+// returns true if the zoom changed
+func (c *Camera) zoomBy(notches float64) bool {
+	if notches == 0 {
+		return false
+	}
+	zoom := c.zoom * math.Pow(c.zoomFactor, notches)
+	zoom = math.Max(c.minZoom, math.Min(c.maxZoom, zoom))
+	changed := zoom != c.zoom
+	c.zoom = zoom
+	return changed
+}
+
+// eases the camera towards the given focus position by the smoothing factor
+func (c *Camera) follow(focus Vector) {
+	if !c.targetSet || c.smoothing >= 1 {
+		c.jumpTo(focus)
+		return
+	}
+	c.target = c.target.Lerp(focus, c.smoothing)
+}
+
+// moves the camera straight to the given focus position, without easing
+func (c *Camera) jumpTo(focus Vector) {
+	c.target = focus
+	c.targetSet = true
+}
+
+// returns the position of the focus frame the camera currently shows
+func (g *Game) cameraFocus() Vector {
+	if !g.camera.targetSet {
+		return g.focusPosition()
+	}
+	return g.camera.target
+}
+
+// returns the center of the viewport in pixel
+func (g *Game) viewportCenter() Vector {
+	viewport := g.viewport()
+	return Vector{float64(viewport.Min.X+viewport.Max.X) / 2, float64(viewport.Min.Y+viewport.Max.Y) / 2}
+}
+
+// converts a world position in m to a screen position in pixel
+func (g *Game) worldToScreen(p Vector) Vector {
+	focus := g.cameraFocus()
+	center := g.viewportCenter()
+	return p.Translate(-focus.X-g.camera.offset.X, -focus.Y-g.camera.offset.Y).
+		Scale(XScale*g.camera.zoom, YScale*g.camera.zoom).
+		Translate(center.X, center.Y)
+}
+
+// converts a world length in m to the length in pixel it is drawn with at the current zoom
+func (g *Game) worldLengthToScreen(length float64) float64 {
+	return length * XScale * g.camera.zoom
+}
+
+// converts a screen position in pixel to a world position in m, the inverse of worldToScreen
+func (g *Game) screenToWorld(p Vector) Vector {
+	focus := g.cameraFocus()
+	center := g.viewportCenter()
+	return p.Translate(-center.X, -center.Y).
+		Scale(1/(XScale*g.camera.zoom), 1/(YScale*g.camera.zoom)).
+		Translate(focus.X+g.camera.offset.X, focus.Y+g.camera.offset.Y)
+}
+
+// moves and zooms the camera so the bounding box of all spaceobjects fits into the viewport
+func (g *Game) zoomToFit() {
+	if len(g.spaceObjects) == 0 {
+		return
+	}
+
+	// bounding box of all positions relative to the focus frame
+	focus := g.cameraFocus()
+	min := Vector{math.Inf(1), math.Inf(1)}
+	max := Vector{math.Inf(-1), math.Inf(-1)}
+	for _, so := range g.spaceObjects {
+		p := so.position.Translate(-focus.X, -focus.Y)
+		min = Vector{math.Min(min.X, p.X), math.Min(min.Y, p.Y)}
+		max = Vector{math.Max(max.X, p.X), math.Max(max.Y, p.Y)}
+	}
+
+	g.camera.offset = Vector{(min.X + max.X) / 2, (min.Y + max.Y) / 2}
+
+	// worldToScreen maps a world width w to w * XScale * zoom pixel,
+	// so the zoom that maps the box width to the viewport width is viewport / (w * XScale)
+	viewport := g.viewport()
+	zoomX := float64(viewport.Dx()) * (1 - zoomToFitMargin) / ((max.X - min.X) * XScale)
+	zoomY := float64(viewport.Dy()) * (1 - zoomToFitMargin) / ((max.Y - min.Y) * YScale)
+
+	// a box without width or height gives an infinite zoom for that axis, only the other one limits it
+	// if all bodies are at the same point there is nothing to fit, only the offset changes
+	zoom := math.Min(zoomX, zoomY)
+	if !math.IsInf(zoom, 0) && zoom > 0 {
+		g.camera.zoom = zoom
+	}
+
+	// the paths were drawn with the old camera and no longer match
+	g.clearPaths()
+}
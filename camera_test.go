@@ -0,0 +1,113 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestZoomNotchesAreGeometric(t *testing.T) {
+	c := NewCamera()
+	c.zoom = 3
+	for range 7 {
+		c.zoomBy(1)
+	}
+	if want := 3 * math.Pow(c.zoomFactor, 7); math.Abs(c.zoom-want) > 1e-12*want {
+		t.Errorf("zoom after 7 notches in is %v, want %v", c.zoom, want)
+	}
+	for range 7 {
+		c.zoomBy(-1)
+	}
+	if math.Abs(c.zoom-3) > 1e-12 {
+		t.Errorf("zoom after 7 notches in and out is %v, want 3", c.zoom)
+	}
+}
+
+func TestZoomIsClamped(t *testing.T) {
+	c := NewCamera()
+	c.minZoom, c.maxZoom = 0.5, 4
+	for range 20 {
+		c.zoomBy(1)
+	}
+	if c.zoom != c.maxZoom {
+		t.Errorf("zoom is %v, want the maximum %v", c.zoom, c.maxZoom)
+	}
+	if c.zoomBy(1) {
+		t.Error("zooming in at the maximum reported a change")
+	}
+	for range 20 {
+		c.zoomBy(-1)
+	}
+	if c.zoom != c.minZoom {
+		t.Errorf("zoom is %v, want the minimum %v", c.zoom, c.minZoom)
+	}
+	if c.zoomBy(0) {
+		t.Error("zero notches reported a change")
+	}
+}
+
+func TestFollowEasesTowardsTheFocus(t *testing.T) {
+	c := NewCamera()
+	c.follow(Vector{0, 0})
+
+	// with a smoothing of 0.5 the remaining distance halves every frame
+	c.smoothing = 0.5
+	focus := Vector{1024, -512}
+	previous := math.Inf(1)
+	for frame := 1; frame <= 10; frame++ {
+		c.follow(focus)
+		remaining := math.Sqrt(c.target.DistanceSquared(focus))
+		want := math.Sqrt(focus.Dot(focus)) * math.Pow(0.5, float64(frame))
+		if math.Abs(remaining-want) > 1e-9 {
+			t.Errorf("frame %d: %v from the focus, want %v", frame, remaining, want)
+		}
+		if remaining >= previous {
+			t.Errorf("frame %d: the camera did not get closer", frame)
+		}
+		previous = remaining
+	}
+
+	// a smoothing of 1 snaps to the focus
+	c.smoothing = 1
+	c.follow(Vector{5, 5})
+	if c.target != (Vector{5, 5}) {
+		t.Errorf("camera is at %v, want snapped to the focus", c.target)
+	}
+
+	// the first follow always snaps, there is nothing to ease from
+	c = NewCamera()
+	c.smoothing = 0.1
+	c.follow(focus)
+	if c.target != focus {
+		t.Errorf("first follow went to %v, want %v", c.target, focus)
+	}
+}
+
+func TestRingRadiusOnScreen(t *testing.T) {
+	// at zoom 1 a pixel is 1e7 m
+	tests := []struct {
+		radius, zoom float64
+		want         float64 // pixel
+	}{
+		{1e9, 1, 100},
+		{1e9, 2, 200},
+		{3e9, 0.5, 150},
+		{defaultRingSpacing, 1e-3, 0.1},
+	}
+	for _, test := range tests {
+		g := newGame()
+		g.screenWidth, g.screenHeight = 800, 600
+		g.camera.zoom = test.zoom
+		g.camera.offset = Vector{4e9, -1e9}
+		got := g.worldLengthToScreen(test.radius)
+		if math.Abs(got-test.want) > 1e-9*test.want {
+			t.Errorf("ring of %v m at zoom %v is %v pixel, want %v", test.radius, test.zoom, got, test.want)
+		}
+
+		// the ring goes through the points that far from its center, wherever the camera is
+		center := Vector{2e9, 5e8}
+		edge := g.worldToScreen(center.Translate(test.radius, 0)).X - g.worldToScreen(center).X
+		if math.Abs(edge-got) > 1e-9*got {
+			t.Errorf("ring of %v m at zoom %v is %v pixel, but its edge is %v pixel from its center", test.radius, test.zoom, got, edge)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package main
+
+import "time"
+
+// measures the real time the simulation has been running, without the time it was paused
+type realClock struct {
+	elapsed time.Duration // real time spent running
+	last    time.Time     // real time of the previous frame, zero before the first one
+}
+
+// advances the clock by the real time since the previous frame, unless the simulation is paused
+// the frame after a pause only counts its own delta, so resuming does not add the paused time
+func (c *realClock) tick(now time.Time, paused bool) {
+	if !paused && !c.last.IsZero() {
+		c.elapsed += now.Sub(c.last)
+	}
+	c.last = now
+}
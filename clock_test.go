@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRealClockStopsWhilePaused(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	frames := []struct {
+		at     time.Duration // real time of the frame since the start
+		paused bool
+		want   time.Duration // elapsed time after the frame
+	}{
+		// the first frame has no previous one to measure from
+		{0, false, 0},
+		{time.Second, false, time.Second},
+		{2 * time.Second, false, 2 * time.Second},
+		// paused frames do not count, however long the pause is
+		{3 * time.Second, true, 2 * time.Second},
+		{60 * time.Second, true, 2 * time.Second},
+		// after resuming the clock runs again, counting only from the last paused frame
+		{61 * time.Second, false, 3 * time.Second},
+		{63 * time.Second, false, 5 * time.Second},
+	}
+	var c realClock
+	for i, frame := range frames {
+		c.tick(start.Add(frame.at), frame.paused)
+		if c.elapsed != frame.want {
+			t.Errorf("frame %d at %v (paused %v): elapsed %v, want %v", i, frame.at, frame.paused, c.elapsed, frame.want)
+		}
+	}
+}
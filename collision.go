@@ -0,0 +1,46 @@
+package main
+
+import "math"
+
+// returns the distance between the point p and the line segment from a to b
+func pointSegmentDistance(p, a, b Vector) float64 {
+	ab := Vector{b.X - a.X, b.Y - a.Y}
+	ap := Vector{p.X - a.X, p.Y - a.Y}
+
+	// project p onto the segment and clamp to its ends
+	t := 0.0
+	if lengthSquared := ab.Dot(ab); lengthSquared > 0 {
+		t = math.Max(0, math.Min(1, ap.Dot(ab)/lengthSquared))
+	}
+	return math.Sqrt(p.DistanceSquared(a.Lerp(b, t)))
+}
+
+// returns the fraction t in [0, 1] of the way from a to b at which the segment first enters
+// the circle with the given radius around the origin, false if it never gets that close
+func segmentEntry(a, b Vector, radius float64) (float64, bool) {
+	if pointSegmentDistance(Vector{0, 0}, a, b) >= radius {
+		return 0, false
+	}
+	if a.Dot(a) < radius*radius {
+		// already inside at the start
+		return 0, true
+	}
+
+	// |a + t*(b-a)|^2 = radius^2 is a quadratic equation in t, the smaller root is the entry
+	d := Vector{b.X - a.X, b.Y - a.Y}
+	qa := d.Dot(d)
+	qb := 2 * a.Dot(d)
+	qc := a.Dot(a) - radius*radius
+	t := (-qb - math.Sqrt(qb*qb-4*qa*qc)) / (2 * qa)
+	return math.Max(0, math.Min(1, t)), true
+}
+
+// checks whether the two objects touched at any time during the last step, not only at its end
+// a fast object can jump clean through another one within a single step otherwise
+// the motion during the step is treated as a straight line, so relative to so2 so1 moves along a segment
+// returns the fraction of the step at which the objects first touched
+func sweptCollision(so1, so2 *SpaceObject) (float64, bool) {
+	from := Vector{so1.previousPosition.X - so2.previousPosition.X, so1.previousPosition.Y - so2.previousPosition.Y}
+	to := Vector{so1.position.X - so2.position.X, so1.position.Y - so2.position.Y}
+	return segmentEntry(from, to, so1.radius+so2.radius)
+}
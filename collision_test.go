@@ -0,0 +1,48 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSegmentEntry(t *testing.T) {
+	tests := []struct {
+		name   string
+		a, b   Vector
+		hit    bool
+		wantAt float64
+	}{
+		{"through the middle", Vector{-10, 0}, Vector{10, 0}, true, 0.4},
+		{"stops short", Vector{-10, 0}, Vector{-5, 0}, false, 0},
+		{"passes beside", Vector{-10, 3}, Vector{10, 3}, false, 0},
+		{"starts inside", Vector{1, 0}, Vector{10, 0}, true, 0},
+		{"ends inside", Vector{0, -10}, Vector{0, 0}, true, 0.8},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			at, hit := segmentEntry(test.a, test.b, 2)
+			if hit != test.hit || math.Abs(at-test.wantAt) > 1e-12 {
+				t.Errorf("segmentEntry(%v, %v, 2) = %v, %v, want %v, %v", test.a, test.b, at, hit, test.wantAt, test.hit)
+			}
+		})
+	}
+}
+
+func TestFastSpacecraftCannotTunnelThroughAPlanet(t *testing.T) {
+	// in one step the spacecraft jumps from 1e9 m on one side of the planet to 1e9 m on the other
+	g := newGame()
+	planet := &SpaceObject{name: "planet", mass: 6e24, radius: 6.4e6}
+	craft := &SpaceObject{name: "craft", mass: 1, radius: 1, isSpacecraft: true, position: Vector{-1e9, 0}, velocity: Vector{2e9 / dt, 0}}
+	g.spaceObjects = []*SpaceObject{planet, craft}
+
+	// the end points of the step are far outside the planet, only the swept test sees the collision
+	g.Step()
+	if len(g.spaceObjects) != 1 {
+		t.Fatalf("%d spaceobjects after the step, the spacecraft should have merged with the planet", len(g.spaceObjects))
+	}
+
+	// the merge happens where the spacecraft entered the planet, not beyond it
+	if x := g.spaceObjects[0].position.X; math.Abs(x) > planet.radius {
+		t.Errorf("merged at x = %v m, want within the planet", x)
+	}
+}
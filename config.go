@@ -0,0 +1,103 @@
+package main
+
+import (
+	"math"
+	"runtime"
+)
+
+// gScale of the game mode: 100 times stronger gravity makes every orbit of a scene 10 times faster
+const gameGScale float64 = 100
+
+// SimConfig holds the tunable parameters of the simulation
+type SimConfig struct {
+	forceExponent float64 // exponent of the distance in the gravity law, 2 for newtonian gravity
+
+	// multiplier of the gravitational constant, 1 is realistic and gameGScale the arcade preset
+	// see Game.setGScale for keeping the orbits of a scene when it changes
+	gScale float64
+
+	// bodies farther apart than this distance in m do not attract each other, infinite to disable
+	// This is physically wrong: the force of a distant body is small but never zero, and cutting it off
+	// makes the force jump to zero at the cutoff, so the total energy is no longer conserved when bodies cross it.
+	// It only saves time in large systems where many far bodies contribute almost nothing.
+	cutoffDistance float64
+
+	// only look for bodies within the cutoff in the cells of a grid around each body instead of checking all pairs
+	// the forces are the same as with the cutoff alone, so the accuracy only depends on the cutoff distance
+	spatialHash bool
+
+	integrator Integrator // method the positions and velocities are advanced with
+
+	float32Forces bool // compute the pairwise forces in float32, see calculateGravitationalForce32
+
+	// the spacecraft pulls on the other bodies like any other body, disabled it is a massless test particle
+	// that only feels gravity itself; the total momentum is then no longer conserved
+	spacecraftGravitates bool
+
+	// frozen objects keep their position and velocity but still pull on the others
+	freezePlanets    bool
+	freezeSpacecraft bool
+
+	// surface density of the atmosphere of every planet in kg/m^3, 0 disables drag; see applyDrag
+	atmosphereDensity     float64
+	atmosphereScaleHeight float64 // height in m over which the density drops by a factor of e
+
+	numWorkers int // goroutines the forces are computed with, see netForces
+
+	substeps int // number of smaller steps every step of dt is split into, finer near close encounters at more cost
+}
+
+// returns the configuration of the real world
+func DefaultSimConfig() SimConfig {
+	return SimConfig{
+		forceExponent:         2.0,
+		gScale:                1,
+		cutoffDistance:        math.Inf(1),
+		spacecraftGravitates:  true,
+		substeps:              1,
+		numWorkers:            runtime.GOMAXPROCS(0),
+		atmosphereScaleHeight: defaultScaleHeight,
+	}
+}
+
+// returns the gravitational constant scaled by gScale
+func (c SimConfig) gravitationalConstant() float64 {
+	return gravitation * c.gScale
+}
+
+// returns distance^forceExponent, the denominator of the gravity law
+func (c SimConfig) distancePower(distance float64) float64 {
+	// avoid math.Pow for the common inverse-square case
+	if c.forceExponent == 2.0 {
+		return distance * distance
+	}
+	return math.Pow(distance, c.forceExponent)
+}
+
+// returns the potential energy of two masses at the given distance for the configured gravity law
+// the potential is the integral of the force: -G*m1*m2 / ((n-1) * r^(n-1)), or G*m1*m2*ln(r) for n = 1
+func (c SimConfig) potentialEnergy(mass1, mass2, distance float64) float64 {
+	if c.forceExponent == 1.0 {
+		return c.gravitationalConstant() * mass1 * mass2 * math.Log(distance)
+	}
+	n := c.forceExponent
+	return -c.gravitationalConstant() * mass1 * mass2 / ((n - 1) * math.Pow(distance, n-1))
+}
+
+// returns true if two bodies with the given squared distance attract each other
+func (c SimConfig) withinCutoff(distanceSquared float64) bool {
+	return distanceSquared <= c.cutoffDistance*c.cutoffDistance
+}
+
+// returns true if the given object is not moved by the integration
+func (c SimConfig) frozen(so *SpaceObject) bool {
+	if so.isSpacecraft {
+		return c.freezeSpacecraft
+	}
+	return c.freezePlanets
+}
+
+// returns true if so2 pulls on so1
+func (c SimConfig) attracts(so1, so2 *SpaceObject) bool {
+	return c.spacecraftGravitates || !so2.isSpacecraft
+}
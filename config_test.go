@@ -0,0 +1,42 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGScaleScalesTheDerivedQuantities(t *testing.T) {
+	realistic := DefaultSimConfig()
+	game := DefaultSimConfig()
+	game.gScale = gameGScale
+
+	factor := math.Sqrt(gameGScale)
+	near := func(got, want float64) bool { return math.Abs(got/want-1) < 1e-12 }
+
+	if got, want := game.CircularOrbitVelocity(testStarMass, testOrbitRadius), factor*realistic.CircularOrbitVelocity(testStarMass, testOrbitRadius); !near(got, want) {
+		t.Errorf("circular velocity is %v m/s, want sqrt(gScale) times the realistic one, %v", got, want)
+	}
+	if got, want := game.EscapeVelocity(testStarMass, testOrbitRadius), factor*realistic.EscapeVelocity(testStarMass, testOrbitRadius); !near(got, want) {
+		t.Errorf("escape velocity is %v m/s, want %v", got, want)
+	}
+	if got, want := game.OrbitalPeriod(testStarMass, testOrbitRadius), realistic.OrbitalPeriod(testStarMass, testOrbitRadius)/factor; !near(got, want) {
+		t.Errorf("orbital period is %v s, want the realistic one divided by sqrt(gScale), %v", got, want)
+	}
+}
+
+func TestSetGScaleKeepsTheOrbits(t *testing.T) {
+	// the same orbit is traversed ten times faster with 100 times stronger gravity
+	slow := circularOrbitGame(testOrbitRadius)
+	slow.config.integrator = integratorRK4
+	fast := circularOrbitGame(testOrbitRadius)
+	fast.config.integrator = integratorRK4
+	fast.setGScale(100)
+
+	for range 10 {
+		slow.Step()
+	}
+	fast.Step()
+	if d := math.Sqrt(slow.spaceObjects[1].position.DistanceSquared(fast.spaceObjects[1].position)); d > 1e-3*testOrbitRadius {
+		t.Errorf("one fast step ends %v m from ten slow steps", d)
+	}
+}
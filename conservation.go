@@ -0,0 +1,135 @@
+package main
+
+import (
+	"log"
+	"math"
+)
+
+const (
+	momentumTolerance float64 = 1e-9 // allowed relative drift of the total momentum
+	energyTolerance   float64 = 1e-3 // allowed relative drift of the total energy
+)
+
+// holds the conserved quantities at the start of the simulation
+type conservationBaseline struct {
+	momentum Vector
+	energy   float64
+	set      bool
+	warned   bool
+}
+
+// returns the position and velocity of the center of mass of all spaceobjects
+func (g *Game) Barycenter() (position, velocity Vector) {
+	totalMass := 0.0
+	for _, so := range g.spaceObjects {
+		totalMass += so.mass
+		position = position.Translate(so.position.X*so.mass, so.position.Y*so.mass)
+		velocity = velocity.Translate(so.velocity.X*so.mass, so.velocity.Y*so.mass)
+	}
+	if totalMass == 0 {
+		return Vector{0, 0}, Vector{0, 0}
+	}
+	return position.Scale(1/totalMass, 1/totalMass), velocity.Scale(1/totalMass, 1/totalMass)
+}
+
+// subtracts the velocity of the barycenter from every spaceobject, so the center of mass stays where it is
+// and the total momentum is zero; the motion of the bodies relative to each other does not change
+func (g *Game) zeroNetMomentum() {
+	_, velocity := g.Barycenter()
+	for _, so := range g.spaceObjects {
+		so.velocity = so.velocity.Translate(-velocity.X, -velocity.Y)
+	}
+	g.baseline.set = false
+}
+
+// returns the total linear momentum p = sum(m*v) of all spaceobjects
+func (g *Game) TotalMomentum() Vector {
+	momentum := Vector{0, 0}
+	for _, so := range g.spaceObjects {
+		momentum = momentum.Translate(so.velocity.X*so.mass, so.velocity.Y*so.mass)
+	}
+	return momentum
+}
+
+// returns the kinetic energy of all spaceobjects, the potential energy of all pairs of them
+// (plus the energy stored in springs) and their sum
+func (g *Game) SystemEnergy() (kinetic, potential, total float64) {
+	for i, so1 := range g.spaceObjects {
+		// kinetic energy: E = 1/2 * m * v^2
+		speed := so1.velocity.Length()
+		kinetic += 0.5 * so1.mass * speed * speed
+
+		// potential energy of every pair (counted once): E = -G*m1*m2/r for newtonian gravity
+		for _, so2 := range g.spaceObjects[i+1:] {
+			distance := math.Sqrt(so1.position.DistanceSquared(so2.position))
+			potential += g.config.potentialEnergy(so1.mass, so2.mass, distance)
+		}
+	}
+
+	// springs store energy when they are stretched or compressed
+	for _, s := range g.springs {
+		potential += s.potentialEnergy(g.spaceObjects)
+	}
+	return kinetic, potential, kinetic + potential
+}
+
+// returns the total energy (kinetic + potential) of all spaceobjects and springs
+func (g *Game) TotalEnergy() float64 {
+	_, _, total := g.SystemEnergy()
+	return total
+}
+
+// returns the relative change of the total energy since the start of the simulation
+func (g *Game) energyDrift() float64 {
+	if g.initialEnergy == 0 {
+		return 0
+	}
+	return (g.TotalEnergy() - g.initialEnergy) / math.Abs(g.initialEnergy)
+}
+
+// returns the sum of the momentum magnitudes of all spaceobjects
+// the total momentum is often close to zero, so its drift is measured relative to this
+func (g *Game) momentumScale() float64 {
+	scale := 0.0
+	for _, so := range g.spaceObjects {
+		scale += so.mass * so.velocity.Length()
+	}
+	return scale
+}
+
+// remembers the current momentum and energy as the values that should be conserved
+func (g *Game) resetConservationBaseline() {
+	g.baseline = conservationBaseline{
+		momentum: g.TotalMomentum(),
+		energy:   g.TotalEnergy(),
+		set:      true,
+	}
+}
+
+// compares the current momentum and energy against the baseline and logs a warning once they drift too far
+func (g *Game) checkConservation() {
+	if !g.baseline.set {
+		g.resetConservationBaseline()
+		return
+	}
+
+	momentum := g.TotalMomentum()
+	momentumDrift := 0.0
+	if scale := g.momentumScale(); scale > 0 {
+		momentumDrift = math.Sqrt(momentum.DistanceSquared(g.baseline.momentum)) / scale
+	}
+
+	energy := g.TotalEnergy()
+	energyDrift := 0.0
+	if g.baseline.energy != 0 {
+		energyDrift = math.Abs((energy - g.baseline.energy) / g.baseline.energy)
+	}
+
+	drifted := momentumDrift > momentumTolerance || energyDrift > energyTolerance
+
+	// only warn when the drift first exceeds the tolerance, not every step
+	if drifted && !g.baseline.warned {
+		log.Printf("warning: conservation violated at t=%.0fs: momentum drift %.3e, energy drift %.3e\n", g.time, momentumDrift, energyDrift)
+	}
+	g.baseline.warned = drifted
+}
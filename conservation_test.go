@@ -0,0 +1,100 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestConservationWarning(t *testing.T) {
+	tests := []struct {
+		name  string
+		force func(g *Game) // force applied between the two checks
+		warns bool
+	}{
+		{"gravity", func(g *Game) { stepSpaceObjects(g.spaceObjects, nil, g.config, g.time, nil) }, false},
+		// a force without its reaction breaks newtons third law, so momentum and energy change
+		{"one-sided force", func(g *Game) { g.spaceObjects[1].UpdateVelocity(Vector{1e3, 0}, dt) }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logged := captureLog(t)
+			g := circularOrbitGame(testOrbitRadius)
+			g.checkConservation()
+			tt.force(g)
+			g.checkConservation()
+
+			warned := strings.Contains(logged.String(), "conservation violated")
+			if warned != tt.warns {
+				t.Errorf("warned %v, want %v; log: %q", warned, tt.warns, logged.String())
+			}
+		})
+	}
+}
+
+func TestSystemEnergy(t *testing.T) {
+	const (
+		planetMass = 6e24
+		speed      = 3e4
+	)
+	g := newGame()
+	g.spaceObjects = []*SpaceObject{
+		{name: "star", mass: testStarMass, radius: testStarRadius},
+		{name: "planet", mass: planetMass, radius: 1, position: Vector{0, testOrbitRadius}, velocity: Vector{speed, 0}},
+	}
+
+	kinetic, potential, total := g.SystemEnergy()
+	wantKinetic := 0.5 * planetMass * speed * speed
+	wantPotential := -g.config.gravitationalConstant() * testStarMass * planetMass / testOrbitRadius
+	if math.Abs(kinetic-wantKinetic) > 1e-12*wantKinetic {
+		t.Errorf("kinetic energy is %v J, want %v", kinetic, wantKinetic)
+	}
+	if math.Abs(potential-wantPotential) > 1e-12*math.Abs(wantPotential) {
+		t.Errorf("potential energy is %v J, want %v", potential, wantPotential)
+	}
+	if total != kinetic+potential {
+		t.Errorf("total energy %v J is not the sum of %v and %v", total, kinetic, potential)
+	}
+
+	// a circular orbit of a light body has half the potential energy as its total: -GMm/2r
+	g = circularOrbitGame(testOrbitRadius)
+	_, _, total = g.SystemEnergy()
+	if want := -g.config.gravitationalConstant() * testStarMass / (2 * testOrbitRadius); math.Abs(total-want) > 1e-9*math.Abs(want) {
+		t.Errorf("total energy of the circular orbit is %v J, want %v", total, want)
+	}
+}
+
+func TestZeroNetMomentum(t *testing.T) {
+	g := newGame()
+	g.spaceObjects = randomBodies(20, 1e12)
+	relative := g.spaceObjects[1].velocity.Translate(-g.spaceObjects[0].velocity.X, -g.spaceObjects[0].velocity.Y)
+	before := g.TotalMomentum()
+	if before.Length() == 0 {
+		t.Fatal("random bodies start without momentum")
+	}
+
+	g.zeroNetMomentum()
+	// every body had a share of the momentum, so it cancels only up to the rounding of the largest one
+	largest := 0.0
+	for _, so := range g.spaceObjects {
+		largest = math.Max(largest, so.mass*so.velocity.Length())
+	}
+	if momentum := g.TotalMomentum(); momentum.Length() > 1e-12*largest*float64(len(g.spaceObjects)) {
+		t.Errorf("total momentum is %v kg m/s, want about 0", momentum)
+	}
+
+	// the bodies still move the same relative to each other
+	after := g.spaceObjects[1].velocity.Translate(-g.spaceObjects[0].velocity.X, -g.spaceObjects[0].velocity.Y)
+	if math.Sqrt(after.DistanceSquared(relative)) > 1e-9*relative.Length() {
+		t.Errorf("relative velocity changed from %v to %v m/s", relative, after)
+	}
+
+	// the center of mass stays put over the following steps
+	start, _ := g.Barycenter()
+	for range 10 {
+		g.Step()
+	}
+	if end, _ := g.Barycenter(); math.Sqrt(end.DistanceSquared(start)) > 1e-6*1e12 {
+		t.Errorf("barycenter drifted from %v to %v m", start, end)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const cursorFontSize float64 = 12 // font size of the coordinates next to the cursor
+
+// draws the world coordinates under the cursor next to it in the configured units
+// nothing is drawn while the cursor is outside of the viewport
+func (g *Game) drawCursorCoordinates(screen *ebiten.Image) {
+	x, y := ebiten.CursorPosition()
+	str, ok := g.cursorCoordinates(x, y)
+	if !ok {
+		return
+	}
+
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(float64(x)+12, float64(y)+12)
+	text.Draw(screen, str, &text.GoTextFace{Source: mplusFaceSource, Size: cursorFontSize}, op)
+}
+
+// returns the world coordinates under the cursor at the given screen position in the configured units,
+// false if the position is outside of the viewport
+func (g *Game) cursorCoordinates(x, y int) (string, bool) {
+	if !image.Pt(x, y).In(g.viewport()) {
+		return "", false
+	}
+	p := g.screenToWorld(Vector{float64(x), float64(y)})
+	return "(" + g.units.FormatLength(p.X) + ", " + g.units.FormatLength(p.Y) + ")", true
+}
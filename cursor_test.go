@@ -0,0 +1,43 @@
+package main
+
+import "testing"
+
+func TestCursorCoordinates(t *testing.T) {
+	// at zoom 1 a pixel is 1e7 m, the center of the 800x600 screen shows the focus
+	tests := []struct {
+		name   string
+		setup  func(g *Game)
+		x, y   int
+		want   string
+		inside bool
+	}{
+		{"center", func(g *Game) {}, 400, 300, "(0 m, 0 m)", true},
+		{"right of the center", func(g *Game) {}, 500, 300, "(1e+09 m, 0 m)", true},
+		{"below the center", func(g *Game) {}, 400, 350, "(0 m, 5e+08 m)", true},
+		{"zoomed and panned", func(g *Game) {
+			g.camera.zoom = 2
+			g.camera.offset = Vector{5e8, 0}
+		}, 500, 300, "(1e+09 m, 0 m)", true},
+		{"focused body", func(g *Game) {
+			g.spaceObjects = []*SpaceObject{{name: "planet", mass: 1, position: Vector{2e9, 1e9}}}
+			g.focus = 0
+		}, 400, 300, "(2e+09 m, 1e+09 m)", true},
+		{"astronomical units", func(g *Game) {
+			g.units = unitSystems["astro"]
+			g.camera.zoom = 1e-4
+		}, 400 + 15, 300, "(10.03 AU, 0 AU)", true},
+		{"outside the window", func(g *Game) {}, -1, 300, "", false},
+		{"on the letterbox bar", func(g *Game) { g.aspectRatio = 1 }, 50, 300, "", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := newGame()
+			g.screenWidth, g.screenHeight = 800, 600
+			test.setup(g)
+			got, inside := g.cursorCoordinates(test.x, test.y)
+			if got != test.want || inside != test.inside {
+				t.Errorf("cursorCoordinates(%d, %d) = %q, %v, want %q, %v", test.x, test.y, got, inside, test.want, test.inside)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+)
+
+// separation of two runs of a scene over time, see runDivergence
+type divergenceResult struct {
+	Perturbation Vector    `json:"perturbation"` // offset of the spacecraft position of the second run in m
+	Separation   []float64 `json:"separation"`   // distance of the two spacecraft after every step in m
+	GrowthRate   float64   `json:"growthRate"`   // ln(final / initial separation) / time in 1/s, lyapunov-like
+}
+
+// runs the scene twice for the given number of steps, the second time with the spacecraft moved by epsilon m
+// in a direction from rng, and returns the distance between the two spacecraft after every step
+// the runs only differ in the perturbation, so the growth of the separation measures how chaotic the scene is
+func Divergence(create func() *Game, epsilon float64, steps int, rng *rand.Rand) (Vector, []float64, error) {
+	if epsilon <= 0 {
+		return Vector{}, nil, fmt.Errorf("perturbation must be positive, got %g", epsilon)
+	}
+	reference, perturbed := create(), create()
+	if err := reference.checkBodyCount(); err != nil {
+		return Vector{}, nil, err
+	}
+	a, b := reference.spacecraftIndex(), perturbed.spacecraftIndex()
+	if a < 0 || b < 0 {
+		return Vector{}, nil, fmt.Errorf("scene has no spacecraft to perturb")
+	}
+
+	perturbation := VectorFromPolar(epsilon, rng.Float64()*2*math.Pi)
+	craft := perturbed.spaceObjects[b]
+	craft.position = craft.position.Translate(perturbation.X, perturbation.Y)
+
+	separation := make([]float64, steps)
+	for step := range separation {
+		reference.Step()
+		perturbed.Step()
+		a, b = reference.spacecraftIndex(), perturbed.spacecraftIndex()
+		if a < 0 || b < 0 {
+			// a spacecraft merged with a body, from here on there is nothing to compare
+			return perturbation, separation[:step], nil
+		}
+		separation[step] = math.Sqrt(reference.spaceObjects[a].position.DistanceSquared(perturbed.spaceObjects[b].position))
+	}
+	return perturbation, separation, nil
+}
+
+// measures the divergence of two runs of the scene and writes the separations and their growth rate as json to w
+func runDivergence(create func() *Game, epsilon float64, steps int, rng *rand.Rand, w io.Writer) error {
+	perturbation, separation, err := Divergence(create, epsilon, steps, rng)
+	if err != nil {
+		return err
+	}
+
+	result := divergenceResult{Perturbation: perturbation, Separation: separation}
+	if n := len(separation); n > 0 && separation[n-1] > 0 {
+		result.GrowthRate = math.Log(separation[n-1]/epsilon) / (float64(n) * dt)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
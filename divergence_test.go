@@ -0,0 +1,45 @@
+package main
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestDivergenceOfAStableOrbitStaysBounded(t *testing.T) {
+	const (
+		epsilon = 1e3
+		steps   = 1460 // two orbits
+	)
+	create := func() *Game {
+		g := circularOrbitGame(testOrbitRadius)
+		g.config.integrator = integratorRK4
+		return g
+	}
+	perturbation, separation, err := Divergence(create, epsilon, steps, rand.New(rand.NewPCG(1, 2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(perturbation.Length()-epsilon) > 1e-9*epsilon {
+		t.Errorf("perturbation %v is %v m long, want %v", perturbation, perturbation.Length(), epsilon)
+	}
+	if len(separation) != steps {
+		t.Fatalf("got %d separations, want %d", len(separation), steps)
+	}
+
+	// a slightly different orbit only falls behind or ahead a little more every orbit instead of growing exponentially
+	if largest := slices.Max(separation); largest > 100*epsilon {
+		t.Errorf("separation grew to %v m from %v m", largest, epsilon)
+	}
+
+	// the same seed perturbs the same way
+	again, _, _ := Divergence(create, epsilon, 1, rand.New(rand.NewPCG(1, 2)))
+	if again != perturbation {
+		t.Errorf("same seed perturbed by %v, want %v", again, perturbation)
+	}
+
+	if _, _, err := Divergence(create, 0, steps, rand.New(rand.NewPCG(1, 2))); err == nil {
+		t.Error("a perturbation of 0 m was accepted")
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	defaultDominanceCell  int   = 16 // edge length of a cell of the dominance overlay in pixel
+	defaultDominanceAlpha uint8 = 48 // opacity of the dominance overlay
+)
+
+// dominanceOverlay tints every cell of a coarse screen grid in the color of the planet pulling strongest there
+// the tint is cached in an image and only redrawn when a planet moved on screen by more than half a cell,
+// or when a mass or the force law changed, which moves the boundaries without moving any planet
+type dominanceOverlay struct {
+	cell      int             // edge length of a cell in pixel
+	alpha     uint8           // opacity of the tint
+	img       *ebiten.Image   // cached tint of the whole viewport
+	viewport  image.Rectangle // viewport the cache was drawn for
+	positions []Vector        // screen positions of the planets the cache was drawn for
+	masses    []float64       // masses of the planets the cache was drawn for in kg
+	exponent  float64         // force exponent the cache was drawn for
+}
+
+// returns true if the cached tint no longer matches the planets on screen
+func (d *dominanceOverlay) stale(viewport image.Rectangle, positions []Vector, masses []float64, exponent float64) bool {
+	if d.img == nil || d.viewport != viewport || len(d.positions) != len(positions) || d.exponent != exponent {
+		return true
+	}
+	if !slices.Equal(d.masses, masses) {
+		return true
+	}
+	tolerance := float64(d.cell) / 2
+	for i, p := range positions {
+		if p.DistanceSquared(d.positions[i]) > tolerance*tolerance {
+			return true
+		}
+	}
+	return false
+}
+
+// returns the planet exerting the strongest gravitational force at the given world position, nil if there is none
+func (g *Game) dominantPlanetAt(p Vector) *SpaceObject {
+	var dominant *SpaceObject
+	strongest := 0.0
+	for _, so := range g.spaceObjects {
+		if so.isSpacecraft {
+			continue
+		}
+		distance := math.Sqrt(p.DistanceSquared(so.position))
+		if pull := so.mass / g.config.distancePower(distance); pull > strongest {
+			dominant = so
+			strongest = pull
+		}
+	}
+	return dominant
+}
+
+// redraws the cached tint for the current planet positions and masses
+func (g *Game) updateDominanceOverlay(viewport image.Rectangle, positions []Vector, masses []float64) {
+	d := &g.dominance
+	if d.img == nil || d.img.Bounds().Size() != viewport.Size() {
+		if d.img != nil {
+			d.img.Deallocate()
+		}
+		d.img = ebiten.NewImage(viewport.Dx(), viewport.Dy())
+	}
+	d.img.Clear()
+
+	cell := max(d.cell, 1)
+	for y := 0; y < viewport.Dy(); y += cell {
+		for x := 0; x < viewport.Dx(); x += cell {
+			center := Vector{float64(viewport.Min.X+x) + float64(cell)/2, float64(viewport.Min.Y+y) + float64(cell)/2}
+			so := g.dominantPlanetAt(g.screenToWorld(center))
+			if so == nil {
+				continue
+			}
+
+			// the image expects premultiplied alpha
+			r, gr, b, _ := so.color.RGBA()
+			a := uint32(d.alpha)
+			tint := color.RGBA{uint8(r >> 8 * a / 255), uint8(gr >> 8 * a / 255), uint8(b >> 8 * a / 255), d.alpha}
+			vector.DrawFilledRect(d.img, float32(x), float32(y), float32(cell), float32(cell), tint, false)
+		}
+	}
+
+	d.viewport = viewport
+	d.positions = positions
+	d.masses = masses
+	d.exponent = g.config.forceExponent
+}
+
+// draws the dominance overlay into the viewport, redrawing the cache if the planets moved
+func (g *Game) drawDominance(screen *ebiten.Image) {
+	var positions []Vector
+	var masses []float64
+	for _, so := range g.spaceObjects {
+		if !so.isSpacecraft {
+			positions = append(positions, so.scaledPosition)
+			masses = append(masses, so.mass)
+		}
+	}
+
+	viewport := g.viewport()
+	if g.dominance.stale(viewport, positions, masses, g.config.forceExponent) {
+		g.updateDominanceOverlay(viewport, positions, masses)
+	}
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(viewport.Min.X), float64(viewport.Min.Y))
+	screen.DrawImage(g.dominance.img, op)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestDominantPlanetAt(t *testing.T) {
+	g := newGame()
+	g.spaceObjects = []*SpaceObject{
+		{name: "heavy", mass: 4e24},
+		{name: "light", mass: 1e24, position: Vector{3e8, 0}},
+		{name: "craft", mass: 1e30, isSpacecraft: true, position: Vector{2e8, 0}},
+	}
+
+	// with the inverse-square law the pulls are equal where the distances are 2:1, at 2e8 m
+	name := func(p Vector) string {
+		if so := g.dominantPlanetAt(p); so != nil {
+			return so.name
+		}
+		return ""
+	}
+	if got := name(Vector{1.9e8, 0}); got != "heavy" {
+		t.Errorf("dominant planet before the boundary is %q, want heavy", got)
+	}
+	if got := name(Vector{2.1e8, 0}); got != "light" {
+		t.Errorf("dominant planet behind the boundary is %q, want light, the spacecraft does not count", got)
+	}
+
+	// a weaker falloff lets the heavy planet dominate further out
+	g.config.forceExponent = 1
+	if got := name(Vector{2.1e8, 0}); got != "heavy" {
+		t.Errorf("dominant planet with exponent 1 is %q, want heavy", got)
+	}
+}
+
+func TestDominanceOverlayStale(t *testing.T) {
+	viewport := image.Rect(0, 0, 100, 100)
+	positions := []Vector{{10, 10}, {50, 50}}
+	masses := []float64{1, 2}
+	d := dominanceOverlay{cell: 16, img: ebiten.NewImage(1, 1), viewport: viewport, positions: positions, masses: masses, exponent: 2}
+
+	tests := []struct {
+		name      string
+		viewport  image.Rectangle
+		positions []Vector
+		masses    []float64
+		exponent  float64
+		want      bool
+	}{
+		{"unchanged", viewport, positions, masses, 2, false},
+		{"moved within half a cell", viewport, []Vector{{17, 10}, {50, 50}}, masses, 2, false},
+		{"moved further", viewport, []Vector{{19, 10}, {50, 50}}, masses, 2, true},
+		{"resized viewport", image.Rect(0, 0, 120, 100), positions, masses, 2, true},
+		{"planet removed", viewport, positions[:1], masses[:1], 2, true},
+		{"mass changed", viewport, positions, []float64{1, 4}, 2, true},
+		{"exponent changed", viewport, positions, masses, 2.5, true},
+	}
+	for _, test := range tests {
+		if got := d.stale(test.viewport, test.positions, test.masses, test.exponent); got != test.want {
+			t.Errorf("%s: stale = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
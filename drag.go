@@ -0,0 +1,84 @@
+package main
+
+import (
+	"math"
+	"strconv"
+)
+
+const (
+	defaultScaleHeight float64 = 8500 // height in m over which the atmosphere density drops by a factor of e
+	spacecraftDragArea float64 = 10   // cross section of the spacecraft in m^2
+	dragCoefficient    float64 = 2.2  // drag coefficient of the spacecraft
+)
+
+// slows the spacecraft down relative to the planet whose atmosphere it flies through
+// the density falls exponentially with the height above the surface, the drag is 1/2 rho v^2 Cd A
+// returns true if the spacecraft was slowed down
+func (g *Game) applyDrag() bool {
+	if g.config.atmosphereDensity <= 0 {
+		return false
+	}
+	craft, body := g.spacecraftAndDominantBody()
+	if craft == nil || body == nil || g.config.frozen(craft) || craft.mode != modeNBody {
+		return false
+	}
+
+	height := math.Sqrt(craft.position.DistanceSquared(body.position)) - body.radius
+	density := g.config.atmosphereDensity * math.Exp(-height/g.config.atmosphereScaleHeight)
+	v := craft.velocity.Translate(-body.velocity.X, -body.velocity.Y)
+	speed := v.Length()
+	if speed == 0 || density == 0 {
+		return false
+	}
+
+	// deceleration against the velocity relative to the atmosphere, it can at most stop the craft
+	deceleration := 0.5 * density * speed * speed * dragCoefficient * spacecraftDragArea / craft.mass
+	dv := math.Min(deceleration*dt, speed)
+	craft.velocity = craft.velocity.Translate(-v.X/speed*dv, -v.Y/speed*dv)
+	return true
+}
+
+// estimates how fast the orbit of the spacecraft decays from the energy it loses between periapsis passes
+type decayTracker struct {
+	body         *SpaceObject // body of the last periapsis pass
+	energy       float64      // specific orbital energy at the last periapsis pass in J/kg
+	lossPerOrbit float64      // specific energy lost from the second last to the last pass in J/kg
+	orbitsLeft   float64      // estimated orbits until the orbit is inside the body, NaN if unknown
+}
+
+// updates the decay estimate at every periapsis pass of the spacecraft
+// the orbit counts as decayed once its semi-major axis is below the radius of the body, that is at the
+// specific energy -mu / 2R; the remaining orbits are the energy left above that divided by the loss per orbit
+func (g *Game) trackDecay(events []spacecraftEvent) {
+	_, body := g.spacecraftAndDominantBody()
+	if body != g.decay.body {
+		g.decay = decayTracker{body: body, energy: math.NaN(), orbitsLeft: math.NaN()}
+	}
+	if body == nil {
+		return
+	}
+
+	for _, event := range events {
+		if event.trigger != triggerPeriapsis || event.body != body.name {
+			continue
+		}
+		energy, _ := g.specificOrbitalEnergy()
+		if !math.IsNaN(g.decay.energy) {
+			g.decay.lossPerOrbit = g.decay.energy - energy
+			g.decay.orbitsLeft = math.NaN()
+			decayed := -g.config.gravitationalConstant() * body.mass / (2 * body.radius)
+			if g.decay.lossPerOrbit > 0 && energy > decayed {
+				g.decay.orbitsLeft = (energy - decayed) / g.decay.lossPerOrbit
+			}
+		}
+		g.decay.energy = energy
+	}
+}
+
+// returns the decay warning for the HUD, empty if the orbit does not measurably decay
+func (g *Game) decayWarning() string {
+	if g.config.atmosphereDensity <= 0 || math.IsNaN(g.decay.orbitsLeft) {
+		return ""
+	}
+	return "Orbit decaying: about " + strconv.FormatFloat(g.decay.orbitsLeft, 'f', 1, 64) + " orbits left around " + g.decay.body.name
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDragDeceleration(t *testing.T) {
+	g := circularOrbitGame(2 * testStarRadius)
+	// a thin atmosphere that takes about 170 m/s of the 310 km/s in one step
+	g.config.atmosphereDensity = 1e-14
+	g.config.atmosphereScaleHeight = testStarRadius
+	craft := g.spaceObjects[1]
+	speed := craft.velocity.Length()
+
+	if !g.applyDrag() {
+		t.Fatal("no drag in the atmosphere")
+	}
+	// one scale height up the density is 1/e of the surface density
+	density := g.config.atmosphereDensity / math.E
+	dv := 0.5 * density * speed * speed * dragCoefficient * spacecraftDragArea / craft.mass * dt
+	if want := (Vector{0, speed - dv}); math.Sqrt(craft.velocity.DistanceSquared(want)) > 1e-9*speed {
+		t.Errorf("velocity after the drag is %v m/s, want %v", craft.velocity, want)
+	}
+
+	g.config.atmosphereDensity = 0
+	if g.applyDrag() {
+		t.Error("drag without an atmosphere")
+	}
+}
+
+func TestDecayEstimateWithAConstantLoss(t *testing.T) {
+	g := ellipticOrbitGame(10*testStarRadius, 0.8, 0, 0)
+	g.config.atmosphereDensity = 1e-6
+	craft := g.spaceObjects[1]
+	mu := g.config.gravitationalConstant() * testStarMass
+	periapsis := craft.position.Length()
+	decayed := -mu / (2 * testStarRadius)
+
+	// the drag takes the same energy at every periapsis pass
+	start, _ := g.specificOrbitalEnergy()
+	loss := (start - decayed) / 20
+	pass := func(n int) {
+		energy := start - float64(n)*loss
+		craft.velocity = Vector{0, math.Sqrt(2 * (energy + mu/periapsis))}
+		g.trackDecay([]spacecraftEvent{{triggerPeriapsis, "star"}})
+	}
+
+	pass(0)
+	if g.decayWarning() != "" {
+		t.Errorf("warning %q after the first pass, the loss per orbit is not known yet", g.decayWarning())
+	}
+	for n := 1; n <= 5; n++ {
+		pass(n)
+		if want := float64(20 - n); math.Abs(g.decay.orbitsLeft-want) > 1e-6 {
+			t.Errorf("after %d passes %v orbits are left, want %v", n, g.decay.orbitsLeft, want)
+		}
+	}
+	if g.decayWarning() == "" {
+		t.Error("no warning for a decaying orbit")
+	}
+
+	// other events do not count as a pass
+	g.trackDecay([]spacecraftEvent{{triggerSOIExit, "star"}})
+	if want := 15.0; math.Abs(g.decay.orbitsLeft-want) > 1e-6 {
+		t.Errorf("%v orbits left after a non periapsis event, want %v", g.decay.orbitsLeft, want)
+	}
+}
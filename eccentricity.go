@@ -0,0 +1,52 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	eccentricityArrowScale float64 = 150         // length of the arrow in pixel for an eccentricity of 1
+	arrowHeadLength        float64 = 8           // length of the two lines of an arrow head in pixel
+	arrowHeadAngle         float64 = math.Pi / 7 // angle between the shaft and the lines of an arrow head in rad
+)
+
+var eccentricityColor = color.RGBA{255, 120, 200, 255}
+
+// returns the eccentricity vector of the spacecraft around its dominant body together with the body
+// it points to the periapsis and its length is the eccentricity, it stays constant as long as only the body pulls
+// returns false if there is no spacecraft orbiting a body
+func (g *Game) EccentricityVector() (Vector, *SpaceObject, bool) {
+	craft, body := g.spacecraftAndDominantBody()
+	if craft == nil || body == nil {
+		return Vector{}, nil, false
+	}
+	r := craft.position.Translate(-body.position.X, -body.position.Y)
+	v := craft.velocity.Translate(-body.velocity.X, -body.velocity.Y)
+	return eccentricityVector(r, v, g.config.gravitationalConstant()*body.mass), body, true
+}
+
+// draws a line from one screen position to another with a head at the end
+func drawArrow(screen *ebiten.Image, from, to Vector, width float32, c color.Color) {
+	vector.StrokeLine(screen, float32(from.X), float32(from.Y), float32(to.X), float32(to.Y), width, c, true)
+	back := math.Atan2(from.Y-to.Y, from.X-to.X)
+	for _, side := range []float64{-arrowHeadAngle, arrowHeadAngle} {
+		head := to.Translate(arrowHeadLength*math.Cos(back+side), arrowHeadLength*math.Sin(back+side))
+		vector.StrokeLine(screen, float32(to.X), float32(to.Y), float32(head.X), float32(head.Y), width, c, true)
+	}
+}
+
+// draws the eccentricity vector of the spacecraft as an arrow from its dominant body
+// the arrow has a fixed length per eccentricity on screen, so it stays readable at any zoom
+func (g *Game) drawEccentricityVector(screen *ebiten.Image) {
+	e, body, ok := g.EccentricityVector()
+	if !ok || e.Length() == 0 {
+		return
+	}
+	from := g.worldToScreen(body.position)
+	to := from.Translate(e.X*eccentricityArrowScale, e.Y*eccentricityArrowScale)
+	drawArrow(screen, from, to, 2, eccentricityColor)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEccentricityVector(t *testing.T) {
+	g := circularOrbitGame(testOrbitRadius)
+	e, body, ok := g.EccentricityVector()
+	if !ok || body != g.spaceObjects[0] {
+		t.Fatal("no eccentricity vector around the star")
+	}
+	if e.Length() > 1e-12 {
+		t.Errorf("eccentricity vector of a circular orbit is %v, want about 0", e)
+	}
+
+	// wherever the spacecraft is on the ellipse, the vector points to the periapsis with the length of the eccentricity
+	const eccentricity, argumentOfPeriapsis = 0.4, 1.1
+	want := Vector{eccentricity * math.Cos(argumentOfPeriapsis), eccentricity * math.Sin(argumentOfPeriapsis)}
+	for _, trueAnomaly := range []float64{0, 0.5, math.Pi / 2, math.Pi, 4} {
+		g := ellipticOrbitGame(testOrbitRadius, eccentricity, argumentOfPeriapsis, trueAnomaly)
+		e, _, _ := g.EccentricityVector()
+		if math.Sqrt(e.DistanceSquared(want)) > 1e-12 {
+			t.Errorf("eccentricity vector at a true anomaly of %v rad is %v, want %v", trueAnomaly, e, want)
+		}
+	}
+
+	// without a spacecraft there is nothing to point at
+	g.spaceObjects = g.spaceObjects[:1]
+	if _, _, ok := g.EccentricityVector(); ok {
+		t.Error("eccentricity vector reported without a spacecraft")
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import "fmt"
+
+// EndCondition decides when an automated run is over
+// every condition is disabled by its zero value
+type EndCondition struct {
+	escapeDuration float64 // end after the spacecraft had a positive specific energy for this long in s
+	onCrash        bool    // end when the spacecraft merged with another body
+	timeLimit      float64 // end once the simulated time reaches this value in s
+
+	escapingSince float64 // time the specific energy of the spacecraft last became positive
+	escaping      bool    // the specific energy of the spacecraft is positive
+	hadSpacecraft bool    // the spacecraft existed before the current step
+}
+
+// returns true if any condition is enabled
+func (c EndCondition) enabled() bool {
+	return c.escapeDuration > 0 || c.onCrash || c.timeLimit > 0
+}
+
+// evaluates the end condition after a step and sets done and doneReason once it is met
+func (g *Game) checkEndCondition() {
+	c := &g.endCondition
+	if g.done || !c.enabled() {
+		return
+	}
+
+	if g.spacecraftIndex() >= 0 {
+		c.hadSpacecraft = true
+	} else if c.hadSpacecraft && c.onCrash {
+		g.done = true
+		g.doneReason = "spacecraft crashed"
+		return
+	}
+
+	if c.escapeDuration > 0 {
+		// the specific energy is NaN without spacecraft or dominant body, which does not count as escaping
+		if g.SpecificOrbitalEnergy() > 0 {
+			if !c.escaping {
+				c.escaping = true
+				c.escapingSince = g.time
+			}
+			if g.time-c.escapingSince >= c.escapeDuration {
+				g.done = true
+				g.doneReason = fmt.Sprintf("spacecraft escaped (positive energy for %s)", formatDuration(g.time-c.escapingSince))
+				return
+			}
+		} else {
+			c.escaping = false
+		}
+	}
+
+	if c.timeLimit > 0 && g.time >= c.timeLimit {
+		g.done = true
+		g.doneReason = "time limit of " + formatDuration(c.timeLimit) + " reached"
+	}
+}
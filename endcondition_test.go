@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// steps the game until it is done or the given number of steps is reached and returns the number of steps taken
+func stepUntilDone(g *Game, steps int) int {
+	step := 0
+	for ; step < steps && !g.done; step++ {
+		g.Step()
+	}
+	return step
+}
+
+func TestEndConditions(t *testing.T) {
+	escaping := func() *Game {
+		g := circularOrbitGame(testOrbitRadius)
+		g.spaceObjects[1].velocity = Vector{0, 2 * g.config.EscapeVelocity(testStarMass, testOrbitRadius)}
+		return g
+	}
+	falling := func() *Game {
+		g := circularOrbitGame(2 * testStarRadius)
+		g.spaceObjects[1].velocity = Vector{0, 0}
+		return g
+	}
+	bound := func() *Game { return circularOrbitGame(testOrbitRadius) }
+
+	tests := []struct {
+		name      string
+		create    func() *Game
+		condition EndCondition
+		steps     int    // number of steps until done, 0 if it never ends within 50 steps
+		reason    string // part of the reason
+	}{
+		{"escape", escaping, EndCondition{escapeDuration: 5 * dt}, 6, "escaped"},
+		{"bound orbit does not escape", bound, EndCondition{escapeDuration: 5 * dt}, 0, ""},
+		{"crash", falling, EndCondition{onCrash: true}, 1, "crashed"},
+		{"no crash on a bound orbit", bound, EndCondition{onCrash: true}, 0, ""},
+		{"time limit", bound, EndCondition{timeLimit: 10 * dt}, 10, "time limit"},
+		{"disabled", falling, EndCondition{}, 0, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := test.create()
+			g.endCondition = test.condition
+			steps := stepUntilDone(g, 50)
+			if test.steps == 0 {
+				if g.done {
+					t.Errorf("done after %d steps: %s", steps, g.doneReason)
+				}
+				return
+			}
+			if steps != test.steps || !strings.Contains(g.doneReason, test.reason) {
+				t.Errorf("done after %d steps with %q, want %d steps and a reason containing %q", steps, g.doneReason, test.steps, test.reason)
+			}
+		})
+	}
+}
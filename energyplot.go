@@ -0,0 +1,102 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	defaultEnergyPlotLength int     = 500 // number of recent steps the energy plot shows
+	energyPlotWidth         float32 = 240 // width of the energy plot panel in pixel
+	energyPlotHeight        float32 = 100 // height of the energy plot panel in pixel
+	energyPlotMargin        float32 = 10  // distance of the panel to the corner of the viewport in pixel
+)
+
+var (
+	energyPlotColor         = color.RGBA{255, 180, 80, 255}
+	energyPlotBaselineColor = color.RGBA{120, 120, 120, 255}
+)
+
+// ring buffer of the total energy of the recent steps
+// once it is full the oldest value is overwritten, so recording never allocates
+type energyHistory struct {
+	values []float64 // J, a slot per step of the plot
+	next   int       // slot the next value is written to
+	full   bool      // every slot holds a value
+}
+
+// returns a history holding the given number of values
+func newEnergyHistory(length int) energyHistory {
+	return energyHistory{values: make([]float64, length)}
+}
+
+// adds a value, replacing the oldest one if the history is full
+func (h *energyHistory) push(value float64) {
+	if len(h.values) == 0 {
+		return
+	}
+	h.values[h.next] = value
+	h.next = (h.next + 1) % len(h.values)
+	h.full = h.full || h.next == 0
+}
+
+// forgets all values
+func (h *energyHistory) clear() {
+	h.next = 0
+	h.full = false
+}
+
+// returns the values from the oldest to the newest
+func (h *energyHistory) ordered() []float64 {
+	if !h.full {
+		return h.values[:h.next]
+	}
+	return append(append([]float64(nil), h.values[h.next:]...), h.values[:h.next]...)
+}
+
+// returns the pixel row of a value within the plot, rows grow downwards so the largest value is at the top
+// a plot without range has all values in the middle
+func plotY(value, low, high float64, top, height float32) float32 {
+	if high <= low {
+		return top + height/2
+	}
+	return top + height*float32((high-value)/(high-low))
+}
+
+// remembers the total energy of the system after a step for the energy plot
+func (g *Game) recordEnergy() {
+	g.energyHistory.push(g.TotalEnergy())
+}
+
+// draws the recorded energies over time as a line in a panel at the bottom left of the viewport
+// the range always contains the initial energy, which is marked as a horizontal line
+func (g *Game) drawEnergyPlot(screen *ebiten.Image) {
+	viewport := g.viewport()
+	left := float32(viewport.Min.X) + energyPlotMargin
+	top := float32(viewport.Max.Y) - energyPlotHeight - energyPlotMargin
+	vector.DrawFilledRect(screen, left, top, energyPlotWidth, energyPlotHeight, hodographBackgroundColor, false)
+
+	values := g.energyHistory.ordered()
+	if len(values) == 0 {
+		return
+	}
+	low, high := g.initialEnergy, g.initialEnergy
+	for _, value := range values {
+		low, high = math.Min(low, value), math.Max(high, value)
+	}
+
+	baseline := plotY(g.initialEnergy, low, high, top, energyPlotHeight)
+	vector.StrokeLine(screen, left, baseline, left+energyPlotWidth, baseline, 1, energyPlotBaselineColor, false)
+
+	// the plot is filled from the left, a full history spans the whole width
+	step := energyPlotWidth / float32(max(1, len(g.energyHistory.values)-1))
+	for i := 1; i < len(values); i++ {
+		vector.StrokeLine(screen,
+			left+float32(i-1)*step, plotY(values[i-1], low, high, top, energyPlotHeight),
+			left+float32(i)*step, plotY(values[i], low, high, top, energyPlotHeight),
+			1, energyPlotColor, true)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+func TestPlotY(t *testing.T) {
+	// a plot 100 pixel high with its top at row 20
+	const top, height = 20, 100
+	tests := []struct {
+		name             string
+		value, low, high float64
+		want             float32
+	}{
+		{"highest value at the top", -1e33, -3e33, -1e33, 20},
+		{"lowest value at the bottom", -3e33, -3e33, -1e33, 120},
+		{"middle value in the middle", -2e33, -3e33, -1e33, 70},
+		{"quarter from the top", 7.5, 0, 10, 45},
+		{"no range", 5, 5, 5, 70},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := plotY(test.value, test.low, test.high, top, height); got != test.want {
+				t.Errorf("plotY(%v, %v, %v) = %v, want %v", test.value, test.low, test.high, got, test.want)
+			}
+		})
+	}
+}
+
+func TestEnergyHistoryKeepsTheNewestValues(t *testing.T) {
+	h := newEnergyHistory(3)
+	for _, value := range []float64{1, 2, 3, 4, 5} {
+		h.push(value)
+	}
+	got := h.ordered()
+	if len(got) != 3 || got[0] != 3 || got[1] != 4 || got[2] != 5 {
+		t.Errorf("history holds %v, want [3 4 5]", got)
+	}
+	h.clear()
+	if got := h.ordered(); len(got) != 0 {
+		t.Errorf("cleared history holds %v", got)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import "math"
+
+// float32 version of calculateGravitationalForce for large simulations
+//
+// Positions, velocities and masses stay float64. Only the per-pair work (distance, direction and
+// the acceleration G*M/r^n) is done in float32, which halves the size of the intermediate values.
+// The distance vector is still subtracted in float64 first, otherwise the ~7 significant digits of
+// float32 would lose the separation of close bodies far away from the origin.
+// The acceleration instead of the force is computed in float32, because G*m1*m2 of two stars exceeds
+// the float32 range; it is multiplied by the mass of so1 in float64 at the end.
+//
+// Go does not vectorize this loop, so float32 is not automatically faster: it depends on the hardware
+// whether the smaller values matter. Trajectories drift from the float64 ones by roughly the float32
+// precision per step, so only use it where the looser accuracy does not matter.
+func calculateGravitationalForce32(so1, so2 SpaceObject, config SimConfig) Vector {
+	dx := float32(so1.position.X - so2.position.X)
+	dy := float32(so1.position.Y - so2.position.Y)
+	distance := float32(math.Sqrt(float64(dx*dx + dy*dy)))
+
+	// acceleration of so1 towards so2
+	var power float32
+	if config.forceExponent == 2.0 {
+		power = distance * distance
+	} else {
+		power = float32(math.Pow(float64(distance), config.forceExponent))
+	}
+	acceleration := float32(config.gravitationalConstant()) * float32(so2.mass) / power
+
+	// the direction points from so1 towards so2
+	ax := -acceleration * dx / distance
+	ay := -acceleration * dy / distance
+
+	return Vector{float64(ax) * so1.mass, float64(ay) * so1.mass}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestFloat32ForcesStayCloseToFloat64(t *testing.T) {
+	spaceObjects := randomBodies(100, 1e12)
+	config := DefaultSimConfig()
+	for i := range spaceObjects {
+		for j := range spaceObjects {
+			if i == j {
+				continue
+			}
+			want := calculateGravitationalForce(*spaceObjects[i], *spaceObjects[j], config)
+			got := calculateGravitationalForce32(*spaceObjects[i], *spaceObjects[j], config)
+			if d := math.Sqrt(got.DistanceSquared(want)); d > 1e-6*want.Length() {
+				t.Fatalf("float32 force between %d and %d is %v, want %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestFloat32TrajectoryStaysClose(t *testing.T) {
+	exact := NewGame()
+	loose := NewGame()
+	loose.config.float32Forces = true
+	for range 200 {
+		exact.Step()
+		loose.Step()
+	}
+
+	// the float32 path drifts, but over a flyby it stays within a millionth of the distance travelled
+	for i, so := range loose.spaceObjects {
+		want := exact.spaceObjects[i]
+		travelled := math.Sqrt(want.position.DistanceSquared(NewGame().spaceObjects[i].position))
+		if d := math.Sqrt(so.position.DistanceSquared(want.position)); d > 1e-6*travelled {
+			t.Errorf("%q is %v m away from the float64 position after travelling %v m", so.name, d, travelled)
+		}
+	}
+}
+
+func BenchmarkNetForces(b *testing.B) {
+	for _, n := range []int{100, 1000} {
+		for _, float32Forces := range []bool{false, true} {
+			b.Run(fmt.Sprintf("bodies=%d/float32=%v", n, float32Forces), func(b *testing.B) {
+				spaceObjects := randomBodies(n, 1e12)
+				config := DefaultSimConfig()
+				config.numWorkers = 1
+				config.float32Forces = float32Forces
+				b.ResetTimer()
+				for range b.N {
+					netForces(spaceObjects, nil, config)
+				}
+			})
+		}
+	}
+}
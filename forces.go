@@ -0,0 +1,48 @@
+package main
+
+import "sync"
+
+// below this number of spaceobjects the goroutines cost more than they save
+const parallelMinBodies int = 64
+
+// returns the net force on every spaceobject at the current positions
+// with more than one worker the spaceobjects are split into contiguous chunks computed concurrently;
+// every force is still summed by netForce in the same order, so the result does not depend on the number of workers
+// with the spatial hash only the bodies in the cells around a body are considered, which gives the same forces
+// as the direct summation with the cutoff, see useSpatialHash
+func netForces(spaceObjects []*SpaceObject, springs []Spring, config SimConfig) []Vector {
+	forces := make([]Vector, len(spaceObjects))
+	force := func(i int) Vector {
+		return netForce(spaceObjects, springs, i, config)
+	}
+	if config.useSpatialHash(len(spaceObjects)) {
+		grid := newSpatialHash(spaceObjects, config.cutoffDistance)
+		force = func(i int) Vector {
+			return netForceFrom(spaceObjects, springs, i, config, grid.neighbors(spaceObjects[i].position))
+		}
+	}
+
+	workers := min(config.numWorkers, len(spaceObjects))
+	if workers <= 1 || len(spaceObjects) < parallelMinBodies {
+		for i := range spaceObjects {
+			forces[i] = force(i)
+		}
+		return forces
+	}
+
+	// every worker only writes the forces of its own chunk, so no locking is needed
+	var wg sync.WaitGroup
+	chunk := (len(spaceObjects) + workers - 1) / workers
+	for start := 0; start < len(spaceObjects); start += chunk {
+		end := min(start+chunk, len(spaceObjects))
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				forces[i] = force(i)
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	return forces
+}
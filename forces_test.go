@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+// returns n bodies with random masses scattered over a square of the given size around the origin
+func randomBodies(n int, size float64) []*SpaceObject {
+	rng := rand.New(rand.NewPCG(1, 2))
+	spaceObjects := make([]*SpaceObject, n)
+	for i := range spaceObjects {
+		spaceObjects[i] = &SpaceObject{
+			name:     "body",
+			mass:     math.Pow(10, 20+4*rng.Float64()),
+			radius:   1,
+			position: Vector{(rng.Float64() - 0.5) * size, (rng.Float64() - 0.5) * size},
+			velocity: Vector{(rng.Float64() - 0.5) * 1e3, (rng.Float64() - 0.5) * 1e3},
+		}
+	}
+	return spaceObjects
+}
+
+func TestCutoffDistance(t *testing.T) {
+	spaceObjects := []*SpaceObject{
+		{name: "probe", mass: 1},
+		{name: "near", mass: testStarMass, position: Vector{1e9, 0}},
+		{name: "far", mass: testStarMass, position: Vector{0, 1e11}},
+	}
+	config := DefaultSimConfig()
+	config.cutoffDistance = 1e10
+
+	force := netForce(spaceObjects, nil, 0, config)
+	near := calculateGravitationalForce(*spaceObjects[0], *spaceObjects[1], config)
+	if force != near {
+		t.Errorf("force on the probe is %v, want only the pull of the near body %v", force, near)
+	}
+
+	// the far body feels nothing either, the cutoff is symmetric
+	if force := netForce(spaceObjects, nil, 2, config); force != (Vector{0, 0}) {
+		t.Errorf("force on the far body is %v, want 0", force)
+	}
+
+	// without a cutoff the far body pulls the probe along y
+	config.cutoffDistance = math.Inf(1)
+	if force := netForce(spaceObjects, nil, 0, config); force.Y <= 0 {
+		t.Errorf("force on the probe without a cutoff is %v, want a pull towards the far body", force)
+	}
+}
+
+func TestParallelForcesMatchTheSerialPath(t *testing.T) {
+	spaceObjects := randomBodies(500, 1e12)
+	springs := []Spring{{a: 0, b: 1, restLength: 1e9, stiffness: 1e10}, {a: 499, b: 3, restLength: 1e10, stiffness: 1e8}}
+	config := DefaultSimConfig()
+	config.numWorkers = 1
+	serial := netForces(spaceObjects, springs, config)
+
+	// the chunks do not divide the bodies evenly for most of these, and 1000 workers is more than there are bodies
+	for _, workers := range []int{2, 3, 7, 16, 1000} {
+		config.numWorkers = workers
+		parallel := netForces(spaceObjects, springs, config)
+		for i := range serial {
+			if parallel[i] != serial[i] {
+				t.Fatalf("%d workers: force on body %d is %v, want %v", workers, i, parallel[i], serial[i])
+			}
+		}
+	}
+}
+
+func BenchmarkNetForcesWorkers(b *testing.B) {
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			spaceObjects := randomBodies(1000, 1e12)
+			config := DefaultSimConfig()
+			config.numWorkers = workers
+			b.ResetTimer()
+			for range b.N {
+				netForces(spaceObjects, nil, config)
+			}
+		})
+	}
+}
+
+func TestSpatialHashMatchesTheDirectSummation(t *testing.T) {
+	// bodies in cells of all signs, some of them exactly on the cell borders
+	spaceObjects := randomBodies(2*spatialHashMinBodies, 1e12)
+	spaceObjects[0].position = Vector{0, 0}
+	spaceObjects[1].position = Vector{1e11, -1e11}
+	config := DefaultSimConfig()
+	config.cutoffDistance = 1e11
+	config.numWorkers = 1
+	direct := netForces(spaceObjects, nil, config)
+
+	config.spatialHash = true
+	if !config.useSpatialHash(len(spaceObjects)) {
+		t.Fatal("the spatial hash is not used")
+	}
+	for _, workers := range []int{1, 4} {
+		config.numWorkers = workers
+		hashed := netForces(spaceObjects, nil, config)
+		for i := range direct {
+			if hashed[i] != direct[i] {
+				t.Fatalf("%d workers: force on body %d is %v with the spatial hash, want %v", workers, i, hashed[i], direct[i])
+			}
+		}
+	}
+
+	// too few bodies or no cutoff fall back to the direct summation
+	if config.useSpatialHash(spatialHashMinBodies - 1) {
+		t.Errorf("the spatial hash is used for %d bodies", spatialHashMinBodies-1)
+	}
+	config.cutoffDistance = math.Inf(1)
+	if config.useSpatialHash(len(spaceObjects)) {
+		t.Error("the spatial hash is used without a cutoff")
+	}
+}
+
+func BenchmarkSpatialHash(b *testing.B) {
+	for _, n := range []int{spatialHashMinBodies, 1000, 5000} {
+		for _, hashed := range []bool{false, true} {
+			b.Run(fmt.Sprintf("bodies=%d/hash=%v", n, hashed), func(b *testing.B) {
+				spaceObjects := randomBodies(n, 1e12)
+				config := DefaultSimConfig()
+				config.cutoffDistance = 5e10
+				config.spatialHash = hashed
+				config.numWorkers = 1
+				b.ResetTimer()
+				for range b.N {
+					netForces(spaceObjects, nil, config)
+				}
+			})
+		}
+	}
+}
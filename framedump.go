@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	frameWidth  int = 1080 // width of the rendered frames in pixel
+	frameHeight int = 720  // height of the rendered frames in pixel
+)
+
+// steps a game as fast as possible and writes every interval-th frame as a numbered png for a video
+// ebiten can only draw once its graphics are running, so the frames are rendered inside a window that shows the progress
+type frameRenderer struct {
+	game     *Game
+	dir      string // directory the frames are written to
+	interval int    // every interval-th step is written
+	steps    int    // number of steps to simulate
+	step     int    // steps simulated so far
+	written  int    // frames written so far
+	frame    *ebiten.Image
+	err      error // error that stopped the rendering
+
+	// draws the game and writes it as frame number written, saveFrame for a real run
+	writeFrame func() error
+}
+
+// returns the path of the frame with the given number, frame_000000.png and up, as ffmpeg -i frame_%06d.png expects
+func framePath(dir string, number int) string {
+	return filepath.Join(dir, fmt.Sprintf("frame_%06d.png", number))
+}
+
+// returns the number of frames a run of the given steps writes with the given interval
+func frameCount(steps, interval int) int {
+	if steps <= 0 || interval <= 0 {
+		return 0
+	}
+	return (steps + interval - 1) / interval
+}
+
+func (r *frameRenderer) Update() error {
+	// like the headless run, a met end condition stops early
+	if r.step >= r.steps || r.game.done {
+		return ebiten.Termination
+	}
+
+	r.game.Step()
+	r.game.updateView()
+	r.step++
+
+	// the first step is always written, then every interval-th
+	if (r.step-1)%r.interval != 0 {
+		return nil
+	}
+	if err := r.writeFrame(); err != nil {
+		r.err = err
+		return ebiten.Termination
+	}
+	r.written++
+	return nil
+}
+
+// draws the game into the frame and writes it as the next png of the sequence
+func (r *frameRenderer) saveFrame() error {
+	r.frame.Clear()
+	r.game.Draw(r.frame)
+	img := image.NewRGBA(r.frame.Bounds())
+	r.frame.ReadPixels(img.Pix)
+
+	file, err := os.Create(framePath(r.dir, r.written))
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (r *frameRenderer) Draw(screen *ebiten.Image) {
+	screen.DrawImage(r.frame, nil)
+}
+
+func (r *frameRenderer) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return frameWidth, frameHeight
+}
+
+// simulates the game for the given number of steps and writes every interval-th frame to dir as png
+func renderFrames(game *Game, dir string, steps, interval int) error {
+	if interval <= 0 {
+		return fmt.Errorf("frame interval must be positive, got %d", interval)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	game.screenWidth = frameWidth
+	game.screenHeight = frameHeight
+	r := &frameRenderer{game: game, dir: dir, interval: interval, steps: steps, frame: ebiten.NewImage(frameWidth, frameHeight)}
+	r.writeFrame = r.saveFrame
+
+	// as fast as the frames can be written instead of 60 steps per second
+	ebiten.SetTPS(ebiten.SyncWithFPS)
+	ebiten.SetVsyncEnabled(false)
+	ebiten.SetWindowSize(frameWidth, frameHeight)
+	ebiten.SetWindowTitle("swingby - rendering frames")
+	if err := ebiten.RunGame(r); err != nil {
+		return err
+	}
+	if r.err != nil {
+		return r.err
+	}
+	if r.step < steps && !game.done {
+		return fmt.Errorf("wrote %d of %d frames, the window was closed early", r.written, frameCount(steps, interval))
+	}
+	fmt.Printf("wrote %d frames of %d steps to %s\n", r.written, r.step, dir)
+	return nil
+}
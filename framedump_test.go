@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// runs the frame renderer until it terminates, writing empty files instead of drawn frames
+func runFrameRenderer(t *testing.T, game *Game, steps, interval int) (*frameRenderer, []string) {
+	dir := t.TempDir()
+	r := &frameRenderer{game: game, dir: dir, interval: interval, steps: steps}
+	r.writeFrame = func() error { return os.WriteFile(framePath(dir, r.written), nil, 0644) }
+	for {
+		if err := r.Update(); err != nil {
+			if !errors.Is(err, ebiten.Termination) {
+				t.Fatal(err)
+			}
+			break
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return r, names
+}
+
+func TestRenderFramesWritesEveryIntervalthStep(t *testing.T) {
+	tests := []struct {
+		steps, interval int
+		want            int
+	}{
+		{10, 1, 10},
+		{10, 3, 4}, // steps 1, 4, 7 and 10
+		{9, 3, 3},
+		{1, 5, 1},
+		{0, 5, 0},
+	}
+	for _, test := range tests {
+		r, names := runFrameRenderer(t, NewGame(), test.steps, test.interval)
+		if len(names) != test.want || frameCount(test.steps, test.interval) != test.want {
+			t.Errorf("%d steps every %d: wrote %d frames, counted %d, want %d",
+				test.steps, test.interval, len(names), frameCount(test.steps, test.interval), test.want)
+		}
+		if r.step != test.steps {
+			t.Errorf("%d steps every %d: simulated %d steps", test.steps, test.interval, r.step)
+		}
+		// the frames are numbered without gaps, the way ffmpeg reads a sequence
+		for i, name := range names {
+			if want := filepath.Base(framePath("", i)); name != want {
+				t.Errorf("frame %d is %s, want %s", i, name, want)
+			}
+		}
+	}
+}
+
+func TestRenderFramesStopsAtTheEndCondition(t *testing.T) {
+	game := NewGame()
+	game.endCondition.timeLimit = 5 * dt
+	r, names := runFrameRenderer(t, game, 100, 2)
+	if r.step != 5 || !slices.Equal(names, []string{"frame_000000.png", "frame_000001.png", "frame_000002.png"}) {
+		t.Errorf("simulated %d steps and wrote %v, want 5 steps and 3 frames", r.step, names)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+const ghostAlpha float32 = 0.5 // opacity of the newest ghost trail, older ones are dimmer
+
+// returns one image with the trails of all spaceobjects, nil if none has a trail
+func (g *Game) combinedTrails() *ebiten.Image {
+	var combined *ebiten.Image
+	for _, so := range g.spaceObjects {
+		if so.pathImg == nil {
+			continue
+		}
+		if combined == nil {
+			bounds := so.pathImg.Bounds()
+			combined = ebiten.NewImage(bounds.Dx(), bounds.Dy())
+		}
+		combined.DrawImage(so.pathImg, nil)
+	}
+	return combined
+}
+
+// appends the trails of a finished run to the ghosts, oldest first, and drops the oldest beyond limit
+func pushGhost(ghosts []*ebiten.Image, trails *ebiten.Image, limit int) []*ebiten.Image {
+	if trails != nil {
+		ghosts = append(ghosts, trails)
+	}
+	for len(ghosts) > limit {
+		ghosts[0].Deallocate()
+		ghosts = ghosts[1:]
+	}
+	return ghosts
+}
+
+// draws the trails of the previous runs, each one dimmer than the run after it
+func (g *Game) drawGhosts(screen *ebiten.Image) {
+	for i, ghost := range g.ghosts {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(1/g.trailScale, 1/g.trailScale)
+		op.Filter = ebiten.FilterLinear
+		op.ColorScale.ScaleAlpha(ghostAlpha * float32(i+1) / float32(len(g.ghosts)))
+		screen.DrawImage(ghost, op)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestPushGhostDropsTheOldest(t *testing.T) {
+	runs := make([]*ebiten.Image, 5)
+	for i := range runs {
+		runs[i] = ebiten.NewImage(1, 1)
+	}
+
+	var ghosts []*ebiten.Image
+	for i, run := range runs {
+		ghosts = pushGhost(ghosts, run, 3)
+		// the newest run is always last, and at most three are kept
+		if want := min(i+1, 3); len(ghosts) != want || ghosts[len(ghosts)-1] != run {
+			t.Fatalf("after run %d: %d ghosts ending with %p, want %d ending with %p", i, len(ghosts), ghosts[len(ghosts)-1], want, run)
+		}
+	}
+	for i, want := range runs[2:] {
+		if ghosts[i] != want {
+			t.Errorf("ghost %d is %p, want run %d %p", i, ghosts[i], i+2, want)
+		}
+	}
+
+	// a run without trails adds no ghost, but a lower limit still drops the oldest
+	ghosts = pushGhost(ghosts, nil, 3)
+	if len(ghosts) != 3 || ghosts[2] != runs[4] {
+		t.Errorf("a run without trails changed the ghosts to %v", ghosts)
+	}
+	ghosts = pushGhost(ghosts, nil, 1)
+	if len(ghosts) != 1 || ghosts[0] != runs[4] {
+		t.Errorf("limit 1 kept %v, want only the newest run", ghosts)
+	}
+	if ghosts = pushGhost(ghosts, ebiten.NewImage(1, 1), 0); len(ghosts) != 0 {
+		t.Errorf("limit 0 kept %d ghosts", len(ghosts))
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+)
+
+// simulates the given number of steps and returns a hash of the spacecraft position after every step
+// the positions are hashed bit for bit, so any change of the numerics changes the hash
+func trajectoryHash(game *Game, steps int) string {
+	h := sha256.New()
+	buf := make([]byte, 8)
+	for i := 0; i < steps; i++ {
+		game.Step()
+		position := Vector{math.NaN(), math.NaN()}
+		if index := game.spacecraftIndex(); index >= 0 {
+			position = game.spaceObjects[index].position
+		}
+		for _, f := range []float64{position.X, position.Y} {
+			binary.LittleEndian.PutUint64(buf, math.Float64bits(f))
+			h.Write(buf)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "store the current trajectory hash as the golden one in testdata")
+
+const goldenSteps = 2000 // steps of the flyby scene the golden hash covers
+
+// the flyby scene with the default configuration has to follow exactly the stored trajectory
+// after an intended change of the physics the golden hash is regenerated with go test -run TestGoldenTrajectory -update
+// the stored hash comes from js/wasm, architectures that fuse multiply-adds like arm64 round differently
+func TestGoldenTrajectory(t *testing.T) {
+	path := filepath.Join("testdata", "golden.txt")
+	hash := trajectoryHash(NewGame(), goldenSteps)
+	line := fmt.Sprintf("%d %s\n", goldenSteps, hash)
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(line), 0644); err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("golden trajectory updated: %s", line)
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v, create it with -update", err)
+	}
+	if got := string(data); got != line {
+		t.Errorf("trajectory changed: %d steps hash to %s, golden is %s", goldenSteps, hash, strings.TrimSpace(got))
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// state of a spaceobject in the headless output
+type headlessBody struct {
+	Name     string `json:"name"`
+	Position Vector `json:"position"`
+	Velocity Vector `json:"velocity"`
+}
+
+// final state of a headless run
+type headlessResult struct {
+	Steps  int            `json:"steps"`
+	Done   string         `json:"done,omitempty"` // reason the end condition was met
+	Time   float64        `json:"time"`
+	Energy float64        `json:"energy"`
+	Bodies []headlessBody `json:"bodies"`
+}
+
+// simulates the given number of steps without a window and writes the final state as json to w
+// the run stops early once the end condition of the game is met
+func runHeadless(game *Game, steps int, w io.Writer) error {
+	if steps < 0 {
+		return fmt.Errorf("number of steps must not be negative, got %d", steps)
+	}
+
+	step := 0
+	for ; step < steps && !game.done; step++ {
+		game.Step()
+	}
+
+	result := headlessResult{
+		Steps:  step,
+		Done:   game.doneReason,
+		Time:   game.time,
+		Energy: game.TotalEnergy(),
+		Bodies: make([]headlessBody, len(game.spaceObjects)),
+	}
+	for i, so := range game.spaceObjects {
+		result.Bodies[i] = headlessBody{Name: so.name, Position: so.position, Velocity: so.velocity}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// runs a single update of the game without a window and reports whether it worked
+// the simulation and the view are exercised at the size of a new window, but nothing is drawn: drawing needs a
+// graphics context, which ebiten only creates with a window, so a smoke run checks that a scene can be set up,
+// stepped and followed by the camera on machines without a display
+func runSmoke(game *Game, w io.Writer) error {
+	if err := game.checkBodyCount(); err != nil {
+		return err
+	}
+	if game.screenWidth == 0 {
+		game.screenWidth, game.screenHeight = windowWidth, windowHeight
+	}
+	game.Step()
+	game.updateView()
+	for _, so := range game.spaceObjects {
+		if math.IsNaN(so.position.X) || math.IsNaN(so.position.Y) {
+			return fmt.Errorf("position of %s is not a number after one step", so.name)
+		}
+		if math.IsNaN(so.scaledPosition.X) || math.IsNaN(so.scaledPosition.Y) {
+			return fmt.Errorf("screen position of %s is not a number after one step", so.name)
+		}
+	}
+	_, err := fmt.Fprintf(w, "smoke test passed: %d bodies, one step of %s and a view update, nothing drawn\n",
+		len(game.spaceObjects), formatDuration(dt))
+	return err
+}
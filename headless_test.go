@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestRunHeadless(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runHeadless(NewGame(), 50, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var result headlessResult
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("output is not json: %v\n%s", err, buf.String())
+	}
+
+	// the same steps taken directly give the same state
+	want := NewGame()
+	for range 50 {
+		want.Step()
+	}
+	if result.Steps != 50 || result.Time != want.time || result.Done != "" {
+		t.Errorf("ran %d steps up to %v s (done %q), want 50 steps up to %v s", result.Steps, result.Time, result.Done, want.time)
+	}
+	if result.Energy != want.TotalEnergy() {
+		t.Errorf("energy is %v J, want %v", result.Energy, want.TotalEnergy())
+	}
+	if len(result.Bodies) != len(want.spaceObjects) {
+		t.Fatalf("got %d bodies, want %d", len(result.Bodies), len(want.spaceObjects))
+	}
+	for i, body := range result.Bodies {
+		so := want.spaceObjects[i]
+		if body.Name != so.name || body.Position != so.position || body.Velocity != so.velocity {
+			t.Errorf("body %d is %+v, want %q at %v moving %v", i, body, so.name, so.position, so.velocity)
+		}
+	}
+}
+
+func TestRunHeadlessStopsAtTheEndCondition(t *testing.T) {
+	game := NewGame()
+	game.endCondition.timeLimit = 10 * dt
+
+	var buf bytes.Buffer
+	if err := runHeadless(game, 50, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var result headlessResult
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Steps != 10 || result.Done == "" {
+		t.Errorf("ran %d steps (done %q), want to stop after 10 steps", result.Steps, result.Done)
+	}
+}
+
+func TestRunHeadlessRejectsNegativeSteps(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runHeadless(NewGame(), -1, &buf); err == nil {
+		t.Error("negative number of steps was accepted")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("wrote %q for a rejected run", buf.String())
+	}
+}
+
+func TestSmokeRun(t *testing.T) {
+	// every scene of the menu can be set up and stepped without a window
+	for _, scene := range scenes {
+		t.Run(scene.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			game := scene.create()
+			if err := runSmoke(game, &buf); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(buf.String(), "smoke test passed") {
+				t.Errorf("smoke run reported %q", buf.String())
+			}
+			// the view was updated at the size of a new window, a scene without a camera is zoomed to fit
+			if !game.camera.targetSet || game.fitPending || game.screenWidth != windowWidth {
+				t.Errorf("view was not updated: camera set %v, fit pending %v, width %d", game.camera.targetSet, game.fitPending, game.screenWidth)
+			}
+			for _, so := range game.spaceObjects {
+				if want := game.worldToScreen(so.position); so.scaledPosition != want {
+					t.Errorf("%q is drawn at %v, want %v", so.name, so.scaledPosition, want)
+				}
+			}
+		})
+	}
+
+	// a broken camera fails the run as well
+	game := NewGame()
+	game.camera.zoom = math.NaN()
+	if err := runSmoke(game, io.Discard); err == nil {
+		t.Error("smoke run passed with a zoom that is not a number")
+	}
+
+	// a position that is not a number fails the run
+	game = NewGame()
+	game.spaceObjects[0].position = Vector{math.NaN(), 0}
+	if err := runSmoke(game, io.Discard); err == nil {
+		t.Error("smoke run passed with a position that is not a number")
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// returns the hill radius a * (m / 3M)^(1/3) of a body of mass m orbiting a body of mass M at the semi-major axis a
+// inside it the gravity of the smaller body dominates the tidal pull of the larger one
+func hillRadius(semiMajorAxis, mass, centralMass float64) float64 {
+	return semiMajorAxis * math.Cbrt(mass/(3*centralMass))
+}
+
+// returns the hill radius of the planet around the central body
+// for an unbound planet the current distance takes the place of the semi-major axis
+func (g *Game) planetHillRadius(planet, central *SpaceObject) float64 {
+	r := planet.position.Translate(-central.position.X, -central.position.Y)
+	v := planet.velocity.Translate(-central.velocity.X, -central.velocity.Y)
+	a := computeOrbitalElements(r, v, g.config.gravitationalConstant()*central.mass).semiMajorAxis
+	if a <= 0 {
+		a = r.Length()
+	}
+	return hillRadius(a, planet.mass, central.mass)
+}
+
+// draws the hill sphere of every planet around the central body as a thin circle in the color of the planet
+func (g *Game) drawHillSpheres(screen *ebiten.Image) {
+	central := g.centralBody()
+	if central == nil {
+		return
+	}
+	for _, so := range g.spaceObjects {
+		if so == central || so.isSpacecraft {
+			continue
+		}
+		radius := g.worldLengthToScreen(g.planetHillRadius(so, central))
+		center := g.worldToScreen(so.position)
+		vector.StrokeCircle(screen, float32(center.X), float32(center.Y), float32(radius), 1, so.color, true)
+	}
+}
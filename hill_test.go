@@ -0,0 +1,37 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHillRadius(t *testing.T) {
+	tests := []struct {
+		name                 string
+		a, mass, centralMass float64
+		want                 float64
+	}{
+		// m / 3M = 1/1000, so the radius is a tenth of the semi-major axis
+		{"mass ratio 3/1000", 1e11, 3, 1000, 1e10},
+		{"mass ratio 3/8", 1e11, 3, 8, 5e10},
+		// the earth around the sun, about 1.5 million km
+		{"earth", 1.496e11, 5.972e24, 1.989e30, 1.4964e9},
+	}
+	for _, test := range tests {
+		if got := hillRadius(test.a, test.mass, test.centralMass); math.Abs(got/test.want-1) > 1e-4 {
+			t.Errorf("%s: hill radius is %v m, want %v", test.name, got, test.want)
+		}
+	}
+
+	// on a circular orbit the semi-major axis is the distance, an unbound planet uses its distance as well
+	g := circularOrbitGame(testOrbitRadius)
+	star, planet := g.spaceObjects[0], g.spaceObjects[1]
+	planet.isSpacecraft = false
+	planet.mass = 3e-3 * testStarMass
+	for _, speed := range []float64{planet.velocity.Y, 2 * g.config.EscapeVelocity(testStarMass, testOrbitRadius)} {
+		planet.velocity = Vector{0, speed}
+		if got := g.planetHillRadius(planet, star); math.Abs(got/(testOrbitRadius/10)-1) > 1e-6 {
+			t.Errorf("hill radius at %v m/s is %v m, want %v", speed, got, testOrbitRadius/10)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	hodographLength int     = 500 // number of recent velocities the hodograph shows
+	hodographSize   float32 = 160 // width and height of the hodograph panel in pixel
+	hodographMargin float32 = 10  // distance of the panel to the corner of the viewport in pixel
+)
+
+var (
+	hodographColor           = color.RGBA{120, 220, 255, 255}
+	hodographBackgroundColor = color.RGBA{0, 0, 0, 160}
+	hodographAxisColor       = color.RGBA{100, 100, 100, 255}
+)
+
+// remembers the velocity of the spacecraft relative to its dominant body for the hodograph
+// a keplerian orbit traces a circle in velocity space, any other shape shows perturbations
+func (g *Game) recordHodograph() {
+	craft, body := g.spacecraftAndDominantBody()
+	if craft == nil || body == nil {
+		return
+	}
+	g.hodograph = append(g.hodograph, craft.velocity.Translate(-body.velocity.X, -body.velocity.Y))
+	if len(g.hodograph) > hodographLength {
+		g.hodograph = g.hodograph[len(g.hodograph)-hodographLength:]
+	}
+}
+
+// draws the recorded velocities as a curve in a panel at the bottom right of the viewport
+// the origin is the center of the panel and the fastest recorded velocity reaches its edge
+func (g *Game) drawHodograph(screen *ebiten.Image) {
+	viewport := g.viewport()
+	left := float32(viewport.Max.X) - hodographSize - hodographMargin
+	top := float32(viewport.Max.Y) - hodographSize - hodographMargin
+	cx, cy := left+hodographSize/2, top+hodographSize/2
+
+	vector.DrawFilledRect(screen, left, top, hodographSize, hodographSize, hodographBackgroundColor, false)
+	vector.StrokeLine(screen, left, cy, left+hodographSize, cy, 1, hodographAxisColor, false)
+	vector.StrokeLine(screen, cx, top, cx, top+hodographSize, 1, hodographAxisColor, false)
+
+	maxSpeed := 0.0
+	for _, v := range g.hodograph {
+		maxSpeed = math.Max(maxSpeed, v.Length())
+	}
+	if maxSpeed == 0 {
+		return
+	}
+
+	// screen y points down, velocities up
+	scale := float64(hodographSize/2) / maxSpeed
+	for i := 1; i < len(g.hodograph); i++ {
+		from, to := g.hodograph[i-1], g.hodograph[i]
+		vector.StrokeLine(screen,
+			cx+float32(from.X*scale), cy-float32(from.Y*scale),
+			cx+float32(to.X*scale), cy-float32(to.Y*scale),
+			1, hodographColor, true)
+	}
+}
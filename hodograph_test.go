@@ -0,0 +1,29 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHodographOfAKeplerOrbitIsACircle(t *testing.T) {
+	const e = 0.5
+	g := ellipticOrbitGame(testOrbitRadius, e, 0, 0)
+	g.config.integrator = integratorRK4
+	for range 1000 {
+		g.Step()
+	}
+	if len(g.hodograph) != hodographLength {
+		t.Fatalf("recorded %d velocities, want the last %d", len(g.hodograph), hodographLength)
+	}
+
+	// with the periapsis on the x axis the velocity is mu/h (-sin nu, e + cos nu): a circle of radius mu/h
+	// around (0, e mu/h)
+	mu := g.config.gravitationalConstant() * testStarMass
+	radius := mu / math.Sqrt(mu*testOrbitRadius*(1-e*e))
+	center := Vector{0, e * radius}
+	for i, v := range g.hodograph {
+		if d := math.Sqrt(v.DistanceSquared(center)); math.Abs(d/radius-1) > 1e-4 {
+			t.Fatalf("velocity %d %v is %v m/s from the center %v, want %v", i, v, d, center, radius)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// a single line of text the user is typing, e.g. the label of a marker
+// while a text input is active, typed keys go into it instead of triggering actions
+type textInput struct {
+	prompt   string
+	value    string
+	onSubmit func(value string) // called with the typed text when enter is pressed
+}
+
+// starts a text input with the given prompt
+func (g *Game) startTextInput(prompt string, onSubmit func(value string)) {
+	g.input = &textInput{prompt: prompt, onSubmit: onSubmit}
+}
+
+// adds the typed characters to the active text input and submits or cancels it
+func (g *Game) updateTextInput() {
+	input := g.input
+
+	input.value += string(ebiten.AppendInputChars(nil))
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(input.value) > 0 {
+		runes := []rune(input.value)
+		input.value = string(runes[:len(runes)-1])
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.input = nil
+		return
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		g.input = nil
+		input.onSubmit(input.value)
+	}
+}
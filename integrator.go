@@ -0,0 +1,120 @@
+package main
+
+// Integrator determines how the positions and velocities are advanced by one time step
+type Integrator int
+
+const (
+	integratorEuler    Integrator = iota // semi-implicit euler: velocities first, then positions with the new velocities
+	integratorRK4                        // classic fourth order runge-kutta, four force evaluations per step
+	integratorLeapfrog                   // kick-drift-kick leapfrog, symplectic and second order
+)
+
+func (i Integrator) String() string {
+	switch i {
+	case integratorRK4:
+		return "RK4"
+	case integratorLeapfrog:
+		return "leapfrog"
+	default:
+		return "euler"
+	}
+}
+
+// returns the next integrator, wrapping around after the last one
+func (i Integrator) next() Integrator {
+	if i == integratorLeapfrog {
+		return integratorEuler
+	}
+	return i + 1
+}
+
+// advances the spaceobjects by a step of h seconds using the semi-implicit euler method
+func stepEuler(spaceObjects []*SpaceObject, springs []Spring, config SimConfig, h float64) {
+
+	// update the velocity of every spaceobject with the forces of all other objects
+	// the forces only depend on the positions, so they are all computed before any velocity changes
+	for i, force := range netForces(spaceObjects, springs, config) {
+		spaceObjects[i].UpdateVelocity(force, h)
+	}
+
+	// after updating the velocites, we now update all positions
+	for _, so := range spaceObjects {
+		// Update position using the spaceobjects velocity
+		so.UpdatePosition(h)
+	}
+}
+
+// returns the accelerations of all spaceobjects at their current positions
+func accelerations(spaceObjects []*SpaceObject, springs []Spring, config SimConfig) []Vector {
+	a := netForces(spaceObjects, springs, config)
+	for i, so := range spaceObjects {
+		a[i] = Vector{a[i].X / so.mass, a[i].Y / so.mass}
+	}
+	return a
+}
+
+// advances the spaceobjects by a step of h seconds using the kick-drift-kick leapfrog
+// the velocity is only half a step ahead inside this function, so it is synchronized with the position
+// after every step and switching to or from leapfrog needs no extra state
+func stepLeapfrog(spaceObjects []*SpaceObject, springs []Spring, config SimConfig, h float64) {
+	// kick: half a step with the accelerations at the start
+	for i, a := range accelerations(spaceObjects, springs, config) {
+		so := spaceObjects[i]
+		so.velocity = so.velocity.Translate(a.X*h/2, a.Y*h/2)
+	}
+
+	// drift: a full step with the half step velocities
+	for _, so := range spaceObjects {
+		so.UpdatePosition(h)
+	}
+
+	// kick: the second half step with the accelerations at the new positions
+	for i, a := range accelerations(spaceObjects, springs, config) {
+		so := spaceObjects[i]
+		so.velocity = so.velocity.Translate(a.X*h/2, a.Y*h/2)
+	}
+}
+
+// advances the spaceobjects by a step of h seconds using the classic fourth order runge-kutta method
+// the intermediate states are evaluated on copies, so only the final result touches the spaceobjects
+func stepRK4(spaceObjects []*SpaceObject, springs []Spring, config SimConfig, h float64) {
+	n := len(spaceObjects)
+	stage := make([]*SpaceObject, n)
+	for i, so := range spaceObjects {
+		copied := *so
+		stage[i] = &copied
+	}
+
+	// returns the velocities and accelerations at the state advanced by s along the given derivatives
+	evaluate := func(s float64, dx, dv []Vector) ([]Vector, []Vector) {
+		for i, so := range spaceObjects {
+			stage[i].position = so.position
+			stage[i].velocity = so.velocity
+			if dx != nil {
+				stage[i].position = so.position.Translate(dx[i].X*s, dx[i].Y*s)
+				stage[i].velocity = so.velocity.Translate(dv[i].X*s, dv[i].Y*s)
+			}
+		}
+		v := make([]Vector, n)
+		for i := range stage {
+			v[i] = stage[i].velocity
+		}
+		return v, accelerations(stage, springs, config)
+	}
+
+	v1, a1 := evaluate(0, nil, nil)
+	v2, a2 := evaluate(h/2, v1, a1)
+	v3, a3 := evaluate(h/2, v2, a2)
+	v4, a4 := evaluate(h, v3, a3)
+
+	for i, so := range spaceObjects {
+		so.position = so.position.Translate(
+			h/6*(v1[i].X+2*v2[i].X+2*v3[i].X+v4[i].X),
+			h/6*(v1[i].Y+2*v2[i].Y+2*v3[i].Y+v4[i].Y),
+		)
+		so.velocity = so.velocity.Translate(
+			h/6*(a1[i].X+2*a2[i].X+2*a3[i].X+a4[i].X),
+			h/6*(a1[i].Y+2*a2[i].Y+2*a3[i].Y+a4[i].Y),
+		)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegratorsKeepTheTwoBodyEnergy(t *testing.T) {
+	for _, integrator := range []Integrator{integratorEuler, integratorRK4, integratorLeapfrog} {
+		t.Run(integrator.String(), func(t *testing.T) {
+			g := circularOrbitGame(testOrbitRadius)
+			g.config.integrator = integrator
+			start := g.TotalEnergy()
+
+			// about one revolution
+			for range 730 {
+				g.Step()
+				if drift := math.Abs(g.TotalEnergy()/start - 1); drift > 1e-3 {
+					t.Fatalf("energy drifted by %v after %v s", drift, g.time)
+				}
+			}
+			if r := math.Sqrt(g.spaceObjects[1].position.DistanceSquared(g.spaceObjects[0].position)); math.Abs(r/testOrbitRadius-1) > 1e-3 {
+				t.Errorf("orbit radius is %v m after a revolution, want %v", r, testOrbitRadius)
+			}
+		})
+	}
+}
+
+func TestSwitchingIntegratorsKeepsTheState(t *testing.T) {
+	g := circularOrbitGame(testOrbitRadius)
+	start := g.TotalEnergy()
+	for range 20 {
+		g.Step()
+	}
+
+	// switching does not touch the state, the leapfrog velocity is synchronized at the end of every step
+	craft := *g.spaceObjects[1]
+	g.config.integrator = g.config.integrator.next().next()
+	if g.config.integrator != integratorLeapfrog {
+		t.Fatalf("cycled to %v, want leapfrog", g.config.integrator)
+	}
+	if so := g.spaceObjects[1]; so.position != craft.position || so.velocity != craft.velocity {
+		t.Errorf("switching moved the spacecraft from %v to %v", craft.position, so.position)
+	}
+
+	// a leapfrog step from the euler state lands where an euler step would, up to the difference a*dt^2/2
+	// of where the two methods take the acceleration into account
+	euler := circularOrbitGame(testOrbitRadius)
+	for range 21 {
+		euler.Step()
+	}
+	acceleration, _ := g.SpacecraftAcceleration()
+	g.Step()
+	if d := math.Sqrt(g.spaceObjects[1].position.DistanceSquared(euler.spaceObjects[1].position)); d > acceleration*dt*dt {
+		t.Errorf("leapfrog step lands %v m from the euler step", d)
+	}
+
+	for range 20 {
+		g.Step()
+	}
+	g.config.integrator = g.config.integrator.next()
+	if g.config.integrator != integratorEuler {
+		t.Fatalf("cycled to %v, want euler", g.config.integrator)
+	}
+	for range 20 {
+		g.Step()
+	}
+	if drift := math.Abs(g.TotalEnergy()/start - 1); drift > 1e-4 {
+		t.Errorf("energy drifted by %v across the switches", drift)
+	}
+}
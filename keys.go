@@ -0,0 +1,240 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// names of the actions keys can be bound to
+const (
+	actionFocus            string = "focus"
+	actionSelect           string = "select"
+	actionRecord           string = "record"
+	actionMarker           string = "marker"
+	actionExport           string = "export"
+	actionTrailColor       string = "trailColor"
+	actionZoomToFit        string = "zoomToFit"
+	actionBodyScale        string = "bodyScale"
+	actionOrbit            string = "orbit"
+	actionSave             string = "save"
+	actionLoad             string = "load"
+	actionTurnLeft         string = "turnLeft"
+	actionTurnRight        string = "turnRight"
+	actionThrust           string = "thrust"
+	actionTrail            string = "trail"
+	actionReplay           string = "replay"
+	actionScrubBack        string = "scrubBack"
+	actionScrubForward     string = "scrubForward"
+	actionHelp             string = "help"
+	actionClearTrails      string = "clearTrails"
+	actionPrediction       string = "prediction"
+	actionIntegrator       string = "integrator"
+	actionLagrange         string = "lagrange"
+	actionFreezePlanets    string = "freezePlanets"
+	actionFreezeSpacecraft string = "freezeSpacecraft"
+	actionRuler            string = "ruler"
+	actionSnapOrbit        string = "snapOrbit"
+	actionDominance        string = "dominance"
+	actionTransfer         string = "transfer"
+	actionCoordinates      string = "coordinates"
+	actionResonances       string = "resonances"
+	actionEscape           string = "escape"
+	actionPause            string = "pause"
+	actionSubsteps         string = "substeps"
+	actionIntercept        string = "intercept"
+	actionReset            string = "reset"
+	actionHalveMass        string = "halveMass"
+	actionDoubleMass       string = "doubleMass"
+	actionHill             string = "hill"
+	actionHodograph        string = "hodograph"
+	actionNudgeDown        string = "nudgeDown"
+	actionProgradeLock     string = "progradeLock"
+	actionRings            string = "rings"
+	actionPlaceNode        string = "placeNode"
+	actionExecuteNode      string = "executeNode"
+	actionEnergyPlot       string = "energyPlot"
+	actionAxes             string = "axes"
+	actionStage            string = "stage"
+	actionGoToTime         string = "goToTime"
+	actionEccentricity     string = "eccentricity"
+)
+
+// what the actions do, shown in the help overlay
+var actionDescriptions = map[string]string{
+	actionFocus:            "cycle the focus frame",
+	actionSelect:           "cycle the selected body",
+	actionRecord:           "start or stop recording",
+	actionMarker:           "insert a marker into the recording",
+	actionExport:           "export the recording",
+	actionTrailColor:       "cycle the trail coloring",
+	actionZoomToFit:        "zoom to fit all bodies",
+	actionBodyScale:        "toggle real or artistic body sizes",
+	actionOrbit:            "toggle the spacecraft orbit",
+	actionSave:             "save the state",
+	actionLoad:             "load the saved state",
+	actionTurnLeft:         "turn the spacecraft left",
+	actionTurnRight:        "turn the spacecraft right",
+	actionThrust:           "fire the engine",
+	actionTrail:            "toggle the trail of the selected body",
+	actionReplay:           "start or stop the replay",
+	actionScrubBack:        "jump back in the replay",
+	actionScrubForward:     "jump forward in the replay",
+	actionHelp:             "show this help, turn its page or hide it",
+	actionClearTrails:      "clear the trails of all bodies",
+	actionPrediction:       "toggle the predicted trajectory",
+	actionIntegrator:       "cycle the integrator (euler, RK4, leapfrog)",
+	actionLagrange:         "toggle the lagrange points",
+	actionFreezePlanets:    "freeze or unfreeze the planets",
+	actionFreezeSpacecraft: "freeze or unfreeze the spacecraft",
+	actionRuler:            "toggle the ruler, clicks measure and right click clears",
+	actionSnapOrbit:        "put the spacecraft on a circular orbit around its dominant body",
+	actionDominance:        "toggle the tint showing which planet pulls strongest",
+	actionTransfer:         "plan a hohmann transfer to a circular orbit",
+	actionCoordinates:      "toggle the coordinates under the cursor",
+	actionResonances:       "toggle the list of orbital resonances",
+	actionEscape:           "put the spacecraft on an escape trajectory",
+	actionPause:            "pause or resume the simulation",
+	actionSubsteps:         "show the substeps of the last step",
+	actionIntercept:        "plan an intercept of the selected planet, press again to burn",
+	actionReset:            "restart the scene",
+	actionHalveMass:        "halve the mass of the selected object",
+	actionDoubleMass:       "double the mass of the selected object",
+	actionHill:             "show the hill spheres of the planets",
+	actionHodograph:        "show the velocity history of the spacecraft as a hodograph",
+	actionNudgeDown:        "move the selected body down while paused, the other arrow keys move it the other ways",
+	actionProgradeLock:     "draw the spacecraft pointing prograde instead of along its heading",
+	actionRings:            "show distance rings around the selected object",
+	actionPlaceNode:        "place a maneuver node on the prediction under the cursor",
+	actionExecuteNode:      "do the burn of the next maneuver node now",
+	actionEnergyPlot:       "show the total energy over time with the initial energy marked",
+	actionAxes:             "show the x and y axes through the origin",
+	actionStage:            "drop a stage of the spacecraft",
+	actionGoToTime:         "jump the replay to a typed time and redraw the trails up to it",
+	actionEccentricity:     "show the eccentricity vector of the spacecraft, pointing to the periapsis",
+}
+
+// KeyBindings maps action names to the key triggering them
+type KeyBindings map[string]ebiten.Key
+
+// returns the default key bindings
+func DefaultKeyBindings() KeyBindings {
+	return KeyBindings{
+		actionFocus:            ebiten.KeyTab,
+		actionSelect:           ebiten.KeyT,
+		actionRecord:           ebiten.KeyR,
+		actionMarker:           ebiten.KeyM,
+		actionExport:           ebiten.KeyE,
+		actionTrailColor:       ebiten.KeyC,
+		actionZoomToFit:        ebiten.KeyF,
+		actionBodyScale:        ebiten.KeyV,
+		actionOrbit:            ebiten.KeyO,
+		actionSave:             ebiten.KeyF5,
+		actionLoad:             ebiten.KeyF9,
+		actionTurnLeft:         ebiten.KeyLeft,
+		actionTurnRight:        ebiten.KeyRight,
+		actionThrust:           ebiten.KeyUp,
+		actionTrail:            ebiten.KeyL,
+		actionReplay:           ebiten.KeyP,
+		actionScrubBack:        ebiten.KeyComma,
+		actionScrubForward:     ebiten.KeyPeriod,
+		actionHelp:             ebiten.KeyH,
+		actionClearTrails:      ebiten.KeyX,
+		actionPrediction:       ebiten.KeyG,
+		actionIntegrator:       ebiten.KeyI,
+		actionLagrange:         ebiten.KeyK,
+		actionFreezePlanets:    ebiten.KeyJ,
+		actionFreezeSpacecraft: ebiten.KeyU,
+		actionRuler:            ebiten.KeyQ,
+		actionSnapOrbit:        ebiten.KeyN,
+		actionDominance:        ebiten.KeyZ,
+		actionTransfer:         ebiten.KeyY,
+		actionCoordinates:      ebiten.KeyW,
+		actionResonances:       ebiten.KeyB,
+		actionEscape:           ebiten.KeyA,
+		actionPause:            ebiten.KeySpace,
+		actionSubsteps:         ebiten.KeyS,
+		actionIntercept:        ebiten.KeyD,
+		actionReset:            ebiten.KeyHome,
+		actionHalveMass:        ebiten.KeyBracketLeft,
+		actionDoubleMass:       ebiten.KeyBracketRight,
+		actionHill:             ebiten.KeyF2,
+		actionHodograph:        ebiten.KeyF3,
+		actionNudgeDown:        ebiten.KeyDown,
+		actionProgradeLock:     ebiten.KeyF4,
+		actionRings:            ebiten.KeyF6,
+		actionPlaceNode:        ebiten.KeyF7,
+		actionExecuteNode:      ebiten.KeyF8,
+		actionEnergyPlot:       ebiten.KeyF10,
+		actionAxes:             ebiten.KeyDigit0,
+		actionStage:            ebiten.KeyMinus,
+		actionGoToTime:         ebiten.KeyEnd,
+		actionEccentricity:     ebiten.KeyEqual,
+	}
+}
+
+// returns the default key bindings overridden by the ones in the given json file
+// the file maps action names to key names, e.g. {"thrust": "W"}
+func LoadKeyBindings(path string) (KeyBindings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var overrides map[string]ebiten.Key
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("reading key bindings from %s: %w", path, err)
+	}
+
+	bindings := DefaultKeyBindings()
+	for action, key := range overrides {
+		if _, ok := bindings[action]; !ok {
+			return nil, fmt.Errorf("unknown action %q in %s", action, path)
+		}
+		bindings[action] = key
+	}
+
+	if err := bindings.Validate(); err != nil {
+		return nil, fmt.Errorf("key bindings in %s: %w", path, err)
+	}
+	return bindings, nil
+}
+
+// returns the action names in alphabetical order
+func (b KeyBindings) actions() []string {
+	actions := make([]string, 0, len(b))
+	for action := range b {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+// returns an error if a key is bound to more than one action
+func (b KeyBindings) Validate() error {
+	boundTo := map[ebiten.Key]string{}
+	for _, action := range b.actions() {
+		key := b[action]
+		if other, ok := boundTo[key]; ok {
+			return fmt.Errorf("key %s is bound to both %q and %q", key, other, action)
+		}
+		boundTo[key] = action
+	}
+	return nil
+}
+
+// returns true in the frame the key of the action is pressed
+func (b KeyBindings) JustPressed(action string) bool {
+	key, ok := b[action]
+	return ok && inpututil.IsKeyJustPressed(key)
+}
+
+// returns true as long as the key of the action is held down
+func (b KeyBindings) Pressed(action string) bool {
+	key, ok := b[action]
+	return ok && ebiten.IsKeyPressed(key)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestDefaultKeyBindings(t *testing.T) {
+	bindings := DefaultKeyBindings()
+	if err := bindings.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for action, key := range map[string]ebiten.Key{
+		actionThrust: ebiten.KeyUp,
+		actionPause:  ebiten.KeySpace,
+		actionSave:   ebiten.KeyF5,
+		actionLoad:   ebiten.KeyF9,
+		actionHelp:   ebiten.KeyH,
+	} {
+		if bindings[action] != key {
+			t.Errorf("%q is bound to %s, want %s", action, bindings[action], key)
+		}
+	}
+
+	// the help overlay lists every action
+	for _, action := range bindings.actions() {
+		if actionDescriptions[action] == "" {
+			t.Errorf("%q has no description", action)
+		}
+	}
+}
+
+func TestValidateRejectsDuplicateBindings(t *testing.T) {
+	bindings := DefaultKeyBindings()
+	bindings[actionThrust] = bindings[actionPause]
+	err := bindings.Validate()
+	if err == nil || !strings.Contains(err.Error(), actionThrust) || !strings.Contains(err.Error(), actionPause) {
+		t.Errorf("Validate() = %v, want an error naming %q and %q", err, actionThrust, actionPause)
+	}
+}
+
+func TestLoadKeyBindings(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string // part of the error, empty if loading succeeds
+	}{
+		{"override", `{"thrust": "F11", "escape": "F1"}`, ""},
+		{"unknown action", `{"warp": "W"}`, "unknown action"},
+		{"duplicate", `{"thrust": "Space"}`, "bound to both"},
+		{"malformed", `{"thrust": `, "reading key bindings"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "keys.json")
+			if err := os.WriteFile(path, []byte(test.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			bindings, err := LoadKeyBindings(path)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("LoadKeyBindings() = %v, want an error containing %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bindings[actionThrust] != ebiten.KeyF11 || bindings[actionEscape] != ebiten.KeyF1 {
+				t.Errorf("thrust is %s and escape %s, want F11 and F1", bindings[actionThrust], bindings[actionEscape])
+			}
+			if bindings[actionPause] != ebiten.KeySpace {
+				t.Errorf("pause is %s, the default Space should be kept", bindings[actionPause])
+			}
+		})
+	}
+}
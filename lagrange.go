@@ -0,0 +1,112 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	lagrangeIterations int     = 50    // maximum number of newton steps for a collinear lagrange point
+	lagrangeTolerance  float64 = 1e-12 // newton steps smaller than this fraction of the separation end the iteration
+	lagrangeMarkerSize float32 = 4     // half the length of the cross marking a lagrange point in pixel
+)
+
+var lagrangeColor = color.RGBA{128, 255, 128, 200}
+
+// returns the position along the line from the primary to the secondary (in units of their separation,
+// relative to the barycenter) where gravity and the centrifugal force of the rotating frame cancel out
+// mu is the mass fraction m2 / (m1 + m2) of the secondary and guess the starting point of the newton iteration
+func collinearLagrangePoint(mu, guess float64) float64 {
+	x := guess
+	for i := 0; i < lagrangeIterations; i++ {
+		d1 := math.Abs(x + mu)     // distance to the primary at -mu
+		d2 := math.Abs(x - 1 + mu) // distance to the secondary at 1-mu
+		f := x - (1-mu)*(x+mu)/(d1*d1*d1) - mu*(x-1+mu)/(d2*d2*d2)
+		df := 1 + 2*(1-mu)/(d1*d1*d1) + 2*mu/(d2*d2*d2)
+		step := f / df
+		x -= step
+		if math.Abs(step) < lagrangeTolerance {
+			break
+		}
+	}
+	return x
+}
+
+// returns the lagrange points L1 to L5 of the secondary orbiting the primary
+// the collinear points L1 to L3 are found numerically starting from the usual approximations,
+// L4 and L5 are the apexes of the equilateral triangles over the two bodies, L4 leading the secondary
+func LagrangePoints(primary, secondary *SpaceObject) [5]Vector {
+	mu := secondary.mass / (primary.mass + secondary.mass)
+	offset := secondary.position.Translate(-primary.position.X, -primary.position.Y)
+	separation := offset.Length()
+	direction := offset.Normalize()
+	barycenter := primary.position.Translate(mu*offset.X, mu*offset.Y)
+
+	// points on the axis through both bodies, relative to the barycenter
+	onAxis := func(x float64) Vector {
+		return barycenter.Translate(x*separation*direction.X, x*separation*direction.Y)
+	}
+	hill := math.Cbrt(mu / 3)
+
+	// L4 leads in the direction of motion, so it lies on the other side for clockwise orbits
+	relativeVelocity := secondary.velocity.Translate(-primary.velocity.X, -primary.velocity.Y)
+	angle := math.Pi / 3
+	if offset.X*relativeVelocity.Y-offset.Y*relativeVelocity.X < 0 {
+		angle = -angle
+	}
+	apex := func(angle float64) Vector {
+		cos, sin := math.Cos(angle), math.Sin(angle)
+		rotated := Vector{direction.X*cos - direction.Y*sin, direction.X*sin + direction.Y*cos}
+		return primary.position.Translate(separation*rotated.X, separation*rotated.Y)
+	}
+
+	return [5]Vector{
+		onAxis(collinearLagrangePoint(mu, 1-mu-hill)),
+		onAxis(collinearLagrangePoint(mu, 1-mu+hill)),
+		onAxis(collinearLagrangePoint(mu, -1-5*mu/12)),
+		apex(angle),
+		apex(-angle),
+	}
+}
+
+// returns the pair of bodies the lagrange points are shown for: the selected planet and the body it orbits,
+// or the two heaviest planets if no planet is selected; nil if there is no such pair
+func (g *Game) lagrangePair() (primary, secondary *SpaceObject) {
+	if so := g.selectedObject(); so != nil && !so.isSpacecraft {
+		if body := g.DominantBody(so); body != nil {
+			return body, so
+		}
+	}
+
+	for _, so := range g.spaceObjects {
+		switch {
+		case so.isSpacecraft:
+		case primary == nil || so.mass > primary.mass:
+			primary, secondary = so, primary
+		case secondary == nil || so.mass > secondary.mass:
+			secondary = so
+		}
+	}
+	if secondary == nil {
+		return nil, nil
+	}
+	return primary, secondary
+}
+
+// draws a cross on each lagrange point of the current pair of bodies
+func (g *Game) drawLagrangePoints(screen *ebiten.Image) {
+	primary, secondary := g.lagrangePair()
+	if primary == nil {
+		return
+	}
+
+	for _, point := range LagrangePoints(primary, secondary) {
+		p := g.worldToScreen(point)
+		x, y := float32(p.X), float32(p.Y)
+		vector.StrokeLine(screen, x-lagrangeMarkerSize, y-lagrangeMarkerSize, x+lagrangeMarkerSize, y+lagrangeMarkerSize, 1, lagrangeColor, true)
+		vector.StrokeLine(screen, x-lagrangeMarkerSize, y+lagrangeMarkerSize, x+lagrangeMarkerSize, y-lagrangeMarkerSize, 1, lagrangeColor, true)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLagrangePoints(t *testing.T) {
+	// an earth sized planet on a counterclockwise orbit, the pair rotated away from the axes
+	const separation = testOrbitRadius
+	direction := Vector{math.Cos(0.7), math.Sin(0.7)}
+	primary := &SpaceObject{name: "star", mass: testStarMass, position: Vector{3e9, -2e9}}
+	secondary := &SpaceObject{
+		name:     "planet",
+		mass:     6e24,
+		position: primary.position.Translate(separation*direction.X, separation*direction.Y),
+		velocity: Vector{-direction.Y * 3e4, direction.X * 3e4},
+	}
+	points := LagrangePoints(primary, secondary)
+
+	distance := func(a, b Vector) float64 { return math.Sqrt(a.DistanceSquared(b)) }
+	relative := func(p Vector) Vector { return p.Translate(-primary.position.X, -primary.position.Y) }
+
+	// L4 and L5 form equilateral triangles with the two bodies
+	for i, name := range []string{"L4", "L5"} {
+		p := points[3+i]
+		if d := distance(p, primary.position); math.Abs(d/separation-1) > 1e-12 {
+			t.Errorf("%s is %v m from the primary, want %v", name, d, separation)
+		}
+		if d := distance(p, secondary.position); math.Abs(d/separation-1) > 1e-12 {
+			t.Errorf("%s is %v m from the secondary, want %v", name, d, separation)
+		}
+	}
+
+	// L4 leads the counterclockwise secondary, L5 trails it
+	offset := relative(secondary.position)
+	for i, want := range []float64{1, -1} {
+		p := relative(points[3+i])
+		if side := offset.X*p.Y - offset.Y*p.X; math.Signbit(side) != math.Signbit(want) {
+			t.Errorf("L%d is on the wrong side of the secondary", 4+i)
+		}
+	}
+
+	// L1 and L2 lie about one hill radius inside and outside the secondary, L3 opposite of it
+	hill := separation * math.Cbrt(secondary.mass/(3*primary.mass))
+	along := func(p Vector) float64 { return relative(p).Dot(direction) }
+	for i, want := range []float64{separation - hill, separation + hill, -separation} {
+		p := points[i]
+		if across := math.Abs(relative(p).Dot(Vector{-direction.Y, direction.X})); across > 1e-6*separation {
+			t.Errorf("L%d is %v m off the axis through both bodies", i+1, across)
+		}
+		if got := along(p); math.Abs(got-want) > 0.02*hill {
+			t.Errorf("L%d is %v m from the primary along the axis, want about %v", i+1, got, want)
+		}
+	}
+}
+
+func TestCollinearLagrangePointBalancesTheForces(t *testing.T) {
+	mu := 0.01
+	hill := math.Cbrt(mu / 3)
+	for _, guess := range []float64{1 - mu - hill, 1 - mu + hill, -1 - 5*mu/12} {
+		x := collinearLagrangePoint(mu, guess)
+		d1 := math.Abs(x + mu)
+		d2 := math.Abs(x - 1 + mu)
+		// gravity of both bodies and the centrifugal force of the rotating frame cancel out
+		if f := x - (1-mu)*(x+mu)/(d1*d1*d1) - mu*(x-1+mu)/(d2*d2*d2); math.Abs(f) > 1e-12 {
+			t.Errorf("net force at the point %v found from %v is %v", x, guess, f)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	lambertIterations int = 200 // bisection steps of the lambert solver
+	interceptPoints   int = 200 // points the drawn intercept trajectory consists of
+	interceptSubsteps int = 20  // integration steps between two points of the intercept trajectory
+)
+
+var interceptColor = color.RGBA{255, 120, 255, 200}
+
+// returns the stumpff functions C(z) and S(z) of the universal variable formulation
+func stumpff(z float64) (c, s float64) {
+	switch {
+	case z > 0:
+		sz := math.Sqrt(z)
+		return (1 - math.Cos(sz)) / z, (sz - math.Sin(sz)) / (sz * sz * sz)
+	case z < 0:
+		sz := math.Sqrt(-z)
+		return (math.Cosh(sz) - 1) / -z, (math.Sinh(sz) - sz) / (sz * sz * sz)
+	}
+	return 1.0 / 2, 1.0 / 6
+}
+
+// solves lambert's problem in the plane: returns the velocities at r1 and r2 of the orbit around a body with
+// the gravitational parameter mu that goes from r1 to r2 in the time tof, both positions relative to the body
+// the orbit goes counterclockwise unless clockwise is set and less than one revolution around the body
+// uses the universal variable method, with bisection on z since the time of flight grows monotonically with it
+func lambertSolve(r1, r2 Vector, tof, mu float64, clockwise bool) (v1, v2 Vector, err error) {
+	l1, l2 := r1.Length(), r2.Length()
+	if l1 == 0 || l2 == 0 || tof <= 0 || mu <= 0 {
+		return Vector{}, Vector{}, errors.New("lambert needs two positions away from the body and a positive time")
+	}
+
+	// angle swept from r1 to r2 in the direction of the orbit
+	dtheta := math.Acos(math.Max(-1, math.Min(1, r1.Dot(r2)/(l1*l2))))
+	cross := r1.X*r2.Y - r1.Y*r2.X
+	if (cross < 0) != clockwise {
+		dtheta = 2*math.Pi - dtheta
+	}
+	if math.Abs(1-math.Cos(dtheta)) < 1e-12 {
+		return Vector{}, Vector{}, errors.New("lambert has no unique solution for positions in line with the body")
+	}
+	a := math.Sin(dtheta) * math.Sqrt(l1*l2/(1-math.Cos(dtheta)))
+
+	y := func(z float64) float64 {
+		c, s := stumpff(z)
+		return l1 + l2 + a*(z*s-1)/math.Sqrt(c)
+	}
+	timeOfFlight := func(z float64) float64 {
+		c, s := stumpff(z)
+		yz := y(z)
+		return (math.Pow(yz/c, 1.5)*s + a*math.Sqrt(yz)) / math.Sqrt(mu)
+	}
+
+	// z is below (2 pi)^2 for less than one revolution; the lower bound has to keep y positive
+	low, high := -4*math.Pi*math.Pi, 4*math.Pi*math.Pi*(1-1e-9)
+	for y(low) < 0 {
+		low += (high - low) / 100
+	}
+	if timeOfFlight(low) > tof || timeOfFlight(high) < tof {
+		return Vector{}, Vector{}, fmt.Errorf("no single revolution transfer takes %s", formatDuration(tof))
+	}
+	for k := 0; k < lambertIterations; k++ {
+		z := (low + high) / 2
+		if timeOfFlight(z) < tof {
+			low = z
+		} else {
+			high = z
+		}
+	}
+
+	// lagrange coefficients of the solution
+	yz := y((low + high) / 2)
+	f := 1 - yz/l1
+	g := a * math.Sqrt(yz/mu)
+	gdot := 1 - yz/l2
+	v1 = Vector{(r2.X - f*r1.X) / g, (r2.Y - f*r1.Y) / g}
+	v2 = Vector{(gdot*r2.X - r1.X) / g, (gdot*r2.Y - r1.Y) / g}
+	return v1, v2, nil
+}
+
+// a solved intercept of a planet, waiting for its burn
+// the burn is solved for the state at the departure, so the plan is only valid until the time moves on
+type interceptPlan struct {
+	body      *SpaceObject // body the transfer goes around
+	target    *SpaceObject // planet that is intercepted
+	dv        Vector       // velocity change the burn needs in m/s
+	departure float64      // simulated time the burn is solved for in s
+	arrival   float64      // simulated time the spacecraft arrives at the planet in s
+	path      []Vector     // positions of the transfer relative to the body in m
+	active    bool
+}
+
+// returns the index of the given spaceobject, -1 if it is not part of the simulation
+func (g *Game) objectIndex(so *SpaceObject) int {
+	for i, other := range g.spaceObjects {
+		if other == so {
+			return i
+		}
+	}
+	return -1
+}
+
+// plans an intercept of the selected planet after the given time: the future position of the planet
+// comes from the prediction, the transfer only takes the dominant body of the spacecraft into account
+func (g *Game) solveIntercept(tof float64) error {
+	craft, body := g.spacecraftAndDominantBody()
+	target := g.selectedObject()
+	if craft == nil || body == nil {
+		return errors.New("there is no spacecraft orbiting a body")
+	}
+	if target == nil || target.isSpacecraft || target == body {
+		return errors.New("select the planet to intercept first")
+	}
+
+	steps := max(int(math.Round(tof/dt)), 1)
+	trajectory := g.predictTrajectory(steps, nil)
+	last := trajectory[steps-1]
+	bodyIndex, targetIndex := g.objectIndex(body), g.objectIndex(target)
+	arrival := last[targetIndex].Translate(-last[bodyIndex].X, -last[bodyIndex].Y)
+
+	// the transfer keeps the sense of rotation of the current orbit
+	r := craft.position.Translate(-body.position.X, -body.position.Y)
+	v := craft.velocity.Translate(-body.velocity.X, -body.velocity.Y)
+	clockwise := r.X*v.Y-r.Y*v.X < 0
+
+	mu := g.config.gravitationalConstant() * body.mass
+	tof = float64(steps) * dt
+	v1, _, err := lambertSolve(r, arrival, tof, mu, clockwise)
+	if err != nil {
+		return err
+	}
+
+	g.intercept = interceptPlan{
+		body:      body,
+		target:    target,
+		dv:        v1.Translate(-v.X, -v.Y),
+		departure: g.time,
+		arrival:   g.time + tof,
+		path:      twoBodyPath(r, v1, mu, tof),
+		active:    true,
+	}
+	return nil
+}
+
+// returns the positions along the orbit starting at r with velocity v around a body with the parameter mu
+// integrated with leapfrog for the duration of the transfer
+func twoBodyPath(r, v Vector, mu, duration float64) []Vector {
+	acceleration := func(p Vector) Vector {
+		d := p.Length()
+		k := -mu / (d * d * d)
+		return Vector{p.X * k, p.Y * k}
+	}
+
+	h := duration / float64(interceptPoints*interceptSubsteps)
+	path := []Vector{r}
+	for i := 0; i < interceptPoints; i++ {
+		for k := 0; k < interceptSubsteps; k++ {
+			a := acceleration(r)
+			v = v.Translate(a.X*h/2, a.Y*h/2)
+			r = r.Translate(v.X*h, v.Y*h)
+			a = acceleration(r)
+			v = v.Translate(a.X*h/2, a.Y*h/2)
+		}
+		path = append(path, r)
+	}
+	return path
+}
+
+// asks for the transfer time of an intercept of the selected planet and solves it
+// with an intercept planned, the key does its burn instead
+func (g *Game) planIntercept() {
+	if g.intercept.active {
+		g.burnIntercept()
+		return
+	}
+	g.startTextInput("Intercept the selected planet in (days): ", func(value string) {
+		days, err := strconv.ParseFloat(value, 64)
+		if err == nil {
+			err = g.solveIntercept(days * secondsPerDay)
+		}
+		if err != nil {
+			g.notify("intercept failed: " + err.Error())
+		}
+	})
+}
+
+// returns true if an intercept is planned for the current time, so its burn would still hit the planet
+func (g *Game) interceptPending() bool {
+	return g.intercept.active && g.time == g.intercept.departure
+}
+
+// drops the planned intercept once the time moved past its departure, its burn would miss the planet now
+// called after every change of the time, the intercept has to be planned again from the new state
+func (g *Game) expireIntercept() {
+	if g.intercept.active && !g.interceptPending() {
+		g.intercept.active = false
+		g.notify("intercept dropped, the time moved past its burn")
+	}
+}
+
+// changes the velocity of the spacecraft by the planned intercept burn
+func (g *Game) burnIntercept() {
+	g.expireIntercept()
+	if !g.intercept.active {
+		return
+	}
+	g.intercept.active = false
+	index := g.spacecraftIndex()
+	if index < 0 || !g.containsObject(g.intercept.body) {
+		return
+	}
+	if err := g.spaceObjects[index].applyDeltaV(g.intercept.dv); err != nil {
+		g.notify("intercept failed: " + err.Error())
+	}
+	g.baseline.set = false
+}
+
+// draws the planned transfer relative to the current position of the body and marks where it meets the planet
+func (g *Game) drawIntercept(screen *ebiten.Image) {
+	plan := g.intercept
+	if !g.interceptPending() || !g.containsObject(plan.body) {
+		return
+	}
+
+	var from Vector
+	for i, p := range plan.path {
+		to := g.worldToScreen(plan.body.position.Translate(p.X, p.Y))
+		if i > 0 {
+			vector.StrokeLine(screen, float32(from.X), float32(from.Y), float32(to.X), float32(to.Y), 1, interceptColor, true)
+		}
+		from = to
+	}
+	vector.StrokeCircle(screen, float32(from.X), float32(from.Y), 6, 1, interceptColor, true)
+}
+
+// returns the planned intercept as a line of the HUD, empty if there is none
+func (g *Game) interceptStatus() string {
+	if !g.interceptPending() {
+		return ""
+	}
+	return fmt.Sprintf("Intercept of %s: dv %s, arrival in %s",
+		g.intercept.target.name,
+		g.units.FormatSpeed(g.intercept.dv.Length()),
+		formatDuration(g.intercept.arrival-g.time))
+}
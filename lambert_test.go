@@ -0,0 +1,115 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLambertSolveArrivesAtTheTarget(t *testing.T) {
+	config := DefaultSimConfig()
+	mu := config.gravitationalConstant() * testStarMass
+	period := config.OrbitalPeriod(testStarMass, testOrbitRadius)
+	tests := []struct {
+		name      string
+		r1, r2    Vector
+		tof       float64
+		clockwise bool
+	}{
+		{"quarter of a circle", Vector{testOrbitRadius, 0}, Vector{0, testOrbitRadius}, period / 4, false},
+		{"outwards", Vector{testOrbitRadius, 0}, Vector{-1.5 * testOrbitRadius, 1e11}, 200 * 86400, false},
+		{"inwards the long way", Vector{2 * testOrbitRadius, 0}, Vector{0, -testOrbitRadius}, 300 * 86400, false},
+		{"clockwise", Vector{testOrbitRadius, 0}, Vector{0, 1.2 * testOrbitRadius}, 250 * 86400, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v1, v2, err := lambertSolve(test.r1, test.r2, test.tof, mu, test.clockwise)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// in the two-body field the solved velocity reaches the target after the transfer time
+			path := twoBodyPath(test.r1, v1, mu, test.tof)
+			arrival := path[len(path)-1]
+			if miss := math.Sqrt(arrival.DistanceSquared(test.r2)); miss > 1e-5*test.r2.Length() {
+				t.Errorf("arrives at %v, %v m from the target %v", arrival, miss, test.r2)
+			}
+			if clockwise := test.r1.X*v1.Y-test.r1.Y*v1.X < 0; clockwise != test.clockwise {
+				t.Errorf("transfer goes clockwise %v, want %v", clockwise, test.clockwise)
+			}
+			// both ends are on the same orbit, so they have the same specific energy
+			e1 := v1.Dot(v1)/2 - mu/test.r1.Length()
+			e2 := v2.Dot(v2)/2 - mu/test.r2.Length()
+			if math.Abs(e1-e2) > 1e-9*math.Abs(e1) {
+				t.Errorf("specific energy is %v J/kg at the start and %v at the arrival", e1, e2)
+			}
+		})
+	}
+
+	// a quarter of the circular orbit period on the circle gives the circular velocity
+	v1, _, _ := lambertSolve(tests[0].r1, tests[0].r2, tests[0].tof, mu, false)
+	if want := (Vector{0, config.CircularOrbitVelocity(testStarMass, testOrbitRadius)}); math.Sqrt(v1.DistanceSquared(want)) > 1e-6*want.Y {
+		t.Errorf("velocity of the quarter circle is %v m/s, want %v", v1, want)
+	}
+}
+
+func TestLambertSolveRejectsImpossibleTransfers(t *testing.T) {
+	mu := DefaultSimConfig().gravitationalConstant() * testStarMass
+	r := Vector{testOrbitRadius, 0}
+	tests := []struct {
+		name   string
+		r1, r2 Vector
+		tof    float64
+	}{
+		{"no time", r, Vector{0, testOrbitRadius}, 0},
+		{"at the body", Vector{}, Vector{0, testOrbitRadius}, 86400},
+		{"opposite sides", r, Vector{-testOrbitRadius, 0}, 86400},
+		{"same position", r, r, 86400},
+	}
+	for _, test := range tests {
+		if _, _, err := lambertSolve(test.r1, test.r2, test.tof, mu, false); err == nil {
+			t.Errorf("%s: transfer was solved", test.name)
+		}
+	}
+}
+
+func TestStaleInterceptIsNotBurned(t *testing.T) {
+	// a planet further out, to be intercepted in a quarter of the spacecraft's period
+	setup := func() *Game {
+		g := circularOrbitGame(testOrbitRadius)
+		craft := g.spaceObjects[1]
+		craft.fuelMass, craft.exhaustVelocity = 0.9, 1e5
+		planetRadius := 1.5 * testOrbitRadius
+		g.spaceObjects = append(g.spaceObjects, &SpaceObject{name: "planet", mass: 1, radius: 1,
+			position: Vector{0, planetRadius}, velocity: Vector{-g.config.CircularOrbitVelocity(testStarMass, planetRadius), 0}})
+		g.selected = 2
+		if err := g.solveIntercept(g.config.OrbitalPeriod(testStarMass, testOrbitRadius) / 4); err != nil {
+			t.Fatal(err)
+		}
+		return g
+	}
+
+	// confirmed right away the burn is done
+	g := setup()
+	before := g.spaceObjects[1].velocity
+	g.burnIntercept()
+	if want := before.Translate(g.intercept.dv.X, g.intercept.dv.Y); g.spaceObjects[1].velocity != want {
+		t.Errorf("spacecraft moves at %v m/s after the intercept burn, want %v", g.spaceObjects[1].velocity, want)
+	}
+
+	// a step later the burn was solved for a state that is gone, so the plan is dropped instead
+	g = setup()
+	g.Step()
+	if g.intercept.active || len(g.plannedBurns()) != 0 || g.message == "" {
+		t.Errorf("intercept is still planned %v after the time moved past its burn, message %q", g.intercept.active, g.message)
+	}
+
+	// the key is pressed before the plan was dropped, e.g. after a replay changed the time
+	g.intercept.active, g.message = true, ""
+	before = g.spaceObjects[1].velocity
+	g.burnIntercept()
+	if v := g.spaceObjects[1].velocity; v != before || g.intercept.active {
+		t.Errorf("stale intercept burn changed the velocity from %v to %v m/s", before, v)
+	}
+	if g.message == "" {
+		t.Error("dropping the stale intercept was not reported")
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// a part of the picture, the layers are drawn one after another so later layers cover earlier ones
+type renderLayer struct {
+	name    string
+	enabled bool
+	draw    func(g *Game, view *ebiten.Image)
+}
+
+// returns all layers in the default order: the dominance tint at the bottom, the help on top
+// the overlays with their own toggle key still only draw while that toggle is on
+func defaultRenderLayers() []renderLayer {
+	layers := []renderLayer{
+		{name: "dominance", draw: func(g *Game, view *ebiten.Image) {
+			if g.showDominance {
+				g.drawDominance(view)
+			}
+		}},
+		{name: "axes", draw: func(g *Game, view *ebiten.Image) {
+			if g.showAxes {
+				g.drawAxes(view)
+			}
+		}},
+		{name: "ghosts", draw: (*Game).drawGhosts},
+		// trails are drawn under the bodies, so a body is never hidden behind its own trail
+		{name: "trails", draw: (*Game).drawTrails},
+		{name: "diff", draw: (*Game).drawDiff},
+		{name: "substeps", draw: (*Game).drawSubsteps},
+		{name: "bodies", draw: (*Game).drawBodies},
+		{name: "tethers", draw: (*Game).drawTethers},
+		{name: "lagrange", draw: func(g *Game, view *ebiten.Image) {
+			if g.showLagrange {
+				g.drawLagrangePoints(view)
+			}
+		}},
+		{name: "hill", draw: func(g *Game, view *ebiten.Image) {
+			if g.showHill {
+				g.drawHillSpheres(view)
+			}
+		}},
+		{name: "rings", draw: func(g *Game, view *ebiten.Image) {
+			if g.showRings {
+				g.drawDistanceRings(view)
+			}
+		}},
+		{name: "ruler", draw: (*Game).drawRuler},
+		{name: "annotations", draw: (*Game).drawAnnotations},
+		{name: "resonances", draw: func(g *Game, view *ebiten.Image) {
+			if g.showResonances {
+				g.drawResonances(view)
+			}
+		}},
+		{name: "coordinates", draw: func(g *Game, view *ebiten.Image) {
+			if g.showCoordinates {
+				g.drawCursorCoordinates(view)
+			}
+		}},
+		{name: "orbit", draw: func(g *Game, view *ebiten.Image) {
+			if g.showOrbit {
+				g.drawOrbit(view)
+			}
+		}},
+		{name: "eccentricity", draw: func(g *Game, view *ebiten.Image) {
+			if g.showEccentricity {
+				g.drawEccentricityVector(view)
+			}
+		}},
+		{name: "prediction", draw: func(g *Game, view *ebiten.Image) {
+			if g.showPrediction {
+				g.drawPrediction(view)
+			}
+		}},
+		{name: "intercept", draw: (*Game).drawIntercept},
+		{name: "maneuvers", draw: (*Game).drawManeuverNodes},
+		{name: "hodograph", draw: func(g *Game, view *ebiten.Image) {
+			if g.showHodograph {
+				g.drawHodograph(view)
+			}
+		}},
+		{name: "energy", draw: func(g *Game, view *ebiten.Image) {
+			if g.showEnergyPlot {
+				g.drawEnergyPlot(view)
+			}
+		}},
+		{name: "hud", draw: (*Game).drawHUD},
+		{name: "banner", draw: (*Game).drawBanner},
+		// the help is drawn last so nothing covers it
+		{name: "help", draw: func(g *Game, view *ebiten.Image) {
+			if g.showHelp {
+				g.drawHelp(view)
+			}
+		}},
+	}
+	for i := range layers {
+		layers[i].enabled = true
+	}
+	return layers
+}
+
+// returns the layers in the order of the comma separated names, from bottom to top
+// layers that are not named are kept at the end of the list, but disabled
+func parseRenderLayers(spec string) ([]renderLayer, error) {
+	all := defaultRenderLayers()
+	var layers []renderLayer
+	used := map[string]bool{}
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if used[name] {
+			return nil, fmt.Errorf("layer %q is listed twice", name)
+		}
+		i := layerIndex(all, name)
+		if i < 0 {
+			return nil, fmt.Errorf("unknown layer %q, known layers are %s", name, layerNames(all))
+		}
+		layers = append(layers, all[i])
+		used[name] = true
+	}
+	for _, layer := range all {
+		if !used[layer.name] {
+			layer.enabled = false
+			layers = append(layers, layer)
+		}
+	}
+	return layers, nil
+}
+
+// returns the index of the layer with the given name, -1 if there is none
+func layerIndex(layers []renderLayer, name string) int {
+	for i, layer := range layers {
+		if layer.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// returns the names of the layers, comma separated
+func layerNames(layers []renderLayer) string {
+	names := make([]string, len(layers))
+	for i, layer := range layers {
+		names[i] = layer.name
+	}
+	return strings.Join(names, ",")
+}
+
+// draws the enabled layers into the view from bottom to top
+func (g *Game) drawLayers(view *ebiten.Image) {
+	for _, layer := range g.layers {
+		if layer.enabled {
+			layer.draw(g, view)
+		}
+	}
+}
+
+// swaps the trails and bodies layers, so the trails are drawn on top of the bodies
+func (g *Game) drawTrailsOverBodies() {
+	trails, bodies := layerIndex(g.layers, "trails"), layerIndex(g.layers, "bodies")
+	if trails >= 0 && bodies >= 0 && trails < bodies {
+		g.layers[trails], g.layers[bodies] = g.layers[bodies], g.layers[trails]
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// replaces the draw functions of the layers with ones that record the names of the drawn layers
+func recordLayers(layers []renderLayer) *[]string {
+	var drawn []string
+	for i := range layers {
+		name := layers[i].name
+		layers[i].draw = func(g *Game, view *ebiten.Image) { drawn = append(drawn, name) }
+	}
+	return &drawn
+}
+
+func TestLayerOrder(t *testing.T) {
+	g := newGame()
+	drawn := recordLayers(g.layers)
+	g.drawLayers(nil)
+	if want := strings.Split(layerNames(defaultRenderLayers()), ","); !slices.Equal(*drawn, want) {
+		t.Fatalf("drew %v, want %v", *drawn, want)
+	}
+	// the bodies cover their trails and nothing covers the help
+	if trails, bodies := slices.Index(*drawn, "trails"), slices.Index(*drawn, "bodies"); trails > bodies {
+		t.Errorf("trails are drawn after the bodies")
+	}
+	if last := (*drawn)[len(*drawn)-1]; last != "help" {
+		t.Errorf("%s is drawn last, want help", last)
+	}
+
+	g.drawTrailsOverBodies()
+	*drawn = nil
+	g.drawLayers(nil)
+	if trails, bodies := slices.Index(*drawn, "trails"), slices.Index(*drawn, "bodies"); trails < bodies {
+		t.Errorf("trails are drawn before the bodies after swapping them")
+	}
+}
+
+func TestDisabledLayersAreSkipped(t *testing.T) {
+	layers, err := parseRenderLayers("bodies, trails,hud")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := newGame()
+	g.layers = layers
+	drawn := recordLayers(g.layers)
+
+	g.drawLayers(nil)
+	if want := []string{"bodies", "trails", "hud"}; !slices.Equal(*drawn, want) {
+		t.Errorf("drew %v, want %v", *drawn, want)
+	}
+
+	// a layer toggled off is skipped, the others keep their order
+	g.layers[layerIndex(g.layers, "trails")].enabled = false
+	*drawn = nil
+	g.drawLayers(nil)
+	if want := []string{"bodies", "hud"}; !slices.Equal(*drawn, want) {
+		t.Errorf("drew %v with the trails off, want %v", *drawn, want)
+	}
+}
+
+func TestParseRenderLayersRejectsBadNames(t *testing.T) {
+	for _, spec := range []string{"trails,trails", "trails,nebula", ""} {
+		if _, err := parseRenderLayers(spec); err == nil {
+			t.Errorf("parseRenderLayers(%q) was accepted", spec)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+const (
+	defaultMaxBodies int = 500 // largest number of spaceobjects a simulation may have
+	messageDuration  int = 300 // number of frames a message stays in the HUD
+)
+
+// returns an error if the game has more spaceobjects than it may have
+func (g *Game) checkBodyCount() error {
+	if len(g.spaceObjects) > g.maxBodies {
+		return fmt.Errorf("%d bodies exceed the maximum of %d (see --max-bodies)", len(g.spaceObjects), g.maxBodies)
+	}
+	return nil
+}
+
+// shows the message in the HUD for a while and logs it
+func (g *Game) notify(message string) {
+	log.Println(message)
+	g.message = message
+	g.messageFrames = messageDuration
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"io"
+	"math/rand/v2"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTooManyBodiesAreRefused(t *testing.T) {
+	// the flyby scene with its three bodies, limited to two
+	limited := func() *Game {
+		g := NewGame()
+		g.maxBodies = 2
+		return g
+	}
+	path := filepath.Join(t.TempDir(), "save.json")
+	if err := NewGame().SaveState(path); err != nil {
+		t.Fatal(err)
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{"scene", func() error { return NewApp(func(*Game) {}).Start(Scene{name: "limited", create: limited}) }},
+		{"smoke run", func() error { return runSmoke(limited(), io.Discard) }},
+		{"monte carlo", func() error { return runMonteCarlo(limited, 1, 1, 0, rng, io.Discard) }},
+		{"divergence", func() error { return runDivergence(limited, 1, 1, rng, io.Discard) }},
+		{"loaded save", func() error { return limited().LoadState(path) }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.run(); err == nil || !strings.Contains(err.Error(), "maximum of 2") {
+				t.Errorf("got %v, want an error about the maximum of 2 bodies", err)
+			}
+		})
+	}
+
+	// exactly at the limit the scene is accepted
+	g := limited()
+	g.maxBodies = len(g.spaceObjects)
+	if err := g.checkBodyCount(); err != nil {
+		t.Errorf("scene within the limit is refused: %v", err)
+	}
+}
@@ -2,16 +2,24 @@ package main
 
 import (
 	"bytes"
+	"flag"
 	"fmt"
+	"image"
 	"image/color"
 	"log"
 	"math"
-	"math/rand"
+	"math/rand/v2"
+	"os"
+	"runtime"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/examples/resources/fonts"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 type Vector struct {
@@ -24,6 +32,18 @@ func (v Vector) Length() float64 {
 	return math.Sqrt(v.X*v.X + v.Y*v.Y)
 }
 
+// returns the squared distance between two vectors, avoids the square root if only comparisons are needed
+func (v Vector) DistanceSquared(other Vector) float64 {
+	dx := v.X - other.X
+	dy := v.Y - other.Y
+	return dx*dx + dy*dy
+}
+
+// returns the dot product of two vectors
+func (v Vector) Dot(other Vector) float64 {
+	return v.X*other.X + v.Y*other.Y
+}
+
 // returns a normalized version of the vector (length = 1)
 func (v Vector) Normalize() Vector {
 	length := v.Length()
@@ -40,47 +60,147 @@ func (v Vector) Translate(dx, dy float64) Vector {
 	return Vector{v.X + dx, v.Y + dy}
 }
 
+// returns the vector of the given length pointing at the given angle to the x axis in rad
+func VectorFromPolar(length, angle float64) Vector {
+	return Vector{length * math.Cos(angle), length * math.Sin(angle)}
+}
+
+// returns the angle of the vector to the x axis in rad, in [-pi, pi]
+func (v Vector) Angle() float64 {
+	return math.Atan2(v.Y, v.X)
+}
+
+// returns the point the fraction t of the way from v to other
+func (v Vector) Lerp(other Vector, t float64) Vector {
+	return Vector{v.X + (other.X-v.X)*t, v.Y + (other.Y-v.Y)*t}
+}
+
 type SpaceObject struct {
-	name           string
-	mass           float64       // mass of the object in kg
-	position       Vector        // position vector of the object in m
-	scaledPosition Vector        // scaled position vector of the object in pixel
-	velocity       Vector        // velocity vector of the object in m/s
-	img            *ebiten.Image // object image
-	pathImg        *ebiten.Image // image of the object path
-	color          color.Color   // color of object and object path
+	name             string
+	mass             float64         // mass of the object in kg
+	radius           float64         // radius of the object in m
+	position         Vector          // position vector of the object in m
+	scaledPosition   Vector          // scaled position vector of the object in pixel
+	velocity         Vector          // velocity vector of the object in m/s
+	img              *ebiten.Image   // object image
+	pathImg          *ebiten.Image   // image of the object path
+	color            color.Color     // color of object and object path
+	isSpacecraft     bool            // the spacecraft is the object the player is interested in
+	minSpeed         float64         // lowest speed observed so far in m/s
+	maxSpeed         float64         // highest speed observed so far in m/s
+	speedObserved    bool            // minSpeed and maxSpeed are only valid once a speed was observed
+	heading          float64         // direction the engine pushes the object to in rad
+	thrust           float64         // force of the engine in N
+	thrusting        bool            // the engine fires during the next step
+	fuelMass         float64         // mass of the remaining propellant in kg, part of mass
+	exhaustVelocity  float64         // exhaust velocity of the engine in m/s
+	previousPosition Vector          // position before the last step in m
+	lastPathPoint    Vector          // position in the path image the path was last extended to in pixel
+	hasPathPoint     bool            // lastPathPoint is only valid once the path has a point
+	showTrail        bool            // the path of the object is drawn
+	mode             IntegrationMode // how the object moves, see rails.go
+	rails            *railsOrbit     // orbit of an object on rails, nil for the other modes
+	orientation      float64         // rotation the image is drawn with in rad
+	trailLevel       trailLOD        // level of detail of the trail, see traillod.go
+	trailFade        float32         // opacity of the path image while it fades out after losing its full trail
+	recentPositions  []Vector        // last positions in m for a short trail
 }
 
-func (so *SpaceObject) UpdateVelocity(force Vector) {
+func (so *SpaceObject) UpdateVelocity(force Vector, h float64) {
 	// Update velocity in each direction using Newtons 2nd Law of motion:
 	// F = ma -> a = F/m
 	// with a = dv/dt (acceleration is the derivate of velocity)
-	// we get dv = F/m * dt
+	// we get dv = F/m * dt, with the step h as dt
 	// this way we can apply dv by adding it to the current velocity
-	so.velocity.X += (force.X / so.mass) * dt
-	so.velocity.Y += (force.Y / so.mass) * dt
+	so.velocity.X += (force.X / so.mass) * h
+	so.velocity.Y += (force.Y / so.mass) * h
 }
 
-func (so *SpaceObject) UpdatePosition() {
+func (so *SpaceObject) UpdatePosition(h float64) {
 	// Update position using v = dx/dt (velocity is the derivate of distance)
 	// v = dx/dt -> dx = v*dt
 	// this way we can apply dx by adding it to the current position
-	so.position.X += so.velocity.X * dt
-	so.position.Y += so.velocity.Y * dt
+	so.position.X += so.velocity.X * h
+	so.position.Y += so.velocity.Y * h
+}
+
+// extends the path to the given point, which is in the pixel coordinates of the path image
+// width is the thickness of the path in pixel of the path image
+func (so *SpaceObject) UpdatePathImage(point Vector, pathColor color.Color, spacing, width float64) {
+
+	// the first point of a path is a single round stamp
+	if !so.hasPathPoint {
+		vector.DrawFilledCircle(so.pathImg, float32(point.X), float32(point.Y), float32(width/2), pathColor, true)
+
+		so.lastPathPoint = point
+		so.hasPathPoint = true
+		return
+	}
+
+	// slow objects would draw the same pixels over and over, so wait until they moved far enough
+	if !needsPathPoint(so.lastPathPoint, point, spacing) {
+		return
+	}
+
+	// connect the points with a line, so fast objects leave a path without gaps
+	// thick lines get a round stamp at every point, so the segments join without notches
+	vector.StrokeLine(so.pathImg, float32(so.lastPathPoint.X), float32(so.lastPathPoint.Y), float32(point.X), float32(point.Y), float32(width), pathColor, width > 1)
+	if width > 1 {
+		vector.DrawFilledCircle(so.pathImg, float32(point.X), float32(point.Y), float32(width/2), pathColor, true)
+	}
+	so.lastPathPoint = point
 }
 
-func (so *SpaceObject) UpdatePathImage() {
+// returns true if the two objects overlap (distance is smaller than the sum of radii)
+func (so *SpaceObject) CollidesWith(other *SpaceObject) bool {
+	radii := so.radius + other.radius
+	return so.position.DistanceSquared(other.position) < radii*radii
+}
+
+// merges two colliding objects into one (accretion)
+// mass and momentum are conserved and the radius grows so the volume is conserved
+// everything else (name, images, engine, trail and integration mode) is taken over from the heavier object
+func mergeSpaceObjects(so1, so2 *SpaceObject) *SpaceObject {
+	heavier := so1
+	if so2.mass > so1.mass {
+		heavier = so2
+	}
+
+	mass := so1.mass + so2.mass
+
+	// mass-weighted centroid of both positions
+	position := Vector{
+		(so1.position.X*so1.mass + so2.position.X*so2.mass) / mass,
+		(so1.position.Y*so1.mass + so2.position.Y*so2.mass) / mass,
+	}
 
-	// fill the path pixels in the assigned color
-	newImg := ebiten.NewImage(1, 1)
+	// p = m*v has to be the same before and after the merge
+	velocity := Vector{
+		(so1.velocity.X*so1.mass + so2.velocity.X*so2.mass) / mass,
+		(so1.velocity.Y*so1.mass + so2.velocity.Y*so2.mass) / mass,
+	}
 
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(so.scaledPosition.X, so.scaledPosition.Y)
-	newImg.Fill(so.color)
-	so.pathImg.DrawImage(newImg, op)
+	// V = 4/3*pi*r^3 -> r = cbrt(r1^3 + r2^3)
+	radius := math.Cbrt(so1.radius*so1.radius*so1.radius + so2.radius*so2.radius*so2.radius)
+
+	merged := *heavier
+	merged.mass = mass
+	merged.radius = radius
+	merged.position = position
+	merged.previousPosition = position
+	merged.velocity = velocity
+	// the propellant is part of the mass, the lighter object's fuel is lost with its engine
+	merged.fuelMass = math.Min(heavier.fuelMass, mass)
+	if heavier.rails != nil {
+		// the orbit changed with the merge and is derived again from the merged state
+		rails := *heavier.rails
+		rails.set = false
+		merged.rails = &rails
+	}
+	return &merged
 }
 
-func CreateRandomSpaceObject() *SpaceObject {
+func CreateRandomSpaceObject(rng *rand.Rand) *SpaceObject {
 
 	names := []string{
 		"Mercury",
@@ -94,36 +214,40 @@ func CreateRandomSpaceObject() *SpaceObject {
 		"Pluto",
 	}
 
-	name := names[rand.Intn(len(names))]
+	name := names[rng.IntN(len(names))]
 
 	// generate a random mass
-	mass := rand.Float64() * 6.417e25
+	mass := rng.Float64() * 6.417e25
 
 	fmt.Println(mass)
 
+	// derive the radius from the mass assuming the density of earth (5514 kg/m^3)
+	radius := math.Cbrt(3 * mass / (4 * math.Pi * 5514))
+
 	// generate a random starting position in [-1e8*Scale, 1e8*Scale]
 	position := Vector{
-		(rand.Float64()*2*1e8 - 1e8) * XScale,
-		(rand.Float64()*2*1e8 - 1e8) * YScale,
+		(rng.Float64()*2*1e8 - 1e8) * XScale,
+		(rng.Float64()*2*1e8 - 1e8) * YScale,
 	}
 
 	fmt.Println(position)
 
 	// generate a random starting velocity
 	velocity := Vector{
-		rand.Float64()*7000 - 3500,
-		rand.Float64()*7000 - 3500,
+		rng.Float64()*7000 - 3500,
+		rng.Float64()*7000 - 3500,
 	}
 
 	// generate a random color for imgage and path
-	r := uint8(rand.Int())
-	g := uint8(rand.Int())
-	b := uint8(rand.Int())
+	r := uint8(rng.Int())
+	g := uint8(rng.Int())
+	b := uint8(rng.Int())
 	color := color.RGBA{r, g, b, 1}
 
 	return &SpaceObject{
 		name:     name,
 		mass:     mass,
+		radius:   radius,
 		position: position,
 		velocity: velocity,
 		img:      createEmptyColoredImage(2, 2, color),
@@ -136,17 +260,103 @@ var (
 )
 
 const (
-	gravitation float64 = 6.67430e-11                    // Gravitational constant (m^3 kg^-1 s^-2)
-	dt          float64 = 1.0 / 60.0 * 60 * 60 * 24 * 30 // time delta (1 sec / refreshrate * seconds * minutes * hours)
-	XScale      float64 = 0.1e-6                         // x scaling to show the huge numbers on screen
-	YScale      float64 = 0.1e-6                         // y scaling to show the huge numbers on screen
+	gravitation      float64 = 6.67430e-11                    // Gravitational constant (m^3 kg^-1 s^-2)
+	dt               float64 = 1.0 / 60.0 * 60 * 60 * 24 * 30 // time delta (1 sec / refreshrate * seconds * minutes * hours)
+	XScale           float64 = 0.1e-6                         // x scaling to show the huge numbers on screen
+	YScale           float64 = 0.1e-6                         // y scaling to show the huge numbers on screen
+	defaultSeed      uint64  = 1                              // seed of the random number generator
+	minBodyMass      float64 = 1                              // smallest mass in kg the mass of a body can be edited down to
+	defaultNudgeStep float64 = 1e8                            // distance in m the selected body moves per frame while an arrow key is held
+	fineNudgeDivisor float64 = 10                             // shift divides the nudge step by this
+	windowWidth      int     = 1080                           // width of the window in pixel when the app starts
+	windowHeight     int     = 720                            // height of the window in pixel when the app starts
 )
 
 type Game struct {
-	screenWidth  int
-	screenHeight int
-	spaceObjects []*SpaceObject
-	time         float64
+	screenWidth      int
+	screenHeight     int
+	spaceObjects     []*SpaceObject
+	time             float64
+	focus            int                  // index of the spaceobject the view is centered on, -1 for the origin
+	selected         int                  // index of the selected spaceobject, -1 if nothing is selected
+	debug            bool                 // check momentum and energy conservation every step
+	baseline         conservationBaseline // conserved quantities the debug checks compare against
+	units            UnitSystem           // units the HUD shows values in
+	recording        Recording            // recorded states of the spaceobjects for the export
+	input            *textInput           // text the user is currently typing, nil if there is none
+	config           SimConfig            // tunable parameters of the simulation
+	trailColorMode   trailColorMode       // how the path pixels are colored
+	aspectRatio      float64              // fixed aspect ratio (width / height) of the letterboxed scene, 0 to use the whole window
+	camera           Camera               // part of the world that is shown
+	rngSource        *rand.PCG            // source of all random numbers, kept to save its state
+	rng              *rand.Rand           // random number generator used for generated scenes
+	artisticScale    bool                 // draw bodies bigger than they are so they stay visible, see drawRadius
+	trailSpacing     float64              // minimum distance in pixel between two points of a path
+	showOrbit        bool                 // draw the orbit of the spacecraft around its dominant body
+	keys             KeyBindings          // keys triggering the actions
+	springs          []Spring             // tethers between spaceobjects, applied alongside gravity
+	replay           *Replay              // recording that is played back instead of simulating, nil if there is none
+	showHelp         bool                 // draw the help overlay listing the controls
+	helpPage         int                  // page of the help overlay that is shown
+	showPrediction   bool                 // draw the predicted trajectory of the spacecraft
+	predictionSteps  int                  // number of time steps the trajectory prediction looks ahead
+	initialEnergy    float64              // total energy at the start of the simulation in J
+	initialEnergySet bool                 // initialEnergy is only valid once the first step was taken
+	nodeAxis         float64              // angle of the reference axis the node markers of the orbit are placed on in rad
+	showLagrange     bool                 // draw the lagrange points of the selected planet and the body it orbits
+	ruler            Ruler                // measures distances between clicked points
+	endCondition     EndCondition         // decides when an automated run is over
+	done             bool                 // the end condition was met
+	doneReason       string               // describes which end condition was met
+	showDominance    bool                 // tint the background by the planet pulling strongest
+	dominance        dominanceOverlay     // cached tint of the dominance overlay
+	savePath         string               // file the state is saved to and loaded from, a .gob extension selects the binary format
+	transfer         transferPlan         // hohmann transfer the autopilot is executing
+	trailScale       float64              // resolution of the path images relative to the screen, 2 for twice as many pixels per axis
+	showCoordinates  bool                 // show the world coordinates under the cursor
+	fastForwardLoads bool                 // replay the recording saved along with a loaded state up to its time
+	annotations      []*Annotation        // scripted texts of the scene
+	events           eventTracker         // state of the previous step for detecting spacecraft events
+	apsis            apsisTracker         // orientation of the orbit before and after flybys
+	trailWidth       float64              // thickness of the paths in screen pixel
+	maxBodies        int                  // largest number of spaceobjects the simulation may have
+	message          string               // shown in the HUD while messageFrames is positive
+	messageFrames    int                  // number of frames the message is still shown
+	showResonances   bool                 // list the mean-motion resonances between the planets
+	escapeMargin     float64              // multiple of the escape velocity the escape command sets
+	escapeRadial     bool                 // the escape command points straight away from the body instead of prograde
+	layers           []renderLayer        // drawn from first to last, see layers.go
+	paused           bool                 // the simulation is not stepped
+	clock            realClock            // real time spent simulating
+	showSubsteps     bool                 // the positions after every substep of the last step are drawn
+	substepDots      []Vector             // positions of all spaceobjects after the substeps of the last step in m
+	decay            decayTracker         // estimate of the orbit decay from drag
+	intercept        interceptPlan        // planned intercept of a planet, see lambert.go
+	resetRequested   bool                 // the app restarts the scene after this update
+	ghosts           []*ebiten.Image      // trails of previous runs of the scene, oldest first
+	maxGhosts        int                  // number of previous runs whose trails are kept on reset
+	showHill         bool                 // the hill spheres of the planets are drawn
+	showHodograph    bool                 // the hodograph panel is drawn
+	hodograph        []Vector             // recent velocities of the spacecraft relative to its dominant body in m/s
+	nudgeStep        float64              // distance in m the arrow keys move the selected body per frame while paused
+	autoPause        AutoPause            // pauses the simulation when the spacecraft crashes or escapes
+	progradeLock     bool                 // the spacecraft is drawn pointing along its velocity instead of its heading
+	showRings        bool                 // distance rings are drawn around the selected body
+	ringSpacing      float64              // distance between two distance rings in m
+	ringCount        int                  // number of distance rings
+	nodes            []*ManeuverNode      // planned maneuvers, see maneuver.go
+	dragging         *nodeHandle          // maneuver handle held with the mouse, nil if none
+	fitPending       bool                 // zoom to fit all bodies once the window size is known
+	showEnergyPlot   bool                 // the energy plot panel is drawn
+	energyHistory    energyHistory        // total energy of the recent steps for the energy plot
+	showAxes         bool                 // the x and y axes through the origin are drawn
+	stageMass        float64              // mass in kg the staging drops from the spacecraft
+	minDryMass       float64              // mass in kg the spacecraft cannot be staged below
+	separationSpeed  float64              // m/s a staging pushes the spacecraft along its heading
+	diff             *TrajectoryDiff      // two recordings whose paths are overlaid, nil if there are none
+	showEccentricity bool                 // the eccentricity vector of the spacecraft is drawn
+	revolution       revolutionTracker    // measured period of the last revolution of the spacecraft
+	trailLOD         trailLODConfig       // how many bodies near the camera get full and short trails
 }
 
 func createEmptyColoredImage(width, height int, color color.Color) *ebiten.Image {
@@ -156,14 +366,15 @@ func createEmptyColoredImage(width, height int, color color.Color) *ebiten.Image
 	return img
 }
 
-func calculateGravitationalForce(so1, so2 SpaceObject) Vector {
+func calculateGravitationalForce(so1, so2 SpaceObject, config SimConfig) Vector {
 	// calculate distance vector and actual distance between so1 and so2
 	// The vector points from so2 to  so1
 	distanceVector := Vector{so1.position.X - so2.position.X, so1.position.Y - so2.position.Y}
 	distance := distanceVector.Length()
 
 	// calculate the gravitational force that is acting on so1
-	gravForce := (gravitation * so2.mass * so1.mass) / (distance * distance)
+	// for newtonian gravity the exponent of the distance is 2 (inverse-square law)
+	gravForce := (config.gravitationalConstant() * so2.mass * so1.mass) / config.distancePower(distance)
 
 	// Normalize the distance vector, so its length equals 1.
 	// This gives us a vector that determines the direction of the gravitational force without
@@ -180,6 +391,112 @@ func calculateGravitationalForce(so1, so2 SpaceObject) Vector {
 	return force
 }
 
+// returns the sum of the forces all other objects and the springs put on the object at index i
+func netForce(spaceObjects []*SpaceObject, springs []Spring, i int, config SimConfig) Vector {
+	return netForceFrom(spaceObjects, springs, i, config, nil)
+}
+
+// returns the net force on the spaceobject at index i from the spaceobjects at the given indices, in ascending order
+// nil candidates means all spaceobjects; the springs of the object always count
+func netForceFrom(spaceObjects []*SpaceObject, springs []Spring, i int, config SimConfig, candidates []int) Vector {
+	so1 := spaceObjects[i]
+	net := Vector{0, 0}
+
+	count := len(spaceObjects)
+	if candidates != nil {
+		count = len(candidates)
+	}
+
+	// iterate over every other spaceobject and calculate how it is influencing so1
+	for k := 0; k < count; k++ {
+		j := k
+		if candidates != nil {
+			j = candidates[k]
+		}
+		so2 := spaceObjects[j]
+
+		// skip if we would compare the same object
+		if j == i {
+			continue
+		}
+
+		// a spacecraft treated as test particle does not pull on anything
+		if !config.attracts(so1, so2) {
+			continue
+		}
+
+		// skip bodies too far away to matter (only if a cutoff is configured)
+		if !config.withinCutoff(so1.position.DistanceSquared(so2.position)) {
+			continue
+		}
+
+		// calculate the force so2 is putting on so1
+		var force Vector
+		if config.float32Forces {
+			force = calculateGravitationalForce32(*so1, *so2, config)
+		} else {
+			force = calculateGravitationalForce(*so1, *so2, config)
+		}
+		net = net.Translate(force.X, force.Y)
+	}
+
+	// springs pull on the objects they connect in addition to gravity
+	for _, s := range springs {
+		switch i {
+		case s.a:
+			force := s.force(spaceObjects)
+			net = net.Translate(force.X, force.Y)
+		case s.b:
+			force := s.force(spaceObjects)
+			net = net.Translate(-force.X, -force.Y)
+		}
+	}
+
+	return net
+}
+
+// advances the given spaceobjects by one time step starting at the given simulated time
+// the step of dt is split into config.substeps smaller steps, onSubstep is called after each of them if not nil
+// only nbody objects are integrated, objects on rails are placed on their orbit after every substep
+func stepSpaceObjects(spaceObjects []*SpaceObject, springs []Spring, config SimConfig, time float64, onSubstep func()) {
+	// frozen, fixed and rails objects still pull on the others, the integration must not change their state
+	type state struct{ position, velocity Vector }
+	frozen := map[*SpaceObject]state{}
+	var rails []*SpaceObject
+	for _, so := range spaceObjects {
+		onRails := so.mode == modeRails && !config.frozen(so) && so.placeOnRails(spaceObjects, config, time)
+		if onRails {
+			rails = append(rails, so)
+		}
+		if config.frozen(so) || so.mode == modeFixed || onRails {
+			frozen[so] = state{so.position, so.velocity}
+		}
+	}
+
+	substeps := max(config.substeps, 1)
+	h := dt / float64(substeps)
+	for k := 0; k < substeps; k++ {
+		switch config.integrator {
+		case integratorRK4:
+			stepRK4(spaceObjects, springs, config, h)
+		case integratorLeapfrog:
+			stepLeapfrog(spaceObjects, springs, config, h)
+		default:
+			stepEuler(spaceObjects, springs, config, h)
+		}
+		for so, s := range frozen {
+			so.position, so.velocity = s.position, s.velocity
+		}
+		// a parent listed before its child on rails has already moved, so the child follows it within the substep
+		for _, so := range rails {
+			so.placeOnRails(spaceObjects, config, time+float64(k+1)*h)
+		}
+		if onSubstep != nil {
+			onSubstep()
+		}
+	}
+}
+
 func init() {
 	s, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
 	if err != nil {
@@ -188,15 +505,69 @@ func init() {
 	mplusFaceSource = s
 }
 
-func NewGame() *Game {
+// returns a game without any spaceobjects
+// all random numbers of a game come from its own generator seeded with defaultSeed (see reseed),
+// nothing uses the global generator of math/rand, so a run only depends on its seed and inputs
+func newGame() *Game {
+	rngSource := rand.NewPCG(defaultSeed, defaultSeed)
+	return &Game{
+		rngSource:       rngSource,
+		rng:             rand.New(rngSource),
+		time:            0,
+		focus:           -1,
+		selected:        -1,
+		units:           unitSystems["si"],
+		config:          DefaultSimConfig(),
+		camera:          NewCamera(),
+		layers:          defaultRenderLayers(),
+		artisticScale:   true,
+		trailSpacing:    2,
+		keys:            DefaultKeyBindings(),
+		predictionSteps: defaultPredictionSteps,
+		decay:           decayTracker{energy: math.NaN(), orbitsLeft: math.NaN()},
+		escapeMargin:    defaultEscapeMargin,
+		nudgeStep:       defaultNudgeStep,
+		ringSpacing:     defaultRingSpacing,
+		ringCount:       defaultRingCount,
+		stageMass:       defaultStageMass,
+		minDryMass:      defaultMinDryMass,
+		energyHistory:   newEnergyHistory(defaultEnergyPlotLength),
+		maxBodies:       defaultMaxBodies,
+		trailScale:      1,
+		trailWidth:      1,
+		savePath:        defaultSavePath,
+		dominance:       dominanceOverlay{cell: defaultDominanceCell, alpha: defaultDominanceAlpha},
+	}
+}
 
-	game := &Game{
-		spaceObjects: make([]*SpaceObject, 3),
-		time:         0,
+// returns the spacecraft at the given position with the given velocity
+func newSpacecraft(position, velocity Vector) *SpaceObject {
+	return &SpaceObject{
+		name:            "Spacecraft",
+		mass:            5.9722e22,
+		radius:          1e3,
+		position:        position,
+		velocity:        velocity,
+		img:             createEmptyColoredImage(2, 2, color.RGBA{0, 0, 255, 1}),
+		color:           color.RGBA{0, 0, 255, 1},
+		isSpacecraft:    true,
+		showTrail:       true,
+		heading:         math.Pi / 2,
+		thrust:          2.4e16,
+		fuelMass:        1e22,
+		exhaustVelocity: 3000,
 	}
+}
+
+// returns the single flyby scene: a spacecraft passing a planet and its moon
+func NewGame() *Game {
+
+	game := newGame()
+	game.spaceObjects = make([]*SpaceObject, 3)
 	game.spaceObjects[0] = &SpaceObject{
 		name:     "Earth",
 		mass:     5.9722e24,
+		radius:   6.371e6,
 		position: Vector{0, 0},
 		velocity: Vector{0, -20},
 		img:      createEmptyColoredImage(2, 2, color.RGBA{255, 0, 0, 1}),
@@ -205,19 +576,13 @@ func NewGame() *Game {
 	game.spaceObjects[1] = &SpaceObject{
 		name:     "Moon",
 		mass:     5.9722e22,
+		radius:   1.7374e6,
 		position: Vector{5e9, 0},
 		velocity: Vector{0, -100},
 		img:      createEmptyColoredImage(2, 2, color.RGBA{0, 255, 0, 1}),
 		color:    color.RGBA{0, 255, 0, 1},
 	}
-	game.spaceObjects[2] = &SpaceObject{
-		name:     "Spacecraft",
-		mass:     5.9722e22,
-		position: Vector{-5e9, 1e9},
-		velocity: Vector{-10, 150},
-		img:      createEmptyColoredImage(2, 2, color.RGBA{0, 0, 255, 1}),
-		color:    color.RGBA{0, 0, 255, 1},
-	}
+	game.spaceObjects[2] = newSpacecraft(Vector{-5e9, 1e9}, Vector{-10, 150})
 	/*game.spaceObjects[0] = &SpaceObject{
 		name:     "Mars",
 		mass:     6.417e23,
@@ -234,77 +599,724 @@ func NewGame() *Game {
 		img:      createEmptyColoredImage(2, 2, color.White),
 		color:    color.White,
 	}*/
-	//game.spaceObjects[0] = CreateRandomSpaceObject()
-	//game.spaceObjects[1] = CreateRandomSpaceObject()
+	//game.spaceObjects[0] = CreateRandomSpaceObject(game.rng)
+	//game.spaceObjects[1] = CreateRandomSpaceObject(game.rng)
 	return game
 }
 
-func (g *Game) Update() error {
+// restarts the random number generator of the game from the given seed
+func (g *Game) reseed(seed uint64) {
+	g.rngSource.Seed(seed, seed)
+}
+
+// changes the gravity scale while keeping the paths of all objects
+// scaling G by k and every velocity by sqrt(k) gives the same orbits traversed sqrt(k) times faster
+func (g *Game) setGScale(gScale float64) {
+	factor := math.Sqrt(gScale / g.config.gScale)
+	for _, so := range g.spaceObjects {
+		so.velocity = so.velocity.Scale(factor, factor)
+	}
+	g.config.gScale = gScale
+	g.baseline.set = false
+	g.initialEnergySet = false
+}
 
-	// iterate over every spaceobject and calculate how it is influenced by all other objects
-	for i, so1 := range g.spaceObjects {
-		for j, so2 := range g.spaceObjects {
+// returns the position all drawn positions are relative to
+func (g *Game) focusPosition() Vector {
+	if g.focus < 0 || g.focus >= len(g.spaceObjects) {
+		return Vector{0, 0}
+	}
+	return g.spaceObjects[g.focus].position
+}
 
-			// skip if we would compare the same object
-			if j == i {
+// returns the name of the current focus frame
+func (g *Game) focusName() string {
+	if g.focus < 0 || g.focus >= len(g.spaceObjects) {
+		return "Origin"
+	}
+	return g.spaceObjects[g.focus].name
+}
+
+// cycles the focus frame from the origin through every spaceobject and back
+func (g *Game) cycleFocus() {
+	g.focus++
+	if g.focus >= len(g.spaceObjects) {
+		g.focus = -1
+	}
+
+	// the paths were drawn relative to the old frame and no longer match
+	g.clearPaths()
+}
+
+// clears the path images of all spaceobjects
+func (g *Game) clearPaths() {
+	for _, so := range g.spaceObjects {
+		if so.pathImg != nil {
+			so.pathImg.Clear()
+		}
+		so.hasPathPoint = false
+	}
+}
+
+// returns the new index of an object after the object at index removed was merged into the one at index into
+func remapIndex(index, into, removed int) int {
+	if index == removed {
+		return into
+	}
+	if index > removed {
+		return index - 1
+	}
+	return index
+}
+
+// returns the index of the spacecraft, -1 if there is none
+func (g *Game) spacecraftIndex() int {
+	for i, so := range g.spaceObjects {
+		if so.isSpacecraft {
+			return i
+		}
+	}
+	return -1
+}
+
+// returns the planet (every spaceobject except the spacecraft) closest to the given position and its distance
+// returns nil if there are no planets
+func (g *Game) NearestPlanet(to Vector) (*SpaceObject, float64) {
+	var nearest *SpaceObject
+	minSquared := math.Inf(1)
+	for _, so := range g.spaceObjects {
+		if so.isSpacecraft {
+			continue
+		}
+		if d := so.position.DistanceSquared(to); d < minSquared {
+			nearest = so
+			minSquared = d
+		}
+	}
+	if nearest == nil {
+		return nil, 0
+	}
+	return nearest, math.Sqrt(minSquared)
+}
+
+// returns the selected spaceobject, nil if nothing is selected
+func (g *Game) selectedObject() *SpaceObject {
+	if g.selected < 0 || g.selected >= len(g.spaceObjects) {
+		return nil
+	}
+	return g.spaceObjects[g.selected]
+}
+
+// turns the trail of the selected spaceobject on or off
+// turning it off frees the path image, so it does not keep growing in the background
+func (g *Game) toggleSelectedTrail() {
+	so := g.selectedObject()
+	if so == nil {
+		return
+	}
+	so.showTrail = !so.showTrail
+	if !so.showTrail && so.pathImg != nil {
+		so.pathImg.Deallocate()
+		so.pathImg = nil
+		so.hasPathPoint = false
+	}
+}
+
+// moves the selected spaceobject in the direction of the pressed arrow keys, finer with shift
+// the body moves the way the key points on the screen and keeps moving while the key is held
+func (g *Game) nudgeSelected() {
+	var direction Vector
+	if g.keys.Pressed(actionTurnLeft) {
+		direction.X--
+	}
+	if g.keys.Pressed(actionTurnRight) {
+		direction.X++
+	}
+	if g.keys.Pressed(actionThrust) {
+		direction.Y--
+	}
+	if g.keys.Pressed(actionNudgeDown) {
+		direction.Y++
+	}
+	g.nudge(direction, ebiten.IsKeyPressed(ebiten.KeyShift))
+}
+
+// moves the selected spaceobject by nudgeStep along each axis of the direction, a tenth of it if fine is set
+func (g *Game) nudge(direction Vector, fine bool) {
+	so := g.selectedObject()
+	if so == nil || direction == (Vector{}) {
+		return
+	}
+	step := g.nudgeStep
+	if fine {
+		step /= fineNudgeDivisor
+	}
+
+	so.position = so.position.Translate(direction.X*step, direction.Y*step)
+	so.previousPosition = so.position
+	so.scaledPosition = g.worldToScreen(so.position)
+	g.baseline.set = false
+}
+
+// multiplies the mass of the selected spaceobject by factor, but keeps it at least minBodyMass
+// the gravity of the new mass acts from the next step on; a spacecraft cannot keep more fuel than its mass
+func (g *Game) scaleSelectedMass(factor float64) {
+	so := g.selectedObject()
+	if so == nil {
+		return
+	}
+	so.mass = math.Max(so.mass*factor, minBodyMass)
+	so.fuelMass = math.Min(so.fuelMass, so.mass)
+	g.baseline.set = false
+}
+
+// cycles the selection through every spaceobject and back to nothing
+func (g *Game) cycleSelection() {
+	g.selected++
+	if g.selected >= len(g.spaceObjects) {
+		g.selected = -1
+	}
+}
+
+// replaces every pair of colliding objects with a single merged object
+// returns true if at least one merge happened
+func (g *Game) mergeCollisions() bool {
+	merged := false
+	for i := 0; i < len(g.spaceObjects); i++ {
+		for j := i + 1; j < len(g.spaceObjects); {
+			so1 := g.spaceObjects[i]
+			so2 := g.spaceObjects[j]
+
+			t, hit := sweptCollision(so1, so2)
+			if !hit {
+				j++
 				continue
 			}
 
-			// calculate the force so2 is putting on so1 and update the velocity this force applies
-			force := calculateGravitationalForce(*so1, *so2)
-			so1.UpdateVelocity(force)
+			// the objects are merged where they first touched, not where the step left them
+			so1.position = so1.previousPosition.Lerp(so1.position, t)
+			so2.position = so2.previousPosition.Lerp(so2.position, t)
+
+			g.spaceObjects[i] = mergeSpaceObjects(so1, so2)
+			merged = true
+
+			// remove so2 from the slice, j now points to the next object
+			g.spaceObjects = append(g.spaceObjects[:j], g.spaceObjects[j+1:]...)
+
+			// keep the focus and selection on the same object (or on the merged one)
+			g.focus = remapIndex(g.focus, i, j)
+			g.selected = remapIndex(g.selected, i, j)
+			g.springs = remapSprings(g.springs, i, j)
+		}
+	}
+	return merged
+}
+
+// starts or stops the recording
+func (g *Game) toggleRecording() {
+	if g.recording.active {
+		g.recording.Stop()
+	} else {
+		g.recording.Start()
+	}
+}
+
+// asks for a label and inserts a marker at the current time into the recording
+func (g *Game) insertMarker() {
+	time := g.time
+	g.startTextInput("Marker label: ", func(label string) {
+		g.recording.AddMarker(time, label)
+	})
+}
+
+// writes the recording to the json and csv export files
+func (g *Game) exportRecording() {
+	if err := g.recording.ExportJSON(recordingJSONPath); err != nil {
+		log.Printf("exporting recording failed: %v\n", err)
+	}
+	if err := g.recording.ExportCSV(recordingCSVPath); err != nil {
+		log.Printf("exporting recording failed: %v\n", err)
+	}
+}
 
+// triggers the actions of the pressed keys
+func (g *Game) handleKeys() {
+	if g.keys.JustPressed(actionFocus) {
+		g.cycleFocus()
+	}
+	if g.keys.JustPressed(actionSelect) {
+		g.cycleSelection()
+	}
+	if g.keys.JustPressed(actionCoordinates) {
+		g.showCoordinates = !g.showCoordinates
+	}
+	if g.keys.JustPressed(actionRuler) {
+		g.ruler.active = !g.ruler.active
+	}
+	// a dragged maneuver handle takes the click before the ruler and the selection
+	if g.dragNodeHandles() {
+		// the handle follows the cursor
+	} else if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		x, y := ebiten.CursorPosition()
+		if g.ruler.active {
+			g.placeRulerPoint(Vector{float64(x), float64(y)})
+		} else {
+			// clicking empty space clears the selection
+			g.selected = g.bodyIndexAt(Vector{float64(x), float64(y)})
+		}
+	}
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		g.clearRuler()
+	}
+	if g.keys.JustPressed(actionRecord) {
+		g.toggleRecording()
+	}
+	if g.keys.JustPressed(actionMarker) {
+		g.insertMarker()
+	}
+	if g.keys.JustPressed(actionExport) {
+		g.exportRecording()
+	}
+	if g.keys.JustPressed(actionIntegrator) {
+		g.config.integrator = g.config.integrator.next()
+		g.resetConservationBaseline()
+	}
+	if g.keys.JustPressed(actionFreezePlanets) {
+		g.config.freezePlanets = !g.config.freezePlanets
+		g.baseline.set = false
+	}
+	if g.keys.JustPressed(actionFreezeSpacecraft) {
+		g.config.freezeSpacecraft = !g.config.freezeSpacecraft
+		g.baseline.set = false
+	}
+	if g.keys.JustPressed(actionTrailColor) {
+		g.trailColorMode = g.trailColorMode.next()
+	}
+	if g.keys.JustPressed(actionZoomToFit) {
+		g.zoomToFit()
+	}
+	if g.keys.JustPressed(actionBodyScale) {
+		g.artisticScale = !g.artisticScale
+	}
+	if g.keys.JustPressed(actionOrbit) {
+		g.showOrbit = !g.showOrbit
+	}
+	if g.keys.JustPressed(actionDominance) {
+		g.showDominance = !g.showDominance
+	}
+	if g.keys.JustPressed(actionResonances) {
+		g.showResonances = !g.showResonances
+	}
+	if g.keys.JustPressed(actionLagrange) {
+		g.showLagrange = !g.showLagrange
+	}
+	if g.keys.JustPressed(actionSave) {
+		if err := g.SaveState(g.savePath); err != nil {
+			g.notify("saving failed: " + err.Error())
+		}
+	}
+	if _, wheel := ebiten.Wheel(); g.camera.zoomBy(wheel) {
+		// the paths were drawn with the old zoom and no longer match
+		g.clearPaths()
+	}
+	if g.keys.JustPressed(actionClearTrails) {
+		// only the drawn paths are wiped, the simulation continues unchanged
+		g.clearPaths()
+	}
+	if g.keys.JustPressed(actionPrediction) {
+		g.showPrediction = !g.showPrediction
+	}
+	if g.keys.JustPressed(actionHelp) {
+		g.toggleHelp()
+	}
+	if g.keys.JustPressed(actionTrail) {
+		g.toggleSelectedTrail()
+	}
+	if g.keys.JustPressed(actionReplay) {
+		g.toggleReplay()
+	}
+	// a replay or fast-forward owns the spaceobjects until it ends
+	if g.keys.JustPressed(actionLoad) && g.replay == nil {
+		if err := g.LoadState(g.savePath); err != nil {
+			g.notify("loading failed: " + err.Error())
+		}
+	}
+	// while paused the arrow keys place the selected body instead of steering
+	if g.paused && g.selectedObject() != nil {
+		g.nudgeSelected()
+	} else if craft := g.spacecraftIndex(); craft >= 0 && g.replay == nil {
+		g.spaceObjects[craft].handleControls(g.keys)
+	}
+	if g.keys.JustPressed(actionGoToTime) && g.replay != nil {
+		g.startTextInput("Go to time (days): ", func(value string) {
+			if days, err := strconv.ParseFloat(value, 64); err == nil {
+				g.jumpReplay(days * secondsPerDay)
+			} else {
+				log.Printf("going to time failed: %v\n", err)
+			}
+		})
+	}
+	if g.keys.JustPressed(actionIntercept) && g.replay == nil {
+		g.planIntercept()
+	}
+	if g.keys.JustPressed(actionTransfer) && g.replay == nil {
+		g.planTransfer()
+	}
+	if g.keys.JustPressed(actionSubsteps) {
+		g.showSubsteps = !g.showSubsteps
+	}
+	if g.keys.JustPressed(actionHalveMass) && g.replay == nil {
+		g.scaleSelectedMass(0.5)
+	}
+	if g.keys.JustPressed(actionDoubleMass) && g.replay == nil {
+		g.scaleSelectedMass(2)
+	}
+	if g.keys.JustPressed(actionHill) {
+		g.showHill = !g.showHill
+	}
+	if g.keys.JustPressed(actionHodograph) {
+		g.showHodograph = !g.showHodograph
+	}
+	if g.keys.JustPressed(actionStage) && g.replay == nil {
+		g.stageSpacecraft()
+	}
+	if g.keys.JustPressed(actionEccentricity) {
+		g.showEccentricity = !g.showEccentricity
+	}
+	if g.keys.JustPressed(actionAxes) {
+		g.showAxes = !g.showAxes
+	}
+	if g.keys.JustPressed(actionEnergyPlot) {
+		g.showEnergyPlot = !g.showEnergyPlot
+	}
+	if g.keys.JustPressed(actionProgradeLock) {
+		g.progradeLock = !g.progradeLock
+	}
+	if g.keys.JustPressed(actionRings) {
+		g.showRings = !g.showRings
+	}
+	if g.keys.JustPressed(actionPlaceNode) && g.replay == nil {
+		x, y := ebiten.CursorPosition()
+		g.placeManeuverNode(Vector{float64(x), float64(y)})
+	}
+	if g.keys.JustPressed(actionExecuteNode) && g.replay == nil {
+		if n := g.nextManeuverNode(); n != nil {
+			g.executeManeuverNode(n)
 		}
 	}
+	if g.keys.JustPressed(actionReset) {
+		g.resetRequested = true
+	}
+	if g.keys.JustPressed(actionPause) {
+		g.paused = !g.paused
+		g.autoPause.banner = ""
+	}
+	if g.keys.JustPressed(actionEscape) && g.replay == nil && g.setEscapeTrajectory(g.escapeMargin, g.escapeRadial) {
+		g.baseline.set = false
+	}
+	// the snapped velocity changes the energy of the system, so the conservation checks start over
+	if g.keys.JustPressed(actionSnapOrbit) && g.replay == nil && g.snapToCircularOrbit() {
+		g.baseline.set = false
+	}
+}
+
+// advances the simulation by one time step without any input or rendering
+func (g *Game) Step() {
+	// remember the energy at the start to show how much the integration drifts
+	if !g.initialEnergySet {
+		g.initialEnergy = g.TotalEnergy()
+		g.initialEnergySet = true
+		// the plot shows the drift from this energy, older values belong to another baseline
+		g.energyHistory.clear()
+	}
 
-	// after updating the velocites, we now update all positions
+	// the collision check needs to know where the objects came from
 	for _, so := range g.spaceObjects {
-		// Update position using the spaceobjects velocity
-		so.UpdatePosition()
-		// scale current postion to window
-		so.scaledPosition = so.position.Scale(XScale, YScale).Translate(float64(g.screenWidth/2.0), float64(g.screenHeight/2.0))
+		so.previousPosition = so.position
+	}
+	// a crash in the very first step has to count for the end condition and the auto-pause as well
+	if g.spacecraftIndex() >= 0 {
+		g.endCondition.hadSpacecraft = true
+		g.autoPause.hadSpacecraft = true
+	}
+
+	g.substepDots = g.substepDots[:0]
+	stepSpaceObjects(g.spaceObjects, g.springs, g.config, g.time, g.recordSubstep)
+
+	// the engine adds momentum to the system, so the conservation checks start over
+	for _, so := range g.spaceObjects {
+		if !g.config.frozen(so) && so.mode == modeNBody && so.ApplyThrust() {
+			g.baseline.set = false
+		}
+	}
+
+	// the atmosphere takes energy out of the system as well
+	if g.applyDrag() {
+		g.baseline.set = false
+	}
+
+	// the autopilot does the second burn of a transfer and the maneuver nodes when their time has come
+	g.updateTransfer()
+	g.updateManeuverNodes()
+
+	// scripted annotations react to the time and to what the spacecraft does
+	events := g.detectEvents()
+	g.updateAnnotations(events)
+	g.trackDecay(events)
+	g.recordHodograph()
+	g.recordEnergy()
+	g.trackApsisLine()
+	g.trackRevolution()
+
+	// objects that overlap after the position update are merged into one
+	// a merge is inelastic and loses energy, so the conservation checks start over
+	if g.mergeCollisions() {
+		g.baseline.set = false
 	}
 
 	g.time += dt
+
+	g.recording.RecordFrame(g.time, g.spaceObjects, g.recordedOrbit())
+
+	if g.debug {
+		g.checkConservation()
+	}
+
+	g.checkEndCondition()
+	g.checkAutoPause()
+	g.expireIntercept()
+}
+
+func (g *Game) Update() error {
+	if g.messageFrames > 0 {
+		g.messageFrames--
+	}
+
+	// while the user is typing, keys do not trigger actions
+	if g.input != nil {
+		g.updateTextInput()
+	} else {
+		g.handleKeys()
+	}
+
+	// a replay sets the positions from the recording instead of integrating
+	g.clock.tick(time.Now(), g.paused)
+	if !g.paused {
+		if g.replay != nil {
+			g.updateReplay()
+		} else {
+			g.Step()
+		}
+	}
+
+	g.updateView()
 	return nil
 }
 
-func (g *Game) Draw(screen *ebiten.Image) {
+// moves the camera and the drawn positions to the current state of the simulation
+func (g *Game) updateView() {
+	if craft, body := g.spacecraftAndDominantBody(); craft != nil {
+		craft.orient(body, g.progradeLock)
+	}
 
-	// iterate over every spaceobject and draw it
+	// the focused object stays in the center of the window, everything else moves relative to it
+	g.camera.follow(g.focusPosition())
+	// the fit needs the size of the window, which is only known after the first layout
+	if g.fitPending && g.screenWidth > 0 {
+		g.fitPending = false
+		g.zoomToFit()
+	}
 	for _, so := range g.spaceObjects {
+		// scale current postion to window
+		so.scaledPosition = g.worldToScreen(so.position)
+		// the speed range is needed to color the path by speed
+		so.trackSpeed()
+	}
+	g.updateTrailLOD()
+}
 
-		// temp
-		if g.time <= dt {
-			so.pathImg = ebiten.NewImageFromImage(screen)
+// extends the path image of the spaceobject to the given screen position
+// objects created after the start (merged or loaded) or with a trail turned on again get a new path image
+func (g *Game) extendPath(so *SpaceObject, screenPosition Vector, screenWidth, screenHeight int) {
+	if so.pathImg == nil {
+		width, height := g.trailImageSize(screenWidth, screenHeight)
+		so.pathImg = ebiten.NewImage(width, height)
+	}
+	so.UpdatePathImage(g.trailPoint(screenPosition), g.trailColor(so), g.trailSpacing*g.trailScale, g.trailStampWidth())
+}
+
+// extends the paths of all spaceobjects with a trail and draws them
+func (g *Game) drawTrails(screen *ebiten.Image) {
+	for _, so := range g.spaceObjects {
+		if !so.showTrail {
+			continue
+		}
+		if so.trailLevel != trailFull {
+			g.drawReducedTrail(screen, so)
+			continue
 		}
 
-		// draw image on screen
-		soImgOptions := &ebiten.DrawImageOptions{}
-		soImgOptions.GeoM.Translate(so.scaledPosition.X, so.scaledPosition.Y)
-		screen.DrawImage(so.img, soImgOptions)
+		// update so internal path image and draw it on screen, scaled from the trail resolution
+		g.extendPath(so, so.scaledPosition, screen.Bounds().Max.X, screen.Bounds().Max.Y)
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(1/g.trailScale, 1/g.trailScale)
+		op.Filter = ebiten.FilterLinear
+		screen.DrawImage(so.pathImg, op)
+	}
+}
 
-		// update so internal path image and draw it on screen
-		so.UpdatePathImage()
-		screen.DrawImage(so.pathImg, nil)
+// draws the images of all spaceobjects
+func (g *Game) drawBodies(screen *ebiten.Image) {
+	for _, so := range g.spaceObjects {
+		// draw image on screen
+		drawSpaceObject(screen, so, g.drawRadius(so))
 
 		fmt.Printf("SO: %s, Position: (%.2f, %.2f), Velocity: (%.2f, %.2f)\n", so.name, so.scaledPosition.X, so.scaledPosition.Y, so.velocity.X, so.velocity.Y)
 	}
+}
+
+func (g *Game) Draw(screen *ebiten.Image) {
+
+	// everything is drawn into the viewport, which clips it to the letterbox
+	viewport := g.viewport()
+	view := screen.SubImage(viewport).(*ebiten.Image)
+	g.drawLayers(view)
+}
+
+// returns the speed of the spacecraft as the first line of the HUD
+// without a spacecraft it is the speed of the focused object, and without a focus there is no speed to show
+func (g *Game) speedStatus() string {
+	index := g.spacecraftIndex()
+	if index < 0 {
+		index = g.focus
+	}
+	if index < 0 || index >= len(g.spaceObjects) {
+		return "Speed: -"
+	}
+	return g.units.FormatSpeed(g.spaceObjects[index].velocity.Length())
+}
 
+// draws the status text at the top left of the viewport
+func (g *Game) drawHUD(view *ebiten.Image) {
+	viewport := g.viewport()
 	size := 12.0
 
-	str := strconv.FormatFloat(g.spaceObjects[0].velocity.Length(), 'f', 2, 64)
+	str := g.speedStatus()
+	str += "\nTime: " + formatDuration(g.time)
+	str += "\nReal time: " + formatDuration(g.clock.elapsed.Seconds())
+	if g.paused {
+		str += " (paused)"
+	}
+	str += "\nIntegrator: " + g.config.integrator.String()
+	if g.config.substeps > 1 {
+		str += " (" + strconv.Itoa(g.config.substeps) + " substeps)"
+	}
+	if g.config.freezePlanets {
+		str += "\nPlanets frozen"
+	}
+	if g.config.freezeSpacecraft {
+		str += "\nSpacecraft frozen"
+	}
+	str += "\nFocus: " + g.focusName()
+	if craft := g.spacecraftIndex(); craft >= 0 {
+		str += "\nFuel: " + strconv.FormatFloat(g.spaceObjects[craft].fuelMass, 'g', 4, 64) + " kg"
+	}
+	if warning := g.decayWarning(); warning != "" {
+		str += "\n" + warning
+	}
+	if status := g.revolutionStatus(); status != "" {
+		str += "\n" + status
+	}
+	if status := g.diffStatus(); status != "" {
+		str += "\n" + status
+	}
+	if status := g.maneuverStatus(); status != "" {
+		str += "\n" + status
+	}
+	if status := g.interceptStatus(); status != "" {
+		str += "\n" + status
+	}
+	if status := g.transferStatus(); status != "" {
+		str += "\n" + status
+	}
+	str += "\nSystem energy: " + strconv.FormatFloat(g.TotalEnergy(), 'g', 4, 64) + " J (drift " + strconv.FormatFloat(g.energyDrift()*100, 'f', 3, 64) + " %)"
+	if acceleration, ok := g.SpacecraftAcceleration(); ok {
+		str += "\nAcceleration: " + strconv.FormatFloat(acceleration, 'g', 4, 64) + " m/s² (" + strconv.FormatFloat(acceleration/standardGravity, 'g', 4, 64) + " g)"
+	}
+	if _, body := g.spacecraftAndDominantBody(); body != nil {
+		str += "\nOrbit: " + g.OrbitClassification().String() + " around " + body.name
+	}
+	if elements, _, ok := g.OrbitalElements(); ok {
+		str += "\nArgument of periapsis: " + strconv.FormatFloat(elements.argumentOfPeriapsis*180/math.Pi, 'f', 1, 64) + "°"
+	}
+	if angle, ok := g.FlightPathAngle(); ok {
+		str += "\nFlight path angle: " + strconv.FormatFloat(angle, 'f', 1, 64) + "°"
+	}
+	if g.apsis.rotated {
+		str += "\nFlyby rotated the orbit around " + g.apsis.rotatedFor.name + " by " + strconv.FormatFloat(g.apsis.rotation*180/math.Pi, 'f', 1, 64) + "°"
+	}
+	if g.replay != nil {
+		str += "\nREPLAY"
+	}
+	if g.recording.active {
+		str += "\nREC " + strconv.Itoa(len(g.recording.Frames)) + " frames"
+	}
+
+	if target := g.selectedObject(); target != nil {
+		str += "\nSelected: " + target.name + ", mass " + strconv.FormatFloat(target.mass, 'g', 4, 64) + " kg"
+	}
+
+	// show how close the spacecraft will get to the selected object
+	if target := g.selectedObject(); target != nil && !target.isSpacecraft {
+		if craft := g.spacecraftIndex(); craft >= 0 {
+			distance, time := g.ClosestApproach(craft, g.selected, g.predictionSteps)
+			str += "\nClosest approach: " + g.units.FormatLength(distance) + " in " + g.units.FormatTime(time-g.time)
+		}
+	}
+
+	if g.input != nil {
+		str += "\n" + g.input.prompt + g.input.value
+	}
+	if g.messageFrames > 0 {
+		str += "\n" + g.message
+	}
 
 	textOp := &text.DrawOptions{}
+	textOp.LineSpacing = size * 1.5
+	textOp.GeoM.Translate(float64(viewport.Min.X), float64(viewport.Min.Y))
 	//textOp.GeoM.Translate(float64(x)+float64(tileSize)/2, float64(y)+float64(tileSize)/2)
 	//textOp.ColorScale.ScaleWithColor(tileColor(v))
 	//textOp.PrimaryAlign = text.AlignCenter
 	//textOp.SecondaryAlign = text.AlignCenter
-	text.Draw(screen, str, &text.GoTextFace{
+	text.Draw(view, str, &text.GoTextFace{
 		Source: mplusFaceSource,
 		Size:   size,
 	}, textOp)
+}
+
+// returns the largest rectangle with the given aspect ratio (width / height) centered in the window
+// an aspect ratio of 0 uses the whole window
+func letterboxViewport(width, height int, aspect float64) image.Rectangle {
+	if aspect <= 0 || width <= 0 || height <= 0 {
+		return image.Rect(0, 0, width, height)
+	}
+
+	// the window is too wide: bars on the left and right
+	if float64(width)/float64(height) > aspect {
+		w := int(math.Round(float64(height) * aspect))
+		x := (width - w) / 2
+		return image.Rect(x, 0, x+w, height)
+	}
+
+	// the window is too high: bars on the top and bottom
+	h := int(math.Round(float64(width) / aspect))
+	y := (height - h) / 2
+	return image.Rect(0, y, width, y+h)
+}
 
+// returns the part of the window the scene is rendered to
+func (g *Game) viewport() image.Rectangle {
+	return letterboxViewport(g.screenWidth, g.screenHeight, g.aspectRatio)
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
@@ -313,10 +1325,294 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 	return outsideWidth, outsideHeight
 }
 
+// runs the app until it is closed
+// if the game fails, its last state is saved to the crash file so long runs can be resumed
+func run(app *App) error {
+	err := ebiten.RunGame(app)
+	if err == nil {
+		return nil
+	}
+
+	// the game never started if it failed in the menu, so there is nothing to save
+	game := app.game
+	if game == nil {
+		return err
+	}
+
+	elapsed := game.units.FormatTime(game.time)
+	if saveErr := game.SaveState(crashSavePath); saveErr != nil {
+		return fmt.Errorf("simulation failed after %s of simulated time: %w (saving the state failed: %v)", elapsed, err, saveErr)
+	}
+	return fmt.Errorf("simulation failed after %s of simulated time, state saved to %s: %w", elapsed, crashSavePath, err)
+}
+
 func main() {
-	ebiten.SetWindowSize(1080, 720)
+	debug := flag.Bool("debug", false, "warn when momentum or energy are not conserved")
+	seed := flag.Uint64("seed", defaultSeed, "seed of the random number generator, the same seed gives the same run")
+	unitsName := flag.String("units", "si", "units shown in the HUD (si, astro)")
+	forceExponent := flag.Float64("force-exponent", 2.0, "exponent of the distance in the gravity law")
+	gScale := flag.Float64("g-scale", 1, "multiplier of the gravitational constant, the velocities of the scene are scaled to keep its orbits")
+	gameMode := flag.Bool("game-mode", false, "use the arcade gravity preset (g-scale 100, orbits 10 times faster)")
+	trailSpacing := flag.Float64("trail-spacing", 2, "minimum distance in pixel between two points of a path")
+	trailScale := flag.Float64("trail-scale", 1, "resolution of the trail images relative to the screen, below 1 saves memory, above 1 gives crisper trails")
+	trailWidth := flag.Float64("trail-width", 1, "thickness of the trails in pixel")
+	trailLODFull := flag.Int("trail-lod-full", 0, "number of bodies nearest to the camera with a full trail besides the selected one, 0 keeps every trail full")
+	trailLODShort := flag.Int("trail-lod-short", 0, "with --trail-lod-full, number of further bodies with a short trail, the rest get none")
+	cutoff := flag.Float64("cutoff", 0, "distance in m beyond which bodies do not attract each other, 0 to disable")
+	substeps := flag.Int("substeps", 1, "number of smaller steps every time step is split into")
+	useSpatialHash := flag.Bool("spatial-hash", false, "find the bodies within the cutoff with a grid instead of checking all pairs, needs --cutoff")
+	workers := flag.Int("workers", runtime.GOMAXPROCS(0), "number of goroutines the forces are computed with")
+	dragDensity := flag.Float64("drag-density", 0, "atmosphere density at the surface of the planets in kg/m^3, 0 disables drag")
+	scaleHeight := flag.Float64("scale-height", defaultScaleHeight, "height in m over which the atmosphere density drops by a factor of e")
+	predictionSteps := flag.Int("prediction-steps", defaultPredictionSteps, "number of time steps the trajectory prediction looks ahead")
+	float32Forces := flag.Bool("float32", false, "compute the gravitational forces in float32, see calculateGravitationalForce32")
+	testParticle := flag.Bool("test-particle", false, "treat the spacecraft as massless test particle that does not pull on the other bodies")
+	zeroMomentum := flag.Bool("zero-momentum", false, "remove the drift of the whole system by making its total momentum zero")
+	zoomStep := flag.Float64("zoom-step", 1.25, "factor the zoom changes by per mouse wheel notch")
+	cameraSmoothing := flag.Float64("camera-smoothing", 1, "fraction of the way to the focus the camera moves per frame, 1 snaps to it")
+	dominanceCell := flag.Int("dominance-cell", defaultDominanceCell, "edge length in pixel of the cells of the dominance overlay")
+	dominanceAlpha := flag.Uint("dominance-alpha", uint(defaultDominanceAlpha), "opacity (0-255) of the dominance overlay")
+	sceneName := flag.String("scene", "", "scene to start without showing the menu (flyby, binary, solar, tether, slingshot) or a json scene file")
+	bodiesPath := flag.String("bodies", "", "csv file (name, mass, x, y, vx, vy) to load the bodies from instead of a scene")
+	keysPath := flag.String("keys", "", "json file overriding the default key bindings")
+	savePath := flag.String("save", defaultSavePath, "file the state is saved to and loaded from, use the .gob extension for the compact binary format")
+	ghostTrails := flag.Int("ghost-trails", 0, "number of previous runs whose trails stay visible after a reset")
+	nudgeStep := flag.Float64("nudge-step", defaultNudgeStep, "distance in m the arrow keys move the selected body per frame while paused")
+	stageMass := flag.Float64("stage-mass", defaultStageMass, "mass in kg a staging drops from the spacecraft")
+	minDryMass := flag.Float64("min-dry-mass", defaultMinDryMass, "mass in kg the spacecraft cannot be staged below")
+	separationSpeed := flag.Float64("separation-speed", 0, "speed in m/s a staging pushes the spacecraft along its heading")
+	ringSpacing := flag.Float64("ring-spacing", defaultRingSpacing, "distance in m between the distance rings around the selected body")
+	ringCount := flag.Int("ring-count", defaultRingCount, "number of distance rings around the selected body")
+	energyPlot := flag.Bool("energy-plot", false, "show the energy plot from the start")
+	energyPlotLength := flag.Int("energy-plot-length", defaultEnergyPlotLength, "number of recent steps the energy plot shows")
+	escapeMargin := flag.Float64("escape-margin", defaultEscapeMargin, "multiple of the escape velocity the escape command sets")
+	escapeRadial := flag.Bool("escape-radial", false, "the escape command points away from the body instead of prograde")
+	maxBodies := flag.Int("max-bodies", defaultMaxBodies, "largest number of bodies a simulation may have, larger scenes and saves are rejected")
+	fastForward := flag.Bool("fast-forward", false, "after loading a state, fast-forward through the recording saved with it up to the saved time")
+	replayPath := flag.String("replay", "", "recording to play back instead of simulating")
+	diffPaths := flag.String("diff", "", "two comma separated recordings to compare: prints their position differences and overlays both paths")
+	headless := flag.Bool("headless", false, "run the simulation without a window and print the final state as json")
+	smoke := flag.Bool("smoke", false, "set up the scene, simulate one step and update the view without a window and exit, for checking a build on machines without a display; nothing is drawn")
+	steps := flag.Int("steps", 1000, "number of time steps to simulate in headless mode")
+	framesDir := flag.String("render-frames", "", "render the scene for --steps steps into numbered pngs in this directory instead of running it interactively")
+	frameInterval := flag.Int("frame-interval", 1, "with --render-frames, write every n-th step as a frame")
+	trials := flag.Int("trials", 0, "number of monte carlo trials with a perturbed spacecraft velocity in headless mode, 0 for a single run")
+	sigma := flag.Float64("sigma", 10, "standard deviation in m/s of the spacecraft velocity perturbation of the monte carlo trials")
+	divergence := flag.Float64("divergence", 0, "in headless mode, run the scene twice with the spacecraft moved by this many m and print how far the runs diverge")
+	endEscape := flag.Float64("end-escape", 0, "end a headless run after the spacecraft had a positive orbital energy for this many seconds, 0 to disable")
+	endCrash := flag.Bool("end-crash", false, "end a headless run when the spacecraft crashes")
+	pauseOnCrash := flag.Bool("pause-on-crash", false, "pause the simulation with a message when the spacecraft crashes")
+	pauseOnEscape := flag.Bool("pause-on-escape", false, "pause the simulation with a message when the orbit of the spacecraft becomes hyperbolic")
+	timeLimit := flag.Float64("time-limit", 0, "end a headless run once this many seconds are simulated, 0 to disable")
+	aspectRatio := flag.Float64("aspect", 0, "fixed aspect ratio (width / height) of the scene, 0 to fill the window")
+	trailsOverBodies := flag.Bool("trails-over-bodies", false, "draw the trails on top of the bodies instead of below them")
+	layers := flag.String("layers", "", "comma separated render layers from bottom to top, unlisted layers are hidden (default "+layerNames(defaultRenderLayers())+")")
+	nodeAxis := flag.Float64("node-axis", 0, "angle of the reference axis for the orbit node markers in degrees")
+	flag.Parse()
+
+	units, err := unitSystemByName(*unitsName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var renderLayers []renderLayer
+	if *layers != "" {
+		renderLayers, err = parseRenderLayers(*layers)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	keys := DefaultKeyBindings()
+	if *keysPath != "" {
+		keys, err = LoadKeyBindings(*keysPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var replay *Recording
+	if *replayPath != "" {
+		replay, err = LoadRecording(*replayPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// the diff is reported right away, in headless mode there is nothing else to do with it
+	var diff *TrajectoryDiff
+	if *diffPaths != "" {
+		diff, err = loadTrajectoryDiff(*diffPaths)
+		if err != nil {
+			log.Fatal(err)
+		}
+		diff.write(os.Stdout, units)
+		if *headless {
+			return
+		}
+	}
+
+	// applies the command line settings to every game started from the menu
+	configure := func(game *Game) {
+		game.debug = *debug
+		game.reseed(*seed)
+		game.units = units
+		game.config.forceExponent = *forceExponent
+		if *gameMode {
+			game.setGScale(gameGScale)
+		} else {
+			game.setGScale(*gScale)
+		}
+		if *zeroMomentum {
+			game.zeroNetMomentum()
+		}
+		game.config.float32Forces = *float32Forces
+		game.config.spacecraftGravitates = !*testParticle
+		if *cutoff > 0 {
+			game.config.cutoffDistance = *cutoff
+		}
+		game.config.substeps = *substeps
+		game.config.spatialHash = *useSpatialHash
+		game.config.numWorkers = *workers
+		game.config.atmosphereDensity = *dragDensity
+		game.config.atmosphereScaleHeight = *scaleHeight
+		game.aspectRatio = *aspectRatio
+		if *layers != "" {
+			game.layers = append([]renderLayer(nil), renderLayers...)
+		}
+		if *trailsOverBodies {
+			game.drawTrailsOverBodies()
+		}
+		game.nodeAxis = *nodeAxis * math.Pi / 180
+		game.trailSpacing = *trailSpacing
+		game.trailWidth = *trailWidth
+		game.trailLOD = trailLODConfig{full: *trailLODFull, short: *trailLODShort}
+		if *trailScale > 0 {
+			game.trailScale = *trailScale
+		}
+		game.keys = keys
+		game.camera.zoomFactor = *zoomStep
+		game.camera.smoothing = *cameraSmoothing
+		game.dominance.cell = *dominanceCell
+		game.dominance.alpha = uint8(*dominanceAlpha)
+		game.predictionSteps = *predictionSteps
+		game.savePath = *savePath
+		game.escapeMargin = *escapeMargin
+		game.nudgeStep = *nudgeStep
+		game.ringSpacing = *ringSpacing
+		game.ringCount = *ringCount
+		game.stageMass = *stageMass
+		game.minDryMass = *minDryMass
+		game.separationSpeed = *separationSpeed
+		game.showEnergyPlot = *energyPlot
+		game.energyHistory = newEnergyHistory(max(0, *energyPlotLength))
+		game.maxGhosts = *ghostTrails
+		game.escapeRadial = *escapeRadial
+		game.maxBodies = *maxBodies
+		game.fastForwardLoads = *fastForward
+		game.autoPause = AutoPause{onCrash: *pauseOnCrash, onEscape: *pauseOnEscape}
+		game.endCondition = EndCondition{escapeDuration: *endEscape, onCrash: *endCrash, timeLimit: *timeLimit}
+		game.diff = diff
+		if replay != nil {
+			game.startReplay(replay)
+		}
+	}
+
+	// a scene or body file given on the command line skips the menu
+	var scene *Scene
+	switch {
+	case *bodiesPath != "":
+		bodies, err := newBodiesScene(*bodiesPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		scene = &bodies
+	case strings.HasSuffix(*sceneName, ".json"):
+		file, err := LoadSceneFile(*sceneName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		scene = &file
+	case *sceneName != "":
+		named, err := sceneByName(*sceneName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		scene = &named
+	}
+
+	if *smoke {
+		if scene == nil {
+			scene = &scenes[0]
+		}
+		game := scene.create()
+		configure(game)
+		if err := runSmoke(game, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if *framesDir != "" {
+		if scene == nil {
+			scene = &scenes[0]
+		}
+		game := scene.create()
+		configure(game)
+		if err := game.checkBodyCount(); err != nil {
+			log.Fatal(err)
+		}
+		if err := renderFrames(game, *framesDir, *steps, *frameInterval); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if *headless {
+		if scene == nil {
+			scene = &scenes[0]
+		}
+		create := func() *Game {
+			game := scene.create()
+			configure(game)
+			return game
+		}
+		if *trials > 0 {
+			rng := rand.New(rand.NewPCG(*seed, *seed))
+			if err := runMonteCarlo(create, *trials, *steps, *sigma, rng, os.Stdout); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+		if *divergence > 0 {
+			rng := rand.New(rand.NewPCG(*seed, *seed))
+			if err := runDivergence(create, *divergence, *steps, rng, os.Stdout); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+
+		game := scene.create()
+		configure(game)
+		if err := game.checkBodyCount(); err != nil {
+			log.Fatal(err)
+		}
+		if err := runHeadless(game, *steps, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	app := NewApp(configure)
+	if scene != nil {
+		if err := app.Start(*scene); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	ebiten.SetWindowSize(windowWidth, windowHeight)
 	ebiten.SetWindowTitle("swingby")
-	if err := ebiten.RunGame(NewGame()); err != nil {
+	if err := run(app); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"log"
+	"math"
+	"os"
+	"testing"
+)
+
+const (
+	testStarMass    float64 = 2e30   // mass of the star of the test orbits in kg
+	testOrbitRadius float64 = 1.5e11 // radius of the circular test orbit in m
+	testStarRadius  float64 = 7e8    // radius of the star in m
+)
+
+// returns a game with a star at rest at the origin and a light spacecraft on a counterclockwise circular orbit
+func circularOrbitGame(radius float64) *Game {
+	g := newGame()
+	star := &SpaceObject{name: "star", mass: testStarMass, radius: testStarRadius}
+	speed := g.config.CircularOrbitVelocity(star.mass, radius)
+	craft := &SpaceObject{name: "craft", mass: 1, radius: 1, isSpacecraft: true, position: Vector{radius, 0}, velocity: Vector{0, speed}}
+	g.spaceObjects = []*SpaceObject{star, craft}
+	return g
+}
+
+// returns a game with a star at rest at the origin and a light spacecraft on the given orbit around it
+func ellipticOrbitGame(semiMajorAxis, eccentricity, argumentOfPeriapsis, trueAnomaly float64) *Game {
+	g := circularOrbitGame(semiMajorAxis)
+	craft := g.spaceObjects[1]
+	craft.position, craft.velocity = stateFromElements(semiMajorAxis, eccentricity, argumentOfPeriapsis, trueAnomaly,
+		g.config.gravitationalConstant()*testStarMass, false)
+	return g
+}
+
+// collects everything logged during the test
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+// returns the total mass and momentum of the spaceobjects
+func massAndMomentum(spaceObjects ...*SpaceObject) (float64, Vector) {
+	mass := 0.0
+	var momentum Vector
+	for _, so := range spaceObjects {
+		mass += so.mass
+		momentum = momentum.Translate(so.velocity.X*so.mass, so.velocity.Y*so.mass)
+	}
+	return mass, momentum
+}
+
+func TestMergeConservesMassAndMomentum(t *testing.T) {
+	tests := []struct {
+		name     string
+		so1, so2 SpaceObject
+	}{
+		{"head on", SpaceObject{mass: 2, velocity: Vector{3, 0}}, SpaceObject{mass: 1, velocity: Vector{-6, 0}}},
+		{"at an angle", SpaceObject{mass: 5.97e24, velocity: Vector{0, 3e4}}, SpaceObject{mass: 7.3e22, velocity: Vector{1e3, 2.9e4}}},
+		{"equal masses", SpaceObject{mass: 1, velocity: Vector{1, 2}}, SpaceObject{mass: 1, velocity: Vector{-3, 4}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			massBefore, momentumBefore := massAndMomentum(&tt.so1, &tt.so2)
+			massAfter, momentumAfter := massAndMomentum(mergeSpaceObjects(&tt.so1, &tt.so2))
+
+			if math.Abs(massAfter-massBefore) > 1e-12*massBefore {
+				t.Errorf("mass %g, want %g", massAfter, massBefore)
+			}
+			scale := math.Max(1, momentumBefore.Length())
+			if d := math.Sqrt(momentumAfter.DistanceSquared(momentumBefore)); d > 1e-12*scale {
+				t.Errorf("momentum %v, want %v", momentumAfter, momentumBefore)
+			}
+		})
+	}
+}
+
+func TestMergeKeepsTheHeavierObject(t *testing.T) {
+	craft := &SpaceObject{
+		name: "craft", mass: 10, isSpacecraft: true, showTrail: true,
+		heading: 1, thrust: 5, fuelMass: 4, exhaustVelocity: 3000,
+		mode: modeRails, rails: &railsOrbit{parent: "earth", set: true}, trailLevel: trailShort,
+	}
+	debris := &SpaceObject{name: "debris", mass: 1, fuelMass: 1}
+
+	merged := mergeSpaceObjects(debris, craft)
+	if merged.name != "craft" || !merged.isSpacecraft || !merged.showTrail {
+		t.Errorf("merged object is %q, spacecraft %v, trail %v", merged.name, merged.isSpacecraft, merged.showTrail)
+	}
+	if merged.heading != 1 || merged.thrust != 5 || merged.fuelMass != 4 || merged.exhaustVelocity != 3000 {
+		t.Errorf("engine not taken over: %+v", merged)
+	}
+	if merged.mode != modeRails || merged.rails == nil || merged.rails.parent != "earth" || merged.trailLevel != trailShort {
+		t.Errorf("mode, rails or trail level not taken over: %+v", merged)
+	}
+	if merged.rails.set || !craft.rails.set {
+		t.Errorf("the merged orbit has to be derived again without touching the old one")
+	}
+}
+
+func TestForceExponent(t *testing.T) {
+	so1 := SpaceObject{mass: 5.97e24, position: Vector{1.5e11, 2e10}}
+	so2 := SpaceObject{mass: 2e30, position: Vector{-3e9, 4e9}}
+	distance := math.Sqrt(so1.position.DistanceSquared(so2.position))
+	config := DefaultSimConfig()
+
+	// newtonian gravity G*m1*m2/r^2 towards so2
+	force := calculateGravitationalForce(so1, so2, config)
+	want := gravitation * so1.mass * so2.mass / (distance * distance)
+	if math.Abs(force.Length()-want) > 1e-15*want {
+		t.Errorf("force %g N with exponent 2, want %g N", force.Length(), want)
+	}
+	if force.Dot(so2.position.Translate(-so1.position.X, -so1.position.Y)) <= 0 {
+		t.Errorf("force %v does not point towards the other body", force)
+	}
+
+	// over a run the spacecraft ends up somewhere else with a different exponent
+	tests := []struct {
+		exponent float64
+		differs  bool
+	}{
+		{2.0, false},
+		{2.1, true},
+		{1.9, true},
+	}
+	reference := NewGame()
+	for i := 0; i < 100; i++ {
+		reference.Step()
+	}
+	for _, tt := range tests {
+		g := NewGame()
+		g.config.forceExponent = tt.exponent
+		for i := 0; i < 100; i++ {
+			g.Step()
+		}
+		craft := g.spaceObjects[g.spacecraftIndex()].position
+		d := math.Sqrt(craft.DistanceSquared(reference.spaceObjects[reference.spacecraftIndex()].position))
+		if tt.differs && d < 1e3 {
+			t.Errorf("exponent %g moved the spacecraft only %g m", tt.exponent, d)
+		}
+		if !tt.differs && d != 0 {
+			t.Errorf("exponent %g moved the spacecraft %g m, want the newtonian trajectory exactly", tt.exponent, d)
+		}
+	}
+}
+
+func TestNearestPlanet(t *testing.T) {
+	g := newGame()
+	g.spaceObjects = []*SpaceObject{
+		{name: "near", position: Vector{3, 4}},
+		{name: "middle", position: Vector{-10, 0}},
+		{name: "far", position: Vector{0, 100}},
+		{name: "craft", isSpacecraft: true, position: Vector{0, 0.5}},
+	}
+
+	tests := []struct {
+		to       Vector
+		nearest  string
+		distance float64
+	}{
+		{Vector{0, 0}, "near", 5},
+		{Vector{-10, 2}, "middle", 2},
+		{Vector{0, 80}, "far", 20},
+	}
+	for _, tt := range tests {
+		so, distance := g.NearestPlanet(tt.to)
+		if so == nil || so.name != tt.nearest || math.Abs(distance-tt.distance) > 1e-12 {
+			t.Errorf("nearest planet to %v is %v at %g, want %s at %g", tt.to, so, distance, tt.nearest, tt.distance)
+		}
+	}
+
+	g.spaceObjects = g.spaceObjects[3:]
+	if so, _ := g.NearestPlanet(Vector{}); so != nil {
+		t.Errorf("nearest planet without planets is %s, want none", so.name)
+	}
+}
+
+func TestLetterboxViewport(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		aspect        float64
+		want          image.Rectangle
+	}{
+		{"fill", 800, 600, 0, image.Rect(0, 0, 800, 600)},
+		{"matching", 800, 600, 4.0 / 3, image.Rect(0, 0, 800, 600)},
+		{"too wide", 1000, 500, 1, image.Rect(250, 0, 750, 500)},
+		{"too high", 400, 1000, 2, image.Rect(0, 400, 400, 600)},
+		{"16:9 in 4:3", 1024, 768, 16.0 / 9, image.Rect(0, 96, 1024, 672)},
+		{"empty window", 0, 0, 1, image.Rect(0, 0, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := letterboxViewport(tt.width, tt.height, tt.aspect); got != tt.want {
+				t.Errorf("viewport %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFreezing(t *testing.T) {
+	g := circularOrbitGame(testOrbitRadius)
+	g.config.spacecraftGravitates = true
+	g.spaceObjects[1].mass = testStarMass / 10
+	star, craft := *g.spaceObjects[0], *g.spaceObjects[1]
+
+	// frozen planets keep their state but still pull the spacecraft
+	g.config.freezePlanets = true
+	for range 10 {
+		g.Step()
+	}
+	if so := g.spaceObjects[0]; so.position != star.position || so.velocity != star.velocity {
+		t.Errorf("frozen star moved to %v at %v m/s", so.position, so.velocity)
+	}
+	if so := g.spaceObjects[1]; so.velocity.X >= 0 {
+		t.Errorf("spacecraft moves at %v m/s, want pulled towards the frozen star", so.velocity)
+	}
+
+	// a frozen spacecraft keeps its state but still pulls the star
+	g = circularOrbitGame(testOrbitRadius)
+	g.config.spacecraftGravitates = true
+	g.spaceObjects[1].mass = testStarMass / 10
+	g.config.freezeSpacecraft = true
+	for range 10 {
+		g.Step()
+	}
+	if so := g.spaceObjects[1]; so.position != craft.position || so.velocity != craft.velocity {
+		t.Errorf("frozen spacecraft moved to %v at %v m/s", so.position, so.velocity)
+	}
+	if so := g.spaceObjects[0]; so.velocity.X <= 0 {
+		t.Errorf("star moves at %v m/s, want pulled towards the frozen spacecraft", so.velocity)
+	}
+}
+
+func TestSameSeedGivesTheSameRun(t *testing.T) {
+	// a game with random bodies from its own generator, stepped a few times
+	run := func(seed uint64) *Game {
+		g := newGame()
+		g.reseed(seed)
+		for range 5 {
+			g.spaceObjects = append(g.spaceObjects, CreateRandomSpaceObject(g.rng))
+		}
+		for range 50 {
+			g.Step()
+		}
+		return g
+	}
+	sameState := func(a, b *Game) bool {
+		if len(a.spaceObjects) != len(b.spaceObjects) || a.time != b.time {
+			return false
+		}
+		for i, so := range a.spaceObjects {
+			other := b.spaceObjects[i]
+			if so.mass != other.mass || so.position != other.position || so.velocity != other.velocity {
+				return false
+			}
+		}
+		return true
+	}
+
+	if !sameState(run(42), run(42)) {
+		t.Error("two runs with the same seed differ")
+	}
+	if sameState(run(42), run(43)) {
+		t.Error("two runs with different seeds are identical")
+	}
+
+	// without a seed every game starts from defaultSeed
+	if a, b := newGame(), newGame(); a.rng.Float64() != b.rng.Float64() {
+		t.Error("two unseeded games draw different random numbers")
+	}
+}
+
+func TestScaleSelectedMass(t *testing.T) {
+	g := circularOrbitGame(testOrbitRadius)
+	star, craft := g.spaceObjects[0], g.spaceObjects[1]
+	before := g.config.CircularOrbitVelocity(star.mass, testOrbitRadius)
+	pull := netForce(g.spaceObjects, nil, 1, g.config)
+
+	g.selected = 0
+	g.scaleSelectedMass(2)
+	if star.mass != 2*testStarMass {
+		t.Fatalf("mass after doubling is %v kg, want %v", star.mass, 2*testStarMass)
+	}
+	// the circular velocity grows with the square root of the mass
+	if after := g.config.CircularOrbitVelocity(star.mass, testOrbitRadius); math.Abs(after/before-math.Sqrt2) > 1e-12 {
+		t.Errorf("circular velocity grew by %v, want sqrt(2)", after/before)
+	}
+	// the next step already feels the new mass
+	if doubled := netForce(g.spaceObjects, nil, 1, g.config); math.Abs(doubled.X/pull.X-2) > 1e-12 {
+		t.Errorf("pull on the craft grew by %v, want 2", doubled.X/pull.X)
+	}
+
+	// the mass does not drop below the minimum, and the fuel not above the mass
+	g.selected = 1
+	craft.mass, craft.fuelMass = 3, 2.5
+	g.scaleSelectedMass(0.5)
+	if craft.mass != 1.5 || craft.fuelMass != 1.5 {
+		t.Errorf("halved craft has %v kg with %v kg fuel, want 1.5 kg with 1.5 kg", craft.mass, craft.fuelMass)
+	}
+	g.scaleSelectedMass(0.5)
+	if craft.mass != minBodyMass {
+		t.Errorf("craft halved below the minimum has %v kg, want %v", craft.mass, minBodyMass)
+	}
+
+	// without a selection nothing changes
+	g.selected = -1
+	g.scaleSelectedMass(2)
+	if star.mass != 2*testStarMass || craft.mass != minBodyMass {
+		t.Errorf("masses changed without a selection to %v and %v kg", star.mass, craft.mass)
+	}
+}
+
+func TestNudgeMovesOnlyTheSelectedBody(t *testing.T) {
+	tests := []struct {
+		name      string
+		direction Vector
+		fine      bool
+		want      Vector // offset in m
+	}{
+		{"left", Vector{-1, 0}, false, Vector{-defaultNudgeStep, 0}},
+		{"up", Vector{0, -1}, false, Vector{0, -defaultNudgeStep}},
+		{"down and right", Vector{1, 1}, false, Vector{defaultNudgeStep, defaultNudgeStep}},
+		{"fine", Vector{1, 0}, true, Vector{defaultNudgeStep / fineNudgeDivisor, 0}},
+		{"no key", Vector{}, false, Vector{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := circularOrbitGame(testOrbitRadius)
+			g.selected = 1
+			star, craft := *g.spaceObjects[0], *g.spaceObjects[1]
+
+			g.nudge(test.direction, test.fine)
+			moved := g.spaceObjects[1]
+			if want := craft.position.Translate(test.want.X, test.want.Y); moved.position != want {
+				t.Errorf("craft is at %v, want %v", moved.position, want)
+			}
+			if moved.velocity != craft.velocity {
+				t.Errorf("nudging changed the velocity of the craft to %v", moved.velocity)
+			}
+			if test.want != (Vector{}) && moved.scaledPosition != g.worldToScreen(moved.position) {
+				t.Errorf("craft is drawn at %v, not at its new position %v", moved.scaledPosition, g.worldToScreen(moved.position))
+			}
+			if other := g.spaceObjects[0]; other.position != star.position || other.velocity != star.velocity {
+				t.Errorf("nudging the craft moved the star to %v", other.position)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	nodeHandleBase   float64 = 20  // distance of a handle from its node at zero delta-v in pixel
+	nodeHandleScale  float64 = 0.1 // pixel a handle moves per m/s of delta-v
+	nodeHandleRadius float32 = 5   // radius of a handle in pixel, also how close a click has to be
+	nodeRadius       float32 = 6   // radius of the node marker in pixel
+)
+
+var (
+	maneuverNodeColor = color.RGBA{255, 255, 255, 220}
+	progradeColor     = color.RGBA{120, 255, 120, 255}
+	radialColor       = color.RGBA{120, 200, 255, 255}
+)
+
+// ManeuverNode is a planned burn at a point of the predicted trajectory
+// the delta-v is split into a prograde part along the velocity relative to the dominant body and a radial part
+// perpendicular to it, pointing away from the body
+type ManeuverNode struct {
+	time     float64 // simulated time the burn is done at in s
+	prograde float64 // m/s, negative is retrograde
+	radial   float64 // m/s, negative is radial in
+}
+
+// a handle of a maneuver node in screen space
+type nodeHandle struct {
+	node      *ManeuverNode
+	radial    bool   // the handle sets the radial instead of the prograde delta-v
+	origin    Vector // position of the node in pixel
+	direction Vector // direction the handle moves in for positive delta-v
+}
+
+// returns the position of the handle in pixel
+func (h nodeHandle) position() Vector {
+	dv := h.node.prograde
+	if h.radial {
+		dv = h.node.radial
+	}
+	distance := nodeHandleBase + dv*nodeHandleScale
+	return h.origin.Translate(h.direction.X*distance, h.direction.Y*distance)
+}
+
+// returns the burn the node does, for the prediction
+func (n *ManeuverNode) burn(body *SpaceObject) scheduledBurn {
+	return scheduledBurn{time: n.time, prograde: n.prograde, radial: n.radial, body: body.name}
+}
+
+// returns the step of the prediction the node falls into, -1 if it is not within the predicted steps
+func (g *Game) nodeStep(n *ManeuverNode, steps int) int {
+	k := int(math.Round((n.time - g.time) / dt))
+	if k < 0 || k >= steps {
+		return -1
+	}
+	return k
+}
+
+// returns the screen position of every node on the predicted trajectory together with its two handles
+// the directions come from the predicted positions relative to the dominant body around the node
+func (g *Game) nodeHandles() []nodeHandle {
+	craft, body := g.spacecraftAndDominantBody()
+	if craft == nil || body == nil || len(g.nodes) == 0 {
+		return nil
+	}
+	craftIndex, bodyIndex := g.objectIndex(craft), g.objectIndex(body)
+	trajectory := g.predictTrajectory(g.predictionSteps, g.plannedBurns())
+	relative := func(k int) Vector {
+		k = max(0, min(k, len(trajectory)-1))
+		return trajectory[k][craftIndex].Translate(-trajectory[k][bodyIndex].X, -trajectory[k][bodyIndex].Y)
+	}
+
+	var handles []nodeHandle
+	for _, n := range g.nodes {
+		k := g.nodeStep(n, len(trajectory))
+		if k < 0 {
+			continue
+		}
+		// the velocity before the burn is the direction from the previous to the next point, and the
+		// position is drawn relative to where the focus is now, like the prediction
+		prograde := relative(k+1).Translate(-relative(k-1).X, -relative(k-1).Y).Normalize()
+		radial := perpendicularAway(prograde, relative(k))
+		origin := g.worldToScreen(g.predictedDrawPosition(trajectory[k], craftIndex))
+		handles = append(handles,
+			nodeHandle{node: n, origin: origin, direction: prograde},
+			nodeHandle{node: n, radial: true, origin: origin, direction: radial},
+		)
+	}
+	return handles
+}
+
+// returns the direction perpendicular to the given one that points away from the body at the relative position r
+func perpendicularAway(direction, r Vector) Vector {
+	perpendicular := Vector{-direction.Y, direction.X}
+	if perpendicular.Dot(r) < 0 {
+		perpendicular = Vector{direction.Y, -direction.X}
+	}
+	return perpendicular
+}
+
+// returns where the spaceobject at the given index of a predicted step is drawn
+// in a focus frame the predicted position follows where the focused object will be, like in drawPrediction
+func (g *Game) predictedDrawPosition(positions []Vector, index int) Vector {
+	p := positions[index]
+	if g.focus >= 0 && g.focus < len(positions) {
+		focusNow := g.focusPosition()
+		p = p.Translate(focusNow.X-positions[g.focus].X, focusNow.Y-positions[g.focus].Y)
+	}
+	return p
+}
+
+// places a maneuver node at the point of the predicted trajectory closest to the given screen position
+func (g *Game) placeManeuverNode(screenPosition Vector) {
+	craft := g.spacecraftIndex()
+	if craft < 0 {
+		return
+	}
+	trajectory := g.predictTrajectory(g.predictionSteps, g.plannedBurns())
+	best, closest := -1, math.Inf(1)
+	for k, positions := range trajectory {
+		d := g.worldToScreen(g.predictedDrawPosition(positions, craft)).DistanceSquared(screenPosition)
+		if d < closest {
+			best, closest = k, d
+		}
+	}
+	if best >= 0 {
+		g.nodes = append(g.nodes, &ManeuverNode{time: g.time + float64(best)*dt})
+	}
+}
+
+// starts dragging the handle under the cursor when the mouse is pressed and moves it while held
+// returns true while a handle is dragged, so the click does not select anything
+func (g *Game) dragNodeHandles() bool {
+	x, y := ebiten.CursorPosition()
+	cursor := Vector{float64(x), float64(y)}
+
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		for _, h := range g.nodeHandles() {
+			if h.position().DistanceSquared(cursor) <= float64(nodeHandleRadius*nodeHandleRadius) {
+				g.dragging = &h
+				break
+			}
+		}
+	}
+	if g.dragging == nil {
+		return false
+	}
+	if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		g.dragging = nil
+		return false
+	}
+
+	// the delta-v follows the distance of the cursor from the node along the handle direction
+	h := g.dragging
+	along := cursor.Translate(-h.origin.X, -h.origin.Y).Dot(h.direction)
+	dv := (along - nodeHandleBase) / nodeHandleScale
+	if h.radial {
+		h.node.radial = dv
+	} else {
+		h.node.prograde = dv
+	}
+	return true
+}
+
+// returns the burns of the maneuver nodes relative to the current dominant body
+func (g *Game) nodeBurns() []scheduledBurn {
+	_, body := g.spacecraftAndDominantBody()
+	if body == nil {
+		return nil
+	}
+	burns := make([]scheduledBurn, len(g.nodes))
+	for i, n := range g.nodes {
+		burns[i] = n.burn(body)
+	}
+	return burns
+}
+
+// does the burn of the node with the spacecraft now and removes the node
+func (g *Game) executeManeuverNode(n *ManeuverNode) {
+	for i, other := range g.nodes {
+		if other == n {
+			g.nodes = append(g.nodes[:i], g.nodes[i+1:]...)
+			break
+		}
+	}
+	if g.dragging != nil && g.dragging.node == n {
+		g.dragging = nil
+	}
+
+	craft, body := g.spacecraftAndDominantBody()
+	if craft == nil || body == nil {
+		return
+	}
+	r := craft.position.Translate(-body.position.X, -body.position.Y)
+	prograde := craft.velocity.Translate(-body.velocity.X, -body.velocity.Y).Normalize()
+	radial := perpendicularAway(prograde, r)
+	dv := Vector{prograde.X*n.prograde + radial.X*n.radial, prograde.Y*n.prograde + radial.Y*n.radial}
+	if err := craft.applyDeltaV(dv); err != nil {
+		log.Printf("maneuver failed: %v\n", err)
+	}
+	g.baseline.set = false
+}
+
+// executes the nodes whose time has come, in the step like the second transfer burn
+func (g *Game) updateManeuverNodes() {
+	for _, n := range append([]*ManeuverNode(nil), g.nodes...) {
+		if g.time >= n.time {
+			g.executeManeuverNode(n)
+		}
+	}
+}
+
+// returns the node that comes next, nil if there is none
+func (g *Game) nextManeuverNode() *ManeuverNode {
+	var next *ManeuverNode
+	for _, n := range g.nodes {
+		if next == nil || n.time < next.time {
+			next = n
+		}
+	}
+	return next
+}
+
+// draws the nodes on the predicted trajectory with their prograde and radial handles
+func (g *Game) drawManeuverNodes(screen *ebiten.Image) {
+	for _, h := range g.nodeHandles() {
+		handleColor := progradeColor
+		if h.radial {
+			handleColor = radialColor
+		} else {
+			vector.StrokeCircle(screen, float32(h.origin.X), float32(h.origin.Y), nodeRadius, 1.5, maneuverNodeColor, true)
+		}
+		p := h.position()
+		vector.StrokeLine(screen, float32(h.origin.X), float32(h.origin.Y), float32(p.X), float32(p.Y), 1, handleColor, true)
+		vector.DrawFilledCircle(screen, float32(p.X), float32(p.Y), nodeHandleRadius, handleColor, true)
+	}
+}
+
+// returns the next node as a line of the HUD, empty if there is none
+func (g *Game) maneuverStatus() string {
+	n := g.nextManeuverNode()
+	if n == nil {
+		return ""
+	}
+	return fmt.Sprintf("Maneuver in %s: prograde %s, radial %s (%d planned)",
+		formatDuration(n.time-g.time), g.units.FormatSpeed(n.prograde), g.units.FormatSpeed(n.radial), len(g.nodes))
+}
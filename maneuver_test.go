@@ -0,0 +1,61 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestManeuverNodeBurnsAtItsTime(t *testing.T) {
+	const (
+		nodeStep = 5
+		prograde = 2e3 // m/s
+		radial   = -1e3
+	)
+	create := func() *Game {
+		g := circularOrbitGame(testOrbitRadius)
+		craft := g.spaceObjects[1]
+		craft.fuelMass = 0.5
+		craft.exhaustVelocity = 1e5
+		return g
+	}
+	g, ballistic := create(), create()
+	g.nodes = []*ManeuverNode{{time: nodeStep * dt, prograde: prograde, radial: radial}}
+	predicted := g.predictTrajectory(3*nodeStep, g.plannedBurns())
+
+	// the node burns at the end of the step that starts at its time, until then both runs agree
+	for step := 0; step < nodeStep; step++ {
+		g.Step()
+		ballistic.Step()
+		if g.spaceObjects[1].velocity != ballistic.spaceObjects[1].velocity {
+			t.Fatalf("velocity differs after %d steps, before the node", step+1)
+		}
+	}
+	g.Step()
+	ballistic.Step()
+	if len(g.nodes) != 0 {
+		t.Fatal("the node was not executed")
+	}
+
+	// prograde is along the velocity relative to the star, radial is perpendicular to it and away from the star
+	craft, star := ballistic.spaceObjects[1], ballistic.spaceObjects[0]
+	dv := g.spaceObjects[1].velocity.Translate(-craft.velocity.X, -craft.velocity.Y)
+	along := craft.velocity.Translate(-star.velocity.X, -star.velocity.Y).Normalize()
+	away := perpendicularAway(along, craft.position.Translate(-star.position.X, -star.position.Y))
+	if got := dv.Dot(along); math.Abs(got-prograde) > 1e-6 {
+		t.Errorf("prograde part of the burn is %v m/s, want %v", got, prograde)
+	}
+	if got := dv.Dot(away); math.Abs(got-radial) > 1e-6 {
+		t.Errorf("radial part of the burn is %v m/s, want %v", got, radial)
+	}
+	if fuel := g.spaceObjects[1].fuelMass; fuel >= 0.5 {
+		t.Errorf("the burn used no fuel, %v kg left", fuel)
+	}
+
+	// the run follows the trajectory the prediction showed with the node
+	for step := nodeStep + 1; step < 3*nodeStep; step++ {
+		g.Step()
+		if d := math.Sqrt(g.spaceObjects[1].position.DistanceSquared(predicted[step][1])); d > 1e-6*testOrbitRadius {
+			t.Fatalf("after %d steps the craft is %v m off the predicted trajectory", step+1, d)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+)
+
+// outcome of a single monte carlo trial
+const (
+	outcomeOrbiting string = "orbiting" // the spacecraft is on a bound orbit at the end
+	outcomeEscaped  string = "escaped"  // the spacecraft is on an escape orbit at the end
+	outcomeCrashed  string = "crashed"  // the spacecraft merged with another body
+)
+
+// result of a single monte carlo trial
+type monteCarloTrial struct {
+	Trial         int            `json:"trial"`
+	Perturbation  Vector         `json:"perturbation"`
+	Outcome       string         `json:"outcome"`
+	Steps         int            `json:"steps"`
+	Time          float64        `json:"time"`
+	FinalPosition Vector         `json:"finalPosition"`
+	FinalVelocity Vector         `json:"finalVelocity"`
+	Bodies        []headlessBody `json:"bodies"`
+}
+
+// results of all trials and how often each outcome occurred
+type monteCarloResult struct {
+	Trials  []monteCarloTrial `json:"trials"`
+	Summary map[string]int    `json:"summary"`
+}
+
+// runs the scene the given number of times, each time with the initial spacecraft velocity perturbed
+// by a normally distributed offset with the standard deviation sigma in m/s per axis
+// a trial ends after the given number of steps or when the spacecraft crashes, the results are written as json to w
+// the perturbations come from rng, so the same seed gives the same trials
+func runMonteCarlo(create func() *Game, trials, steps int, sigma float64, rng *rand.Rand, w io.Writer) error {
+	if trials <= 0 {
+		return fmt.Errorf("number of trials must be positive, got %d", trials)
+	}
+	if steps < 0 {
+		return fmt.Errorf("number of steps must not be negative, got %d", steps)
+	}
+
+	result := monteCarloResult{Summary: map[string]int{}}
+	for trial := 0; trial < trials; trial++ {
+		game := create()
+		if err := game.checkBodyCount(); err != nil {
+			return err
+		}
+		craftIndex := game.spacecraftIndex()
+		if craftIndex < 0 {
+			return fmt.Errorf("scene has no spacecraft to perturb")
+		}
+
+		perturbation := Vector{rng.NormFloat64() * sigma, rng.NormFloat64() * sigma}
+		craft := game.spaceObjects[craftIndex]
+		craft.velocity = craft.velocity.Translate(perturbation.X, perturbation.Y)
+
+		outcome := ""
+		step := 0
+		for ; step < steps && !game.done; step++ {
+			game.Step()
+			if game.spacecraftIndex() < 0 {
+				outcome = outcomeCrashed
+				step++
+				break
+			}
+		}
+
+		record := monteCarloTrial{
+			Trial:        trial,
+			Perturbation: perturbation,
+			Steps:        step,
+			Time:         game.time,
+			Bodies:       make([]headlessBody, len(game.spaceObjects)),
+		}
+		for i, so := range game.spaceObjects {
+			record.Bodies[i] = headlessBody{Name: so.name, Position: so.position, Velocity: so.velocity}
+		}
+		if outcome == "" {
+			craft = game.spaceObjects[game.spacecraftIndex()]
+			record.FinalPosition = craft.position
+			record.FinalVelocity = craft.velocity
+			outcome = outcomeOrbiting
+			if class := game.OrbitClassification(); class == orbitEscape || class == orbitParabolic {
+				outcome = outcomeEscaped
+			}
+		}
+		record.Outcome = outcome
+
+		result.Trials = append(result.Trials, record)
+		result.Summary[outcome]++
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand/v2"
+	"testing"
+)
+
+// runs the monte carlo mode and returns its decoded result
+func monteCarlo(t *testing.T, create func() *Game, trials, steps int, sigma float64, seed uint64) (monteCarloResult, []byte) {
+	var buf bytes.Buffer
+	if err := runMonteCarlo(create, trials, steps, sigma, rand.New(rand.NewPCG(seed, seed)), &buf); err != nil {
+		t.Fatal(err)
+	}
+	var result monteCarloResult
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	return result, buf.Bytes()
+}
+
+func TestMonteCarloOutcomes(t *testing.T) {
+	tests := []struct {
+		name   string
+		create func() *Game
+		want   string
+	}{
+		{"orbiting", func() *Game { return circularOrbitGame(testOrbitRadius) }, outcomeOrbiting},
+		{"escaped", func() *Game {
+			g := circularOrbitGame(testOrbitRadius)
+			g.spaceObjects[1].velocity = Vector{0, 2 * g.config.EscapeVelocity(testStarMass, testOrbitRadius)}
+			return g
+		}, outcomeEscaped},
+		{"crashed", func() *Game {
+			g := circularOrbitGame(2 * testStarRadius)
+			g.spaceObjects[1].velocity = Vector{0, 0}
+			return g
+		}, outcomeCrashed},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// a perturbation of 1 m/s does not change the outcome of these clear cases
+			result, _ := monteCarlo(t, test.create, 3, 20, 1, 1)
+			if len(result.Trials) != 3 || result.Summary[test.want] != 3 {
+				t.Fatalf("summary is %v over %d trials, want 3 times %s", result.Summary, len(result.Trials), test.want)
+			}
+			for _, trial := range result.Trials {
+				if trial.Perturbation == (Vector{0, 0}) {
+					t.Errorf("trial %d was not perturbed", trial.Trial)
+				}
+				if test.want != outcomeCrashed && trial.Steps != 20 {
+					t.Errorf("trial %d ran %d steps, want 20", trial.Trial, trial.Steps)
+				}
+			}
+		})
+	}
+}
+
+func TestMonteCarloIsReproducible(t *testing.T) {
+	create := func() *Game { return circularOrbitGame(testOrbitRadius) }
+	_, first := monteCarlo(t, create, 4, 10, 100, 7)
+	_, second := monteCarlo(t, create, 4, 10, 100, 7)
+	if !bytes.Equal(first, second) {
+		t.Error("two runs with the same seed gave different results")
+	}
+	if _, other := monteCarlo(t, create, 4, 10, 100, 8); bytes.Equal(first, other) {
+		t.Error("a different seed gave the same results")
+	}
+
+	// without perturbation every trial is the unperturbed run
+	result, _ := monteCarlo(t, create, 2, 10, 0, 7)
+	if result.Trials[0].FinalPosition != result.Trials[1].FinalPosition {
+		t.Errorf("unperturbed trials ended at %v and %v", result.Trials[0].FinalPosition, result.Trials[1].FinalPosition)
+	}
+}
@@ -0,0 +1,345 @@
+package main
+
+import "math"
+
+const (
+	parabolicTolerance  float64 = 1e-3 // specific energies within this fraction of the potential count as parabolic
+	defaultEscapeMargin float64 = 1.05 // multiple of the escape velocity the escape command sets
+)
+
+// OrbitClass describes whether an orbit is closed or leaves the dominant body
+type OrbitClass int
+
+const (
+	orbitUnknown   OrbitClass = iota // there is no spacecraft or no body to orbit
+	orbitBound                       // negative specific energy, the orbit is an ellipse
+	orbitParabolic                   // specific energy of about zero, exactly escape velocity
+	orbitEscape                      // positive specific energy, the orbit is a hyperbola
+)
+
+func (c OrbitClass) String() string {
+	switch c {
+	case orbitBound:
+		return "bound"
+	case orbitParabolic:
+		return "parabolic"
+	case orbitEscape:
+		return "escape"
+	default:
+		return "unknown"
+	}
+}
+
+// returns the speed of a circular orbit at the given radius around a body of the given mass: v = sqrt(G*M/r)
+func (c SimConfig) CircularOrbitVelocity(centralMass, radius float64) float64 {
+	return math.Sqrt(c.gravitationalConstant() * centralMass / radius)
+}
+
+// returns the speed needed to escape a body of the given mass from the given distance: v = sqrt(2*G*M/r)
+func (c SimConfig) EscapeVelocity(centralMass, radius float64) float64 {
+	return math.Sqrt(2 * c.gravitationalConstant() * centralMass / radius)
+}
+
+// returns the period of an orbit with the given semi-major axis around a body of the given mass in s:
+// T = 2*pi*sqrt(a^3 / (G*M)), NaN for escape orbits (a <= 0)
+func (c SimConfig) OrbitalPeriod(centralMass, semiMajorAxis float64) float64 {
+	if semiMajorAxis <= 0 {
+		return math.NaN()
+	}
+	return 2 * math.Pi * math.Sqrt(semiMajorAxis*semiMajorAxis*semiMajorAxis/(c.gravitationalConstant()*centralMass))
+}
+
+// returns the planet exerting the strongest gravitational force on the given object, nil if there is none
+func (g *Game) DominantBody(so *SpaceObject) *SpaceObject {
+	var dominant *SpaceObject
+	strongest := 0.0
+	for _, other := range g.spaceObjects {
+		if other == so || other.isSpacecraft {
+			continue
+		}
+		// the mass of so is the same for every candidate, so only M / r^n has to be compared
+		distance := math.Sqrt(so.position.DistanceSquared(other.position))
+		if pull := other.mass / g.config.distancePower(distance); pull > strongest {
+			dominant = other
+			strongest = pull
+		}
+	}
+	return dominant
+}
+
+// returns the spacecraft and the body it orbits, nil if one of them does not exist
+func (g *Game) spacecraftAndDominantBody() (craft, body *SpaceObject) {
+	index := g.spacecraftIndex()
+	if index < 0 {
+		return nil, nil
+	}
+	craft = g.spaceObjects[index]
+	return craft, g.DominantBody(craft)
+}
+
+// returns the specific orbital energy v^2/2 - G*M/r of the spacecraft relative to the dominant body in J/kg
+// returns NaN if there is no spacecraft or no dominant body
+func (g *Game) SpecificOrbitalEnergy() float64 {
+	energy, _ := g.specificOrbitalEnergy()
+	return energy
+}
+
+// returns the specific orbital energy and the magnitude of its potential part G*M/r
+func (g *Game) specificOrbitalEnergy() (energy, potential float64) {
+	craft, body := g.spacecraftAndDominantBody()
+	if craft == nil || body == nil {
+		return math.NaN(), math.NaN()
+	}
+
+	// position and velocity relative to the dominant body
+	r := math.Sqrt(craft.position.DistanceSquared(body.position))
+	v := math.Sqrt(craft.velocity.DistanceSquared(body.velocity))
+
+	potential = g.config.gravitationalConstant() * body.mass / r
+	return v*v/2 - potential, potential
+}
+
+// classifies an orbit by its specific energy
+// energies within parabolicTolerance of the potential are treated as parabolic
+func classifyOrbit(energy, potential float64) OrbitClass {
+	switch {
+	case math.IsNaN(energy):
+		return orbitUnknown
+	case math.Abs(energy) <= parabolicTolerance*potential:
+		return orbitParabolic
+	case energy < 0:
+		return orbitBound
+	default:
+		return orbitEscape
+	}
+}
+
+// returns the class of the current spacecraft orbit around the dominant body
+func (g *Game) OrbitClassification() OrbitClass {
+	return classifyOrbit(g.specificOrbitalEnergy())
+}
+
+// OrbitalElements describe the conic section a body follows around a single attracting body
+type OrbitalElements struct {
+	semiMajorAxis       float64 // a in m, negative for hyperbolic orbits
+	eccentricity        float64 // e, 0 for circles, below 1 for ellipses, above 1 for hyperbolas
+	argumentOfPeriapsis float64 // angle between the x axis and the direction of the periapsis in rad
+	semiLatusRectum     float64 // p = h^2/mu in m, the radius at a true anomaly of 90 degrees
+	angularMomentum     float64 // specific angular momentum h = r x v in m^2/s, negative for clockwise orbits
+}
+
+// returns the eccentricity vector ((v^2 - mu/r)*r - (r.v)*v) / mu, pointing to the periapsis
+// r and v are relative to the attracting body and mu is its gravitational parameter G*M
+func eccentricityVector(r, v Vector, mu float64) Vector {
+	rLength := r.Length()
+	vSquared := v.Dot(v)
+	radial := (vSquared - mu/rLength) / mu
+	tangential := r.Dot(v) / mu
+	return Vector{radial*r.X - tangential*v.X, radial*r.Y - tangential*v.Y}
+}
+
+// returns the orbital elements of a body at the relative position r with the relative velocity v
+// around an attracting body with the gravitational parameter mu = G*M
+func computeOrbitalElements(r, v Vector, mu float64) OrbitalElements {
+	h := r.X*v.Y - r.Y*v.X
+	eVector := eccentricityVector(r, v, mu)
+
+	// the specific energy gives the semi-major axis: a = -mu / (2*energy)
+	energy := v.Dot(v)/2 - mu/r.Length()
+
+	return OrbitalElements{
+		semiMajorAxis:       -mu / (2 * energy),
+		eccentricity:        eVector.Length(),
+		argumentOfPeriapsis: math.Atan2(eVector.Y, eVector.X),
+		semiLatusRectum:     h * h / mu,
+		angularMomentum:     h,
+	}
+}
+
+// returns the orbital elements of the spacecraft around its dominant body
+// the spacecraft is treated as a test particle, so only the mass of the dominant body counts
+// returns false if there is no spacecraft or no dominant body
+func (g *Game) OrbitalElements() (OrbitalElements, *SpaceObject, bool) {
+	craft, body := g.spacecraftAndDominantBody()
+	if craft == nil || body == nil {
+		return OrbitalElements{}, nil, false
+	}
+	r := craft.position.Translate(-body.position.X, -body.position.Y)
+	v := craft.velocity.Translate(-body.velocity.X, -body.velocity.Y)
+	return computeOrbitalElements(r, v, g.config.gravitationalConstant()*body.mass), body, true
+}
+
+// splits the velocity v at the relative position r into the part along the radius (positive away from the body)
+// and the part perpendicular to it (positive counterclockwise)
+func radialTangential(r, v Vector) (radial, tangential float64) {
+	direction := r.Normalize()
+	return v.Dot(direction), direction.X*v.Y - direction.Y*v.X
+}
+
+// returns the flight path angle of the spacecraft in degrees: the angle between its velocity relative to the
+// dominant body and the local horizontal, positive while climbing, negative while descending and zero at the apsides
+// returns false if there is no spacecraft orbiting a body
+func (g *Game) FlightPathAngle() (float64, bool) {
+	craft, body := g.spacecraftAndDominantBody()
+	if craft == nil || body == nil {
+		return 0, false
+	}
+	r := craft.position.Translate(-body.position.X, -body.position.Y)
+	v := craft.velocity.Translate(-body.velocity.X, -body.velocity.Y)
+	radial, tangential := radialTangential(r, v)
+	// the horizontal is measured in the sense of the orbit, so clockwise orbits get the same sign
+	return math.Atan2(radial, math.Abs(tangential)) * 180 / math.Pi, true
+}
+
+// returns the orbital elements of the spacecraft for a recorded frame, nil if there is no spacecraft orbiting a body
+func (g *Game) recordedOrbit() *RecordedOrbit {
+	elements, body, ok := g.OrbitalElements()
+	if !ok {
+		return nil
+	}
+	return &RecordedOrbit{
+		Body:                body.name,
+		SemiMajorAxis:       elements.semiMajorAxis,
+		Eccentricity:        elements.eccentricity,
+		ArgumentOfPeriapsis: elements.argumentOfPeriapsis,
+	}
+}
+
+// returns the position on the orbit at the given true anomaly (angle from the periapsis) relative to the attracting body
+// uses the conic equation r = p / (1 + e*cos(nu)), which holds for ellipses and hyperbolas
+func (e OrbitalElements) positionAt(trueAnomaly float64) Vector {
+	r := e.semiLatusRectum / (1 + e.eccentricity*math.Cos(trueAnomaly))
+	angle := e.argumentOfPeriapsis + trueAnomaly
+	return Vector{r * math.Cos(angle), r * math.Sin(angle)}
+}
+
+// returns the points relative to the attracting body where the orbit crosses the line through the body at the given angle
+// in 2D these take the place of the ascending and descending node, the first point is where the orbit crosses at the axis angle,
+// the second where it crosses on the opposite side; a hyperbola may only cross on one side
+func (e OrbitalElements) nodeCrossings(axis float64) []Vector {
+	nodes := make([]Vector, 0, 2)
+	for _, angle := range []float64{axis, axis + math.Pi} {
+		nu := angle - e.argumentOfPeriapsis
+		// the conic only reaches the directions where 1 + e*cos(nu) is positive
+		if 1+e.eccentricity*math.Cos(nu) <= 0 {
+			continue
+		}
+		nodes = append(nodes, e.positionAt(nu))
+	}
+	return nodes
+}
+
+// returns the direction perpendicular to the radius of the spacecraft around the body in the sense of its current orbit
+// a spacecraft at rest relative to the body goes counterclockwise; also returns the distance to the body
+func perpendicularPrograde(craft, body *SpaceObject) (Vector, float64) {
+	r := craft.position.Translate(-body.position.X, -body.position.Y)
+	v := craft.velocity.Translate(-body.velocity.X, -body.velocity.Y)
+	radial := r.Normalize()
+
+	// perpendicular of the radial direction, rotated 90 degrees in the direction of the current orbit
+	prograde := Vector{-radial.Y, radial.X}
+	if r.X*v.Y-r.Y*v.X < 0 {
+		prograde = Vector{radial.Y, -radial.X}
+	}
+	return prograde, r.Length()
+}
+
+// sets the velocity of the spacecraft to a circular orbit around its dominant body at the current distance
+// the orbit keeps the current sense of rotation
+// returns false if there is no spacecraft or no dominant body
+func (g *Game) snapToCircularOrbit() bool {
+	craft, body := g.spacecraftAndDominantBody()
+	if craft == nil || body == nil {
+		return false
+	}
+
+	prograde, distance := perpendicularPrograde(craft, body)
+	speed := g.config.CircularOrbitVelocity(body.mass, distance)
+	craft.velocity = body.velocity.Translate(prograde.X*speed, prograde.Y*speed)
+	return true
+}
+
+// sets the velocity of the spacecraft to the escape velocity of its dominant body times the margin
+// the velocity points prograde (perpendicular to the radius) or, if radial is set, straight away from the body
+// with a margin above 1 the specific energy is positive and the orbit a hyperbola
+// returns false if there is no spacecraft or no dominant body
+func (g *Game) setEscapeTrajectory(margin float64, radial bool) bool {
+	craft, body := g.spacecraftAndDominantBody()
+	if craft == nil || body == nil {
+		return false
+	}
+
+	direction, distance := perpendicularPrograde(craft, body)
+	if radial {
+		direction = craft.position.Translate(-body.position.X, -body.position.Y).Normalize()
+	}
+	speed := margin * g.config.EscapeVelocity(body.mass, distance)
+	craft.velocity = body.velocity.Translate(direction.X*speed, direction.Y*speed)
+	return true
+}
+
+// returns the position and velocity relative to the attracting body of an object with the given orbital elements
+// at the given true anomaly (angles in rad); the orbit is counterclockwise unless clockwise is set
+// the inverse of computeOrbitalElements, mu is the gravitational parameter G*M of the attracting body
+func stateFromElements(semiMajorAxis, eccentricity, argumentOfPeriapsis, trueAnomaly, mu float64, clockwise bool) (r, v Vector) {
+	direction := 1.0
+	if clockwise {
+		direction = -1
+	}
+
+	p := semiMajorAxis * (1 - eccentricity*eccentricity)
+	distance := p / (1 + eccentricity*math.Cos(trueAnomaly))
+	angle := argumentOfPeriapsis + direction*trueAnomaly
+	radial := Vector{math.Cos(angle), math.Sin(angle)}
+	tangential := Vector{-radial.Y * direction, radial.X * direction}
+
+	// velocity components along and perpendicular to the radius: v_r = sqrt(mu/p)*e*sin(nu), v_t = sqrt(mu/p)*(1+e*cos(nu))
+	vRadial := math.Sqrt(mu/p) * eccentricity * math.Sin(trueAnomaly)
+	vTangential := math.Sqrt(mu/p) * (1 + eccentricity*math.Cos(trueAnomaly))
+
+	r = radial.Scale(distance, distance)
+	v = Vector{vRadial*radial.X + vTangential*tangential.X, vRadial*radial.Y + vTangential*tangential.Y}
+	return r, v
+}
+
+// remembers the orientation of the apsis line around each body the spacecraft orbited
+// when the spacecraft returns to a body after a flyby of another one, the difference shows how far the flyby rotated the orbit
+type apsisTracker struct {
+	body       *SpaceObject             // body the spacecraft orbited in the previous step
+	angles     map[*SpaceObject]float64 // last argument of periapsis around each body in rad
+	rotation   float64                  // change of the argument of periapsis across the last flyby in rad
+	rotated    bool                     // rotation is only valid after a flyby
+	rotatedFor *SpaceObject             // body whose orbit the rotation belongs to
+}
+
+// returns the angle difference a - b wrapped into [-pi, pi)
+func angleDifference(a, b float64) float64 {
+	d := math.Mod(a-b+math.Pi, 2*math.Pi)
+	if d < 0 {
+		d += 2 * math.Pi
+	}
+	return d - math.Pi
+}
+
+// updates the apsis line history with the current orbit of the spacecraft
+func (g *Game) trackApsisLine() {
+	elements, body, ok := g.OrbitalElements()
+	if !ok || elements.eccentricity < circularEpsilon {
+		return
+	}
+	t := &g.apsis
+	if t.angles == nil {
+		t.angles = map[*SpaceObject]float64{}
+	}
+
+	// coming back to a body after orbiting another one: compare with the orientation from before the flyby
+	if body != t.body {
+		if before, ok := t.angles[body]; ok {
+			t.rotation = angleDifference(elements.argumentOfPeriapsis, before)
+			t.rotated = true
+			t.rotatedFor = body
+		}
+	}
+	t.body = body
+	t.angles[body] = elements.argumentOfPeriapsis
+}
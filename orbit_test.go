@@ -0,0 +1,249 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOrbitClassification(t *testing.T) {
+	g := circularOrbitGame(testOrbitRadius)
+	mu := g.config.gravitationalConstant() * testStarMass
+
+	// a circular orbit has half the potential as its (negative) energy
+	energy := g.SpecificOrbitalEnergy()
+	if want := -mu / (2 * testOrbitRadius); math.Abs(energy-want) > 1e-9*math.Abs(want) {
+		t.Errorf("energy of the circular orbit is %v J/kg, want %v", energy, want)
+	}
+	if class := g.OrbitClassification(); class != orbitBound {
+		t.Errorf("circular orbit is %v, want bound", class)
+	}
+
+	craft := g.spaceObjects[1]
+	escape := g.config.EscapeVelocity(testStarMass, testOrbitRadius)
+	craft.velocity = Vector{0, 1.5 * escape}
+	if class := g.OrbitClassification(); class != orbitEscape {
+		t.Errorf("orbit at 1.5 times the escape velocity is %v, want escape", class)
+	}
+
+	craft.velocity = Vector{0, escape}
+	if class := g.OrbitClassification(); class != orbitParabolic {
+		t.Errorf("orbit at the escape velocity is %v, want parabolic", class)
+	}
+
+	g.spaceObjects = g.spaceObjects[:1]
+	if class := g.OrbitClassification(); class != orbitUnknown {
+		t.Errorf("orbit without a spacecraft is %v, want unknown", class)
+	}
+}
+
+func TestNodeCrossings(t *testing.T) {
+	// ellipse with a = 1 and e = 0.5: periapsis at 0.5, apoapsis at 1.5, semi-latus rectum 0.75
+	ellipse := OrbitalElements{semiMajorAxis: 1, eccentricity: 0.5, semiLatusRectum: 0.75}
+	rotated := ellipse
+	rotated.argumentOfPeriapsis = math.Pi / 2
+	hyperbola := OrbitalElements{semiMajorAxis: -1, eccentricity: 2, semiLatusRectum: 3}
+
+	tests := []struct {
+		name     string
+		elements OrbitalElements
+		axis     float64
+		want     []Vector
+	}{
+		{"along the apsides", ellipse, 0, []Vector{{0.5, 0}, {-1.5, 0}}},
+		{"across the apsides", ellipse, math.Pi / 2, []Vector{{0, 0.75}, {0, -0.75}}},
+		{"rotated ellipse", rotated, 0, []Vector{{0.75, 0}, {-0.75, 0}}},
+		{"hyperbola crosses once", hyperbola, 0, []Vector{{1, 0}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.elements.nodeCrossings(test.axis)
+			if len(got) != len(test.want) {
+				t.Fatalf("got %d crossings %v, want %v", len(got), got, test.want)
+			}
+			for i := range got {
+				if math.Sqrt(got[i].DistanceSquared(test.want[i])) > 1e-12 {
+					t.Errorf("crossing %d is %v, want %v", i, got[i], test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSnapToCircularOrbit(t *testing.T) {
+	for _, clockwise := range []bool{false, true} {
+		g := ellipticOrbitGame(testOrbitRadius, 0.5, 1, 2)
+		star, craft := g.spaceObjects[0], g.spaceObjects[1]
+		if clockwise {
+			craft.velocity = craft.velocity.Scale(-1, -1)
+		}
+		// the orbit is relative to the star, even when the star moves
+		star.velocity = Vector{5e3, -2e3}
+		craft.velocity = craft.velocity.Translate(star.velocity.X, star.velocity.Y)
+		before, _, _ := g.OrbitalElements()
+
+		if !g.snapToCircularOrbit() {
+			t.Fatal("snapping failed")
+		}
+		after, _, ok := g.OrbitalElements()
+		if !ok {
+			t.Fatal("no orbit after snapping")
+		}
+		if after.eccentricity > 1e-9 {
+			t.Errorf("clockwise %v: eccentricity after snapping is %v, want 0", clockwise, after.eccentricity)
+		}
+		if math.Signbit(after.angularMomentum) != math.Signbit(before.angularMomentum) {
+			t.Errorf("clockwise %v: snapping reversed the sense of the orbit", clockwise)
+		}
+	}
+
+	g := circularOrbitGame(testOrbitRadius)
+	g.spaceObjects = g.spaceObjects[:1]
+	if g.snapToCircularOrbit() {
+		t.Error("snapping succeeded without a spacecraft")
+	}
+}
+
+func TestArgumentOfPeriapsis(t *testing.T) {
+	const (
+		a = testOrbitRadius
+		e = 0.5
+	)
+	mu := DefaultSimConfig().gravitationalConstant() * testStarMass
+	periapsis, apoapsis := a*(1-e), a*(1+e)
+	// speeds at the apsides from the vis-viva equation
+	periapsisSpeed := math.Sqrt(mu * (2/periapsis - 1/a))
+	apoapsisSpeed := math.Sqrt(mu * (2/apoapsis - 1/a))
+
+	// the craft is placed by hand at an apsis of an ellipse with a known orientation, moving counterclockwise
+	tests := []struct {
+		name     string
+		position Vector
+		velocity Vector
+		want     float64 // argument of periapsis in rad
+	}{
+		{"periapsis on the x axis", Vector{periapsis, 0}, Vector{0, periapsisSpeed}, 0},
+		{"periapsis on the y axis", Vector{0, periapsis}, Vector{-periapsisSpeed, 0}, math.Pi / 2},
+		{"periapsis on the negative y axis", Vector{0, -periapsis}, Vector{periapsisSpeed, 0}, -math.Pi / 2},
+		{"apoapsis on the x axis", Vector{apoapsis, 0}, Vector{0, apoapsisSpeed}, math.Pi},
+		{"periapsis at 45 degrees", Vector{periapsis / math.Sqrt2, periapsis / math.Sqrt2},
+			Vector{-periapsisSpeed / math.Sqrt2, periapsisSpeed / math.Sqrt2}, math.Pi / 4},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := circularOrbitGame(a)
+			g.spaceObjects[1].position = test.position
+			g.spaceObjects[1].velocity = test.velocity
+			elements, _, ok := g.OrbitalElements()
+			if !ok {
+				t.Fatal("no orbit")
+			}
+			if math.Abs(elements.eccentricity-e) > 1e-9 {
+				t.Errorf("eccentricity is %v, want %v", elements.eccentricity, e)
+			}
+			if d := angleDifference(elements.argumentOfPeriapsis, test.want); math.Abs(d) > 1e-9 {
+				t.Errorf("argument of periapsis is %v rad, want %v", elements.argumentOfPeriapsis, test.want)
+			}
+		})
+	}
+}
+
+func TestAngleDifference(t *testing.T) {
+	tests := []struct {
+		a, b, want float64
+	}{
+		{1, 0.5, 0.5},
+		{0.5, 1, -0.5},
+		// across the wrap around the shorter way is taken
+		{-3, 3, 2*math.Pi - 6},
+		{3, -3, 6 - 2*math.Pi},
+		{5 * math.Pi / 2, 0, math.Pi / 2},
+	}
+	for _, test := range tests {
+		if got := angleDifference(test.a, test.b); math.Abs(got-test.want) > 1e-12 {
+			t.Errorf("angleDifference(%v, %v) = %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestSetEscapeTrajectory(t *testing.T) {
+	tests := []struct {
+		name      string
+		radial    bool
+		direction Vector // the craft starts on the positive x axis going counterclockwise
+	}{
+		{"prograde", false, Vector{0, 1}},
+		{"radial", true, Vector{1, 0}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := circularOrbitGame(testOrbitRadius)
+			if !g.setEscapeTrajectory(defaultEscapeMargin, test.radial) {
+				t.Fatal("setting the escape trajectory failed")
+			}
+			craft := g.spaceObjects[1]
+			escape := g.config.EscapeVelocity(testStarMass, testOrbitRadius)
+			if want := test.direction.Scale(defaultEscapeMargin*escape, defaultEscapeMargin*escape); math.Sqrt(craft.velocity.DistanceSquared(want)) > 1e-9*escape {
+				t.Errorf("velocity is %v m/s, want %v", craft.velocity, want)
+			}
+			if energy := g.SpecificOrbitalEnergy(); energy <= 0 {
+				t.Errorf("specific orbital energy is %v J/kg, want positive", energy)
+			}
+			if class := g.OrbitClassification(); class != orbitEscape {
+				t.Errorf("orbit is %v, want escape", class)
+			}
+
+			// the craft keeps moving away from the star
+			distance := testOrbitRadius
+			for range 100 {
+				g.Step()
+				next := craft.position.Length()
+				if next <= distance {
+					t.Fatalf("craft came back to %v m from %v m", next, distance)
+				}
+				distance = next
+			}
+		})
+	}
+
+	g := circularOrbitGame(testOrbitRadius)
+	g.spaceObjects = g.spaceObjects[:1]
+	if g.setEscapeTrajectory(defaultEscapeMargin, false) {
+		t.Error("setting the escape trajectory succeeded without a spacecraft")
+	}
+}
+
+func TestFlightPathAngle(t *testing.T) {
+	const e = 0.5
+	// on a conic the flight path angle is atan(e sin(nu) / (1 + e cos(nu)))
+	known := func(nu float64) float64 {
+		return math.Atan2(e*math.Sin(nu), 1+e*math.Cos(nu)) * 180 / math.Pi
+	}
+	tests := []struct {
+		name      string
+		nu        float64
+		clockwise bool
+		want      float64
+	}{
+		{"periapsis", 0, false, 0},
+		{"apoapsis", math.Pi, false, 0},
+		{"climbing", 1, false, known(1)},
+		{"descending", -2, false, known(-2)},
+		{"periapsis clockwise", 0, true, 0},
+		{"climbing clockwise", 1, true, known(1)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := circularOrbitGame(testOrbitRadius)
+			mu := g.config.gravitationalConstant() * testStarMass
+			craft := g.spaceObjects[1]
+			craft.position, craft.velocity = stateFromElements(testOrbitRadius, e, 0.7, test.nu, mu, test.clockwise)
+			angle, ok := g.FlightPathAngle()
+			if !ok {
+				t.Fatal("no flight path angle")
+			}
+			if math.Abs(angle-test.want) > 1e-9 {
+				t.Errorf("flight path angle is %v°, want %v°", angle, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	defaultPredictionSteps int = 120 // number of time steps the trajectory prediction looks ahead
+)
+
+var predictionColor = color.RGBA{255, 255, 0, 160}
+
+// a burn of the spacecraft the prediction applies once the simulated time reaches it
+// the change of velocity is the fixed vector dv plus prograde m/s along the velocity relative to body
+// and radial m/s perpendicular to it, away from the body
+type scheduledBurn struct {
+	time     float64 // simulated time of the burn in s
+	dv       Vector  // velocity change in m/s
+	prograde float64 // velocity change along the velocity relative to body in m/s, negative is retrograde
+	radial   float64 // velocity change perpendicular to prograde in m/s, negative is towards the body
+	body     string  // name of the body prograde and radial refer to
+}
+
+// returns the burns the planned maneuvers will do: the second burn of a running transfer and a solved intercept
+func (g *Game) plannedBurns() []scheduledBurn {
+	var burns []scheduledBurn
+	if g.transfer.active {
+		burns = append(burns, scheduledBurn{time: g.transfer.burnTime, prograde: g.transfer.dv2, body: g.transfer.body.name})
+	}
+	if g.interceptPending() {
+		// the intercept burns as soon as it is confirmed, which has to be before the time moves on
+		burns = append(burns, scheduledBurn{time: g.time, dv: g.intercept.dv})
+	}
+	return append(burns, g.nodeBurns()...)
+}
+
+// changes the velocity of the spacecraft among the given spaceobjects by the burn
+func applyScheduledBurn(spaceObjects []*SpaceObject, burn scheduledBurn) {
+	var craft *SpaceObject
+	for _, so := range spaceObjects {
+		if so.isSpacecraft {
+			craft = so
+			break
+		}
+	}
+	if craft == nil {
+		return
+	}
+	dv := burn.dv
+	if body := objectNamed(spaceObjects, burn.body); body != nil {
+		prograde := craft.velocity.Translate(-body.velocity.X, -body.velocity.Y).Normalize()
+		radial := perpendicularAway(prograde, craft.position.Translate(-body.position.X, -body.position.Y))
+		dv = dv.Translate(prograde.X*burn.prograde+radial.X*burn.radial, prograde.Y*burn.prograde+radial.Y*burn.radial)
+	}
+	craft.velocity = craft.velocity.Translate(dv.X, dv.Y)
+}
+
+// returns the predicted positions of every spaceobject for the next steps
+// the simulation itself is not changed, the prediction runs on copies of the spaceobjects
+// the burns are applied after the step they fall into, the way the autopilot does them
+// trajectory[k][i] is the position of spaceobject i after k+1 steps
+func (g *Game) predictTrajectory(steps int, burns []scheduledBurn) [][]Vector {
+	spaceObjects := make([]*SpaceObject, len(g.spaceObjects))
+	for i, so := range g.spaceObjects {
+		copied := *so
+		spaceObjects[i] = &copied
+	}
+
+	done := make([]bool, len(burns))
+	trajectory := make([][]Vector, steps)
+	for k := range trajectory {
+		time := g.time + float64(k)*dt
+		stepSpaceObjects(spaceObjects, g.springs, g.config, time, nil)
+		for b, burn := range burns {
+			if !done[b] && time >= burn.time {
+				applyScheduledBurn(spaceObjects, burn)
+				done[b] = true
+			}
+		}
+
+		trajectory[k] = make([]Vector, len(spaceObjects))
+		for i, so := range spaceObjects {
+			trajectory[k][i] = so.position
+		}
+	}
+	return trajectory
+}
+
+// returns the minimum distance between the spaceobjects at index a and b over the next steps
+// and the simulated time at which it occurs
+func (g *Game) ClosestApproach(a, b int, steps int) (distance, time float64) {
+	minSquared := g.spaceObjects[a].position.DistanceSquared(g.spaceObjects[b].position)
+	time = g.time
+
+	for k, positions := range g.predictTrajectory(steps, g.plannedBurns()) {
+		if d := positions[a].DistanceSquared(positions[b]); d < minSquared {
+			minSquared = d
+			time = g.time + float64(k+1)*dt
+		}
+	}
+	return math.Sqrt(minSquared), time
+}
+
+// draws the predicted trajectory of the spacecraft as connected line segments
+// more prediction steps make the curve smoother and longer but cost more time every frame
+func (g *Game) drawPrediction(screen *ebiten.Image) {
+	craft := g.spacecraftIndex()
+	if craft < 0 {
+		return
+	}
+
+	// in a focus frame the prediction has to follow where the focused object will be, not where it is now
+	focus := g.focus
+	if focus >= len(g.spaceObjects) {
+		focus = -1
+	}
+	focusNow := g.focusPosition()
+
+	from := g.spaceObjects[craft].scaledPosition
+	for _, positions := range g.predictTrajectory(g.predictionSteps, g.plannedBurns()) {
+		p := positions[craft]
+		if focus >= 0 {
+			p = p.Translate(focusNow.X-positions[focus].X, focusNow.Y-positions[focus].Y)
+		}
+		to := g.worldToScreen(p)
+		vector.StrokeLine(screen, float32(from.X), float32(from.Y), float32(to.X), float32(to.Y), 1, predictionColor, true)
+		from = to
+	}
+}
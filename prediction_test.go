@@ -0,0 +1,89 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// returns a game with two bodies too light to attract each other noticeably, one passing the other in a straight line
+// the passing body starts at (-speed*dt*steps, impact) and is closest after exactly steps steps
+func flybyGame(speed, impact float64, steps int) *Game {
+	g := newGame()
+	g.spaceObjects = []*SpaceObject{
+		{name: "passing", mass: 1, position: Vector{-speed * dt * float64(steps), impact}, velocity: Vector{speed, 0}},
+		{name: "resting", mass: 1},
+	}
+	return g
+}
+
+func TestClosestApproach(t *testing.T) {
+	tests := []struct {
+		name   string
+		speed  float64
+		impact float64
+		steps  int
+	}{
+		{"slow", 1e3, 1e8, 20},
+		{"fast", 5e4, 3e9, 50},
+		{"head on", 1e4, 0, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := flybyGame(tt.speed, tt.impact, tt.steps)
+			distance, time := g.ClosestApproach(0, 1, 2*tt.steps)
+
+			// straight lines pass closest at the impact parameter
+			if math.Abs(distance-tt.impact) > 1e-6*tt.speed*dt {
+				t.Errorf("closest approach %g m, want %g m", distance, tt.impact)
+			}
+			if want := float64(tt.steps) * dt; time != want {
+				t.Errorf("closest approach at %g s, want %g s", time, want)
+			}
+		})
+	}
+}
+
+func TestPredictionWithAScheduledBurn(t *testing.T) {
+	const (
+		steps     = 40
+		burnStep  = 10
+		burnSpeed = 5e3 // m/s
+	)
+	tests := []struct {
+		name string
+		burn scheduledBurn
+	}{
+		{"prograde", scheduledBurn{prograde: burnSpeed, body: "star"}},
+		{"fixed", scheduledBurn{dv: Vector{-burnSpeed, 0}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := circularOrbitGame(testOrbitRadius)
+			g.time = 3 * dt
+			test.burn.time = g.time + burnStep*dt
+			craft := *g.spaceObjects[1]
+			ballistic := g.predictTrajectory(steps, nil)
+			burned := g.predictTrajectory(steps, []scheduledBurn{test.burn})
+
+			// up to the step the burn falls into both agree, the burn only changes the velocity
+			for k := 0; k <= burnStep; k++ {
+				if burned[k][1] != ballistic[k][1] {
+					t.Fatalf("predictions differ after %d steps, before the burn", k+1)
+				}
+			}
+			// after it they separate by about the change of velocity times the time since the burn
+			for k := burnStep + 1; k < steps; k++ {
+				separation := math.Sqrt(burned[k][1].DistanceSquared(ballistic[k][1]))
+				want := burnSpeed * float64(k-burnStep) * dt
+				if math.Abs(separation/want-1) > 0.1 {
+					t.Fatalf("predictions are %v m apart %d steps after the burn, want about %v", separation, k-burnStep, want)
+				}
+			}
+
+			// the prediction does not burn the real spacecraft
+			if so := g.spaceObjects[1]; so.position != craft.position || so.velocity != craft.velocity {
+				t.Errorf("the prediction moved the craft to %v at %v m/s", so.position, so.velocity)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+const keplerIterations int = 30 // newton steps solving kepler's equation
+
+// IntegrationMode determines how a spaceobject moves, every mode still pulls on the others
+type IntegrationMode int
+
+const (
+	modeNBody IntegrationMode = iota // integrated with the forces of all other spaceobjects
+	modeRails                        // follows the kepler orbit around its parent it started on, unaffected by anything else
+	modeFixed                        // does not move at all
+)
+
+func (m IntegrationMode) String() string {
+	switch m {
+	case modeRails:
+		return "rails"
+	case modeFixed:
+		return "fixed"
+	default:
+		return "nbody"
+	}
+}
+
+// returns the integration mode with the given name, the empty name is nbody
+func parseIntegrationMode(name string) (IntegrationMode, error) {
+	for _, mode := range []IntegrationMode{modeNBody, modeRails, modeFixed} {
+		if name == mode.String() {
+			return mode, nil
+		}
+	}
+	if name == "" {
+		return modeNBody, nil
+	}
+	return modeNBody, fmt.Errorf("unknown integration mode %q, use nbody, rails or fixed", name)
+}
+
+// the kepler orbit a spaceobject on rails follows around its parent
+// the orbit is taken from the state relative to the parent the first time the body is placed, so a scene or
+// a loaded state starts on rails from wherever it is; an unbound state cannot be followed and is integrated instead
+type railsOrbit struct {
+	parent      string          // name of the body the orbit goes around
+	set         bool            // the orbit below has been computed
+	bound       bool            // the orbit is an ellipse the body can follow
+	elements    OrbitalElements // orbit relative to the parent
+	mu          float64         // gravitational parameter of the parent in m^3/s^2
+	meanAnomaly float64         // mean anomaly at the epoch in rad
+	epoch       float64         // simulated time the orbit was computed at in s
+}
+
+// returns the spaceobject with the given name, nil if there is none
+func objectNamed(spaceObjects []*SpaceObject, name string) *SpaceObject {
+	for _, so := range spaceObjects {
+		if so.name == name {
+			return so
+		}
+	}
+	return nil
+}
+
+// returns the true anomaly at the given mean anomaly of an ellipse with the eccentricity e
+// solves kepler's equation M = E - e*sin(E) for the eccentric anomaly E with newton's method
+func trueAnomalyAt(meanAnomaly, e float64) float64 {
+	E := meanAnomaly
+	if e > 0.8 {
+		E = math.Pi
+	}
+	for k := 0; k < keplerIterations; k++ {
+		E -= (E - e*math.Sin(E) - meanAnomaly) / (1 - e*math.Cos(E))
+	}
+	return 2 * math.Atan2(math.Sqrt(1+e)*math.Sin(E/2), math.Sqrt(1-e)*math.Cos(E/2))
+}
+
+// moves the spaceobject on rails to where its orbit is at the given time, relative to the current state of its
+// parent; returns false if it has no parent or no bound orbit, then it has to be integrated like any other body
+func (so *SpaceObject) placeOnRails(spaceObjects []*SpaceObject, config SimConfig, time float64) bool {
+	rails := so.rails
+	if rails == nil {
+		return false
+	}
+	parent := objectNamed(spaceObjects, rails.parent)
+	if parent == nil || parent == so {
+		return false
+	}
+
+	if !rails.set {
+		r := so.position.Translate(-parent.position.X, -parent.position.Y)
+		v := so.velocity.Translate(-parent.velocity.X, -parent.velocity.Y)
+		rails.set = true
+		rails.mu = config.gravitationalConstant() * parent.mass
+		rails.elements = computeOrbitalElements(r, v, rails.mu)
+		rails.bound = rails.elements.eccentricity < 1 && rails.elements.semiMajorAxis > 0
+		rails.epoch = time
+
+		// the true anomaly runs in the direction of the orbit, like in stateFromElements
+		e := rails.elements.eccentricity
+		direction := 1.0
+		if rails.elements.angularMomentum < 0 {
+			direction = -1
+		}
+		nu := direction * (r.Angle() - rails.elements.argumentOfPeriapsis)
+		E := 2 * math.Atan(math.Sqrt((1-e)/(1+e))*math.Tan(nu/2))
+		rails.meanAnomaly = E - e*math.Sin(E)
+	}
+	if !rails.bound {
+		return false
+	}
+
+	a := rails.elements.semiMajorAxis
+	meanMotion := math.Sqrt(rails.mu / (a * a * a))
+	nu := trueAnomalyAt(rails.meanAnomaly+meanMotion*(time-rails.epoch), rails.elements.eccentricity)
+	r, v := stateFromElements(a, rails.elements.eccentricity, rails.elements.argumentOfPeriapsis, nu, rails.mu, rails.elements.angularMomentum < 0)
+	so.position = parent.position.Translate(r.X, r.Y)
+	so.velocity = parent.velocity.Translate(v.X, v.Y)
+	return true
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRailsPlanetIgnoresTheNBodyPlanet(t *testing.T) {
+	const (
+		e      = 0.3
+		argP   = 0.5
+		steps  = 400
+		heavy  = 1e28 // both planets are heavy enough to pull each other off a kepler orbit
+		radius = 1e6
+	)
+	g := newGame()
+	star := &SpaceObject{name: "star", mass: testStarMass, radius: testStarRadius, mode: modeFixed}
+	mu := g.config.gravitationalConstant() * testStarMass
+	r, v := stateFromElements(testOrbitRadius, e, argP, 0, mu, false)
+	onRails := &SpaceObject{name: "rails", mass: heavy, radius: radius, position: r, velocity: v,
+		mode: modeRails, rails: &railsOrbit{parent: "star"}}
+	r, v = stateFromElements(1.3*testOrbitRadius, 0, 0, 0.5, mu, false)
+	integrated := &SpaceObject{name: "nbody", mass: heavy, radius: radius, position: r, velocity: v}
+	g.spaceObjects = []*SpaceObject{star, onRails, integrated}
+
+	elementsAround := func(so *SpaceObject) OrbitalElements {
+		return computeOrbitalElements(so.position.Translate(-star.position.X, -star.position.Y),
+			so.velocity.Translate(-star.velocity.X, -star.velocity.Y), mu)
+	}
+	integratedBefore := elementsAround(integrated)
+
+	meanMotion := math.Sqrt(mu / math.Pow(testOrbitRadius, 3))
+	for step := 1; step <= steps; step++ {
+		g.Step()
+
+		// the rails planet is where kepler's equation puts it, starting at the periapsis
+		nu := trueAnomalyAt(meanMotion*float64(step)*dt, e)
+		want, _ := stateFromElements(testOrbitRadius, e, argP, nu, mu, false)
+		if d := math.Sqrt(onRails.position.DistanceSquared(want)); d > 1e-6*testOrbitRadius {
+			t.Fatalf("step %d: rails planet is %v m off its kepler orbit", step, d)
+		}
+	}
+
+	if elements := elementsAround(onRails); math.Abs(elements.semiMajorAxis/testOrbitRadius-1) > 1e-9 || math.Abs(elements.eccentricity-e) > 1e-9 {
+		t.Errorf("rails planet has a = %v m and e = %v, want %v and %v", elements.semiMajorAxis, elements.eccentricity, testOrbitRadius, e)
+	}
+	// the integrated planet feels the rails planet, so its orbit changes
+	if elements := elementsAround(integrated); math.Abs(elements.eccentricity-integratedBefore.eccentricity) < 1e-3 {
+		t.Errorf("eccentricity of the nbody planet stayed at %v, the rails planet does not pull on it", elements.eccentricity)
+	}
+	if star.position != (Vector{}) {
+		t.Errorf("fixed star moved to %v", star.position)
+	}
+}
+
+func TestParseIntegrationMode(t *testing.T) {
+	for _, mode := range []IntegrationMode{modeNBody, modeRails, modeFixed} {
+		if parsed, err := parseIntegrationMode(mode.String()); err != nil || parsed != mode {
+			t.Errorf("parseIntegrationMode(%q) = %v, %v", mode.String(), parsed, err)
+		}
+	}
+	if mode, err := parseIntegrationMode(""); err != nil || mode != modeNBody {
+		t.Errorf("empty mode is %v, %v, want nbody", mode, err)
+	}
+	if _, err := parseIntegrationMode("orbiting"); err == nil {
+		t.Error("unknown mode was accepted")
+	}
+}
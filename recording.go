@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"os"
+	"strconv"
+)
+
+const (
+	recordingJSONPath string = "recording.json" // file the recording is exported to as json
+	recordingCSVPath  string = "recording.csv"  // file the recording is exported to as csv
+)
+
+// state of a single spaceobject at the time of a recorded frame
+type RecordedBody struct {
+	Name     string `json:"name"`
+	Position Vector `json:"position"`
+	Velocity Vector `json:"velocity"`
+}
+
+// osculating orbital elements of the spacecraft at the time of a recorded frame
+// the elements are relative to the dominant body, so they jump when it changes; the body tells them apart
+type RecordedOrbit struct {
+	Body                string  `json:"body"`
+	SemiMajorAxis       float64 `json:"semiMajorAxis"` // m, negative for hyperbolic orbits
+	Eccentricity        float64 `json:"eccentricity"`
+	ArgumentOfPeriapsis float64 `json:"argumentOfPeriapsis"` // rad
+}
+
+// state of all spaceobjects at a point in time
+type RecordedFrame struct {
+	Time   float64        `json:"time"`
+	Bodies []RecordedBody `json:"bodies"`
+	Orbit  *RecordedOrbit `json:"orbit,omitempty"` // nil if there was no spacecraft orbiting a body
+}
+
+// a named point in time of a recording, e.g. "burn start"
+type Marker struct {
+	Time  float64 `json:"time"`
+	Label string  `json:"label"`
+}
+
+type Recording struct {
+	Frames  []RecordedFrame `json:"frames"`
+	Markers []Marker        `json:"markers"`
+	active  bool            // frames are only recorded while the recording is active
+}
+
+// starts a new recording, the frames and markers of the previous one are dropped
+func (r *Recording) Start() {
+	r.Frames = nil
+	r.Markers = nil
+	r.active = true
+}
+
+// stops recording, the recorded frames are kept for the export
+func (r *Recording) Stop() {
+	r.active = false
+}
+
+// records the current state of all spaceobjects and the orbit of the spacecraft
+func (r *Recording) RecordFrame(time float64, spaceObjects []*SpaceObject, orbit *RecordedOrbit) {
+	if !r.active {
+		return
+	}
+
+	frame := RecordedFrame{Time: time, Bodies: make([]RecordedBody, len(spaceObjects)), Orbit: orbit}
+	for i, so := range spaceObjects {
+		frame.Bodies[i] = RecordedBody{Name: so.name, Position: so.position, Velocity: so.velocity}
+	}
+	r.Frames = append(r.Frames, frame)
+}
+
+// inserts a marker with the given label at the given time
+func (r *Recording) AddMarker(time float64, label string) {
+	r.Markers = append(r.Markers, Marker{Time: time, Label: label})
+}
+
+// returns the labels of all markers set after the first and up to the second time joined by ";"
+// markers fall between recorded frames whenever not every step is recorded, so they belong to the next frame
+func (r *Recording) markerLabels(after, until float64) string {
+	labels := ""
+	for _, m := range r.Markers {
+		if m.Time <= after || m.Time > until {
+			continue
+		}
+		if labels != "" {
+			labels += ";"
+		}
+		labels += m.Label
+	}
+	return labels
+}
+
+// writes the recording as json to the given path
+func (r *Recording) ExportJSON(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// writes the recording as csv to the given path, one row per frame and spaceobject
+// markers are written into a separate column of the rows of the first frame at or after the marker time,
+// markers after the last frame get rows of their own without a body; the orbit of the spacecraft into the last columns of every row of its frame, empty without one
+func (r *Recording) ExportCSV(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	if err := w.Write([]string{"time", "name", "x", "y", "vx", "vy", "marker", "orbitBody", "a", "e", "argumentOfPeriapsis"}); err != nil {
+		return err
+	}
+
+	format := func(f float64) string {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+
+	previous := math.Inf(-1)
+	for _, frame := range r.Frames {
+		marker := r.markerLabels(previous, frame.Time)
+		previous = frame.Time
+		orbit := []string{"", "", "", ""}
+		if o := frame.Orbit; o != nil {
+			orbit = []string{o.Body, format(o.SemiMajorAxis), format(o.Eccentricity), format(o.ArgumentOfPeriapsis)}
+		}
+		for _, body := range frame.Bodies {
+			row := []string{
+				format(frame.Time),
+				body.Name,
+				format(body.Position.X),
+				format(body.Position.Y),
+				format(body.Velocity.X),
+				format(body.Velocity.Y),
+				marker,
+			}
+			row = append(row, orbit...)
+			if err := w.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	for _, m := range r.Markers {
+		if m.Time > previous {
+			if err := w.Write([]string{format(m.Time), "", "", "", "", "", m.Label, "", "", "", ""}); err != nil {
+				return err
+			}
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
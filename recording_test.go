@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/csv"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// returns a recording of the given frame times with a single body and the given markers
+func testRecording(times []float64, markers ...Marker) *Recording {
+	r := &Recording{}
+	r.Start()
+	for _, time := range times {
+		r.RecordFrame(time, []*SpaceObject{{name: "craft", position: Vector{time, 0}}}, nil)
+	}
+	for _, m := range markers {
+		r.AddMarker(m.Time, m.Label)
+	}
+	return r
+}
+
+func TestExportJSONKeepsMarkers(t *testing.T) {
+	markers := []Marker{{Time: 2 * dt, Label: "burn start"}, {Time: 3.5 * dt, Label: "burn end"}}
+	path := filepath.Join(t.TempDir(), "recording.json")
+	if err := testRecording([]float64{0, dt, 2 * dt, 3 * dt, 4 * dt}, markers...).ExportJSON(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadRecording(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Markers) != len(markers) {
+		t.Fatalf("%d markers, want %d", len(loaded.Markers), len(markers))
+	}
+	for i, m := range markers {
+		if loaded.Markers[i] != m {
+			t.Errorf("marker %d is %+v, want %+v", i, loaded.Markers[i], m)
+		}
+	}
+}
+
+func TestExportCSVAttachesMarkersToTheNextFrame(t *testing.T) {
+	tests := []struct {
+		name    string
+		markers []Marker
+		want    map[string]string // marker column by time column
+	}{
+		{"on a frame", []Marker{{Time: dt, Label: "a"}}, map[string]string{"43200": "a"}},
+		{"between frames", []Marker{{Time: 1.5 * dt, Label: "a"}}, map[string]string{"86400": "a"}},
+		{"two before one frame", []Marker{{Time: 1.2 * dt, Label: "a"}, {Time: 1.8 * dt, Label: "b"}}, map[string]string{"86400": "a;b"}},
+		{"before the first frame", []Marker{{Time: -dt, Label: "a"}}, map[string]string{"0": "a"}},
+		{"after the last frame", []Marker{{Time: 5 * dt, Label: "a"}}, map[string]string{"216000": "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "recording.csv")
+			if err := testRecording([]float64{0, dt, 2 * dt}, tt.markers...).ExportCSV(path); err != nil {
+				t.Fatal(err)
+			}
+			file, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			rows, err := csv.NewReader(file).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := map[string]string{}
+			for _, row := range rows[1:] {
+				if row[6] != "" {
+					got[row[0]] = row[6]
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("markers %v, want %v", got, tt.want)
+			}
+			for time, label := range tt.want {
+				if got[time] != label {
+					t.Errorf("marker at %s is %q, want %q", time, got[time], label)
+				}
+			}
+		})
+	}
+}
+
+func TestRecordedOrbitStaysConstantOnATwoBodyOrbit(t *testing.T) {
+	const (
+		e             = 0.3
+		argPeriapsis  = 1.0
+		tolerance     = 1e-6 // relative drift of the integrator over the run
+		recordedSteps = 500  // about two thirds of an orbit
+	)
+	g := ellipticOrbitGame(testOrbitRadius, e, argPeriapsis, 2)
+	g.config.integrator = integratorRK4
+	g.recording.Start()
+	for range recordedSteps {
+		g.Step()
+	}
+
+	if len(g.recording.Frames) != recordedSteps {
+		t.Fatalf("recorded %d frames, want %d", len(g.recording.Frames), recordedSteps)
+	}
+	for i, frame := range g.recording.Frames {
+		o := frame.Orbit
+		if o == nil {
+			t.Fatalf("frame %d has no orbit", i)
+		}
+		if o.Body != "star" ||
+			math.Abs(o.SemiMajorAxis/testOrbitRadius-1) > tolerance ||
+			math.Abs(o.Eccentricity-e) > tolerance ||
+			math.Abs(angleDifference(o.ArgumentOfPeriapsis, argPeriapsis)) > tolerance {
+			t.Fatalf("frame %d has the orbit %+v, want a = %v, e = %v and argument of periapsis %v around the star",
+				i, *o, testOrbitRadius, e, argPeriapsis)
+		}
+	}
+
+	// the csv has the elements in the last columns of every row, empty for frames without an orbit
+	g.recording.RecordFrame(g.time, g.spaceObjects, nil)
+	path := filepath.Join(t.TempDir(), "recording.csv")
+	if err := g.recording.ExportCSV(path); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first := rows[1]; first[7] != "star" || first[9] == "" {
+		t.Errorf("first row %v has no orbit around the star", first)
+	}
+	if last := rows[len(rows)-1]; last[7] != "" || last[8] != "" {
+		t.Errorf("row %v of a frame without an orbit has orbit columns", last)
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// returns the radius in pixel the spaceobject is drawn with
+//
+// real scale uses the physical radius, which makes bodies invisible specks at orbital distances.
+// artistic scale grows with the logarithm of the radius, so bodies stay visible and keep their size order.
+// Only the drawing is affected: collisions and merging always use the physical radius,
+// so in artistic scale bodies can look like they overlap without colliding,
+// and in real scale they collide exactly where they appear to touch.
+func (g *Game) drawRadius(so *SpaceObject) float64 {
+	realRadius := so.radius * XScale * g.camera.zoom
+	if !g.artisticScale {
+		return realRadius
+	}
+	artisticRadius := 1 + math.Log10(math.Max(so.radius/1e3, 1))
+	return math.Max(realRadius, artisticRadius)
+}
+
+// draws the image of the spaceobject centered on its position with the given radius
+func drawSpaceObject(screen *ebiten.Image, so *SpaceObject, radius float64) {
+	// never draw smaller than a single pixel, otherwise the object disappears
+	diameter := math.Max(2*radius, 1)
+
+	// the image is rotated about its center by the orientation
+	width, height := float64(so.img.Bounds().Dx()), float64(so.img.Bounds().Dy())
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(-width/2, -height/2)
+	op.GeoM.Scale(diameter/width, diameter/height)
+	op.GeoM.Rotate(so.orientation)
+	op.GeoM.Translate(so.scaledPosition.X, so.scaledPosition.Y)
+	screen.DrawImage(so.img, op)
+}
+
+// returns the index of the spaceobject drawn under the given screen position, -1 if there is none
+// if multiple drawn objects contain the position, the one whose center is closest is picked
+func (g *Game) bodyIndexAt(screenPosition Vector) int {
+	world := g.screenToWorld(screenPosition)
+	pixelsPerMeter := XScale * g.camera.zoom
+
+	index := -1
+	closest := math.Inf(1)
+	for i, so := range g.spaceObjects {
+		// the drawn radius is in pixel, the same size as drawSpaceObject never goes below
+		radius := math.Max(g.drawRadius(so), 0.5) / pixelsPerMeter
+		d := so.position.DistanceSquared(world)
+		if d <= radius*radius && d < closest {
+			index = i
+			closest = d
+		}
+	}
+	return index
+}
+
+// returns the spaceobject drawn under the given screen position, nil if there is none
+func (g *Game) BodyAt(screenPosition Vector) *SpaceObject {
+	index := g.bodyIndexAt(screenPosition)
+	if index < 0 {
+		return nil
+	}
+	return g.spaceObjects[index]
+}
+
+const (
+	orbitSegments   int     = 180  // number of line segments a predicted orbit is drawn with
+	hyperbolaReach  float64 = 10   // a hyperbola is drawn until this multiple of the semi-latus rectum
+	circularEpsilon float64 = 1e-9 // below this eccentricity the periapsis direction is meaningless
+	orbitDash       int     = 3    // number of segments per dash (and per gap) of the orbit
+)
+
+var (
+	orbitColor = color.RGBA{255, 255, 255, 96}
+	nodeColor  = color.RGBA{255, 160, 64, 255}
+)
+
+const nodeMarkerRadius float32 = 3 // radius of the node markers in pixel
+
+// draws the complete orbit the spacecraft follows around its dominant body if nothing else pulled on it
+// bound orbits are drawn as the whole ellipse, escape orbits as the branch of the hyperbola
+// the orbit is recomputed every frame from the current state and drawn dashed to tell it apart from the solid trail
+func (g *Game) drawOrbit(screen *ebiten.Image) {
+	elements, body, ok := g.OrbitalElements()
+	if !ok {
+		return
+	}
+
+	points := orbitPoints(elements, body.position)
+	for i := 1; i < len(points); i++ {
+		// leave out every other group of segments
+		if (i-1)/orbitDash%2 == 1 {
+			continue
+		}
+		from := g.worldToScreen(points[i-1])
+		to := g.worldToScreen(points[i])
+		vector.StrokeLine(screen, float32(from.X), float32(from.Y), float32(to.X), float32(to.Y), 1, orbitColor, true)
+	}
+
+	// mark where the orbit crosses the reference axis through the body
+	for _, node := range elements.nodeCrossings(g.nodeAxis) {
+		p := g.worldToScreen(body.position.Translate(node.X, node.Y))
+		vector.StrokeCircle(screen, float32(p.X), float32(p.Y), nodeMarkerRadius, 1, nodeColor, true)
+	}
+}
+
+// returns the points the orbit with the given elements around a body at the given position is drawn through in m
+// bound orbits are the whole ellipse, escape orbits the branch of the hyperbola
+func orbitPoints(elements OrbitalElements, focus Vector) []Vector {
+	points := make([]Vector, 0, orbitSegments+1)
+	switch {
+	case elements.eccentricity < 1:
+		// the ellipse around its center: the body sits in a focus, a*e away from the center towards the apoapsis
+		a := elements.semiMajorAxis
+		b := a * math.Sqrt(1-elements.eccentricity*elements.eccentricity)
+		direction := Vector{math.Cos(elements.argumentOfPeriapsis), math.Sin(elements.argumentOfPeriapsis)}
+		if elements.eccentricity < circularEpsilon {
+			direction = Vector{1, 0}
+		}
+		normal := Vector{-direction.Y, direction.X}
+		center := focus.Translate(-a*elements.eccentricity*direction.X, -a*elements.eccentricity*direction.Y)
+
+		for i := 0; i <= orbitSegments; i++ {
+			E := 2 * math.Pi * float64(i) / float64(orbitSegments)
+			x := a * math.Cos(E)
+			y := b * math.Sin(E)
+			points = append(points, center.Translate(x*direction.X+y*normal.X, x*direction.Y+y*normal.Y))
+		}
+	default:
+		// the hyperbola reaches infinity at cos(nu) = -1/e, so stop where it gets too far away
+		// parabolic orbits (e = 1) are drawn the same way
+		maxRadius := hyperbolaReach * elements.semiLatusRectum
+		maxAnomaly := math.Acos(math.Max(-1, (elements.semiLatusRectum/maxRadius-1)/elements.eccentricity))
+
+		for i := 0; i <= orbitSegments; i++ {
+			nu := -maxAnomaly + 2*maxAnomaly*float64(i)/float64(orbitSegments)
+			p := elements.positionAt(nu)
+			points = append(points, focus.Translate(p.X, p.Y))
+		}
+	}
+	return points
+}
+
+const (
+	helpFontSize   float64 = 12 // font size of the help overlay
+	helpLineHeight float64 = 18 // distance between two lines of the help overlay in pixel
+	helpPadding    float64 = 12 // space between the border of the help panel and its text in pixel
+	helpMargin     float64 = 10 // smallest space between the help panel and the border of the viewport in pixel
+	helpKeyGap     float64 = 12 // space between a key and its description in pixel
+	helpColumnGap  float64 = 24 // space between two columns of the help overlay in pixel
+)
+
+var helpPanelColor = color.RGBA{0, 0, 0, 180}
+
+// a line of text of the help overlay at its position on the screen
+type helpText struct {
+	text     string
+	position Vector  // top left corner in pixel
+	width    float64 // width of the text in pixel
+}
+
+// a page of the help overlay: a panel with columns of keys and their descriptions
+type helpPage struct {
+	x, y, width, height float64 // panel in pixel
+	texts               []helpText
+}
+
+// lays out the keys and descriptions of the actions in columns as high as the bounds allow
+// columns are added to a page as long as it fits the width of the bounds, the others go to further pages;
+// with more than one page, the last line of each page tells how to turn it
+// measure returns the width of a text in pixel
+func layoutHelp(keys, descriptions []string, bounds image.Rectangle, turnKey string, measure func(string) float64) []helpPage {
+	// a line is kept free for the page number in case there is more than one page
+	rows := max(1, int((float64(bounds.Dy())-2*helpMargin-2*helpPadding)/helpLineHeight)-1)
+	maxWidth := float64(bounds.Dx()) - 2*helpMargin
+
+	// the columns of every page with the entries they hold
+	type column struct {
+		start, end int
+		keyWidth   float64 // width of the widest key in pixel
+		width      float64 // width of the widest key plus the widest description in pixel
+	}
+	var pages [][]column
+	pageWidth := 0.0
+	for start := 0; start < len(keys); start += rows {
+		c := column{start: start, end: min(start+rows, len(keys))}
+		descriptionWidth := 0.0
+		for i := c.start; i < c.end; i++ {
+			c.keyWidth = max(c.keyWidth, measure(keys[i]))
+			descriptionWidth = max(descriptionWidth, measure(descriptions[i]))
+		}
+		c.width = c.keyWidth + helpKeyGap + descriptionWidth
+		if len(pages) == 0 || pageWidth+helpColumnGap+c.width > maxWidth-2*helpPadding {
+			pages = append(pages, nil)
+			pageWidth = c.width
+		} else {
+			pageWidth += helpColumnGap + c.width
+		}
+		pages[len(pages)-1] = append(pages[len(pages)-1], c)
+	}
+
+	var layout []helpPage
+	for number, columns := range pages {
+		var footer string
+		lines := columns[0].end - columns[0].start
+		width := -helpColumnGap
+		for _, c := range columns {
+			width += helpColumnGap + c.width
+		}
+		if len(pages) > 1 {
+			footer = fmt.Sprintf("page %d of %d, %s turns the page", number+1, len(pages), turnKey)
+			width = max(width, measure(footer))
+			lines++
+		}
+		page := helpPage{width: width + 2*helpPadding, height: float64(lines)*helpLineHeight + 2*helpPadding}
+		page.x = float64(bounds.Min.X) + (float64(bounds.Dx())-page.width)/2
+		page.y = float64(bounds.Min.Y) + (float64(bounds.Dy())-page.height)/2
+
+		left := page.x + helpPadding
+		for _, c := range columns {
+			for i := c.start; i < c.end; i++ {
+				top := page.y + helpPadding + float64(i-c.start)*helpLineHeight
+				page.texts = append(page.texts,
+					helpText{text: keys[i], position: Vector{left, top}, width: measure(keys[i])},
+					helpText{text: descriptions[i], position: Vector{left + c.keyWidth + helpKeyGap, top}, width: measure(descriptions[i])})
+			}
+			left += c.width + helpColumnGap
+		}
+		if footer != "" {
+			top := page.y + helpPadding + float64(lines-1)*helpLineHeight
+			page.texts = append(page.texts, helpText{text: footer, position: Vector{page.x + helpPadding, top}, width: measure(footer)})
+		}
+		layout = append(layout, page)
+	}
+	return layout
+}
+
+// returns the pages of the help overlay for the current key bindings and viewport
+func (g *Game) helpPages() []helpPage {
+	face := &text.GoTextFace{Source: mplusFaceSource, Size: helpFontSize}
+	measure := func(s string) float64 {
+		width, _ := text.Measure(s, face, 0)
+		return width
+	}
+	var keys, descriptions []string
+	for _, action := range g.keys.actions() {
+		keys = append(keys, g.keys[action].String())
+		descriptions = append(descriptions, actionDescriptions[action])
+	}
+	return layoutHelp(keys, descriptions, g.viewport(), g.keys[actionHelp].String(), measure)
+}
+
+// shows the help, turns to its next page, or hides it after the last one
+func (g *Game) toggleHelp() {
+	if !g.showHelp {
+		g.showHelp, g.helpPage = true, 0
+		return
+	}
+	g.helpPage++
+	if g.helpPage >= len(g.helpPages()) {
+		g.showHelp = false
+	}
+}
+
+// draws a semi-transparent panel listing every action and the key it is bound to
+// the actions are spread over columns and pages that fit the viewport
+func (g *Game) drawHelp(screen *ebiten.Image) {
+	pages := g.helpPages()
+	// the viewport may have grown since the page was turned
+	page := pages[min(g.helpPage, len(pages)-1)]
+
+	vector.DrawFilledRect(screen, float32(page.x), float32(page.y), float32(page.width), float32(page.height), helpPanelColor, false)
+
+	face := &text.GoTextFace{Source: mplusFaceSource, Size: helpFontSize}
+	for _, t := range page.texts {
+		op := &text.DrawOptions{}
+		op.GeoM.Translate(t.position.X, t.position.Y)
+		text.Draw(screen, t.text, face, op)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// returns a game with two earth sized bodies on the x axis, the second one the given distance in m from the first
+func hitTestGame(distance float64) *Game {
+	g := newGame()
+	g.screenWidth, g.screenHeight = 800, 600
+	g.spaceObjects = []*SpaceObject{
+		{name: "a", mass: 1, radius: 6.371e6},
+		{name: "b", mass: 1, radius: 6.371e6, position: Vector{distance, 0}},
+	}
+	return g
+}
+
+func TestBodyAt(t *testing.T) {
+	// at zoom 1 the artistic radius of an earth sized body is 1+log10(6371) ≈ 4.8 pixel, 1e8 m are 10 pixel
+	tests := []struct {
+		name     string
+		distance float64 // of the second body from the first in m
+		zoom     float64
+		artistic bool
+		offset   Vector // of the click from the first body in pixel
+		want     string // name of the hit body, empty for none
+	}{
+		{"inside", 1e8, 1, true, Vector{3, 0}, "a"},
+		{"between", 1e8, 1, true, Vector{-7, 0}, ""},
+		{"second body", 1e8, 1, true, Vector{11, 1}, "b"},
+		{"overlap closer to the first", 5e7, 1, true, Vector{2, 0}, "a"},
+		{"overlap closer to the second", 5e7, 1, true, Vector{3, 0}, "b"},
+		{"real scale misses", 1e8, 1, false, Vector{3, 0}, ""},
+		{"real scale zoomed in", 1e10, 1000, false, Vector{0, 600}, "a"},
+		{"real scale zoomed in outside", 1e10, 1000, false, Vector{0, 650}, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := hitTestGame(test.distance)
+			g.camera.zoom = test.zoom
+			g.artisticScale = test.artistic
+
+			click := g.worldToScreen(g.spaceObjects[0].position).Translate(test.offset.X, test.offset.Y)
+			got := ""
+			if so := g.BodyAt(click); so != nil {
+				got = so.name
+			}
+			if got != test.want {
+				t.Errorf("BodyAt(%v) = %q, want %q", click, got, test.want)
+			}
+		})
+	}
+}
+
+// returns the distance in m from the given point to the polyline through the points
+func polylineDistance(p Vector, points []Vector) float64 {
+	distance := math.Inf(1)
+	for i := 1; i < len(points); i++ {
+		distance = math.Min(distance, pointSegmentDistance(p, points[i-1], points[i]))
+	}
+	return distance
+}
+
+func TestOrbitPointsPassThroughTheSpacecraft(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(g *Game)
+	}{
+		{"circular", func(g *Game) {}},
+		{"elliptic", func(g *Game) {
+			*g = *ellipticOrbitGame(testOrbitRadius, 0.3, 0.5, 2)
+		}},
+		{"elliptic clockwise", func(g *Game) {
+			*g = *ellipticOrbitGame(testOrbitRadius, 0.6, -1, 4)
+			g.spaceObjects[1].velocity = g.spaceObjects[1].velocity.Scale(-1, -1)
+		}},
+		{"hyperbolic", func(g *Game) {
+			g.spaceObjects[1].velocity = Vector{0, 1.5 * g.config.EscapeVelocity(testStarMass, testOrbitRadius)}
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := circularOrbitGame(testOrbitRadius)
+			test.setup(g)
+			elements, body, ok := g.OrbitalElements()
+			if !ok {
+				t.Fatal("no orbital elements")
+			}
+
+			// the chords of the drawn segments stay within a fraction of a percent of the curve
+			craft := g.spaceObjects[1]
+			if d := polylineDistance(craft.position, orbitPoints(elements, body.position)); d > 1e-3*testOrbitRadius {
+				t.Errorf("drawn orbit passes %v m from the spacecraft", d)
+			}
+		})
+	}
+}
+
+func TestTrailFollowsTheOsculatingOrbitOfATwoBodySystem(t *testing.T) {
+	g := ellipticOrbitGame(testOrbitRadius, 0.3, 0.5, 0)
+	g.config.integrator = integratorRK4
+
+	// the trail is where the spacecraft was, relative to the star
+	var trail []Vector
+	for range 500 {
+		g.Step()
+		star, craft := g.spaceObjects[0], g.spaceObjects[1]
+		trail = append(trail, craft.position.Translate(-star.position.X, -star.position.Y))
+	}
+
+	// without a third body the orbit stays fixed, so the whole trail lies on the current osculating orbit
+	elements, _, ok := g.OrbitalElements()
+	if !ok {
+		t.Fatal("no orbital elements")
+	}
+	points := orbitPoints(elements, Vector{0, 0})
+	worst := 0.0
+	for _, p := range trail {
+		worst = math.Max(worst, polylineDistance(p, points))
+	}
+	if worst > 1e-3*testOrbitRadius {
+		t.Errorf("trail strays up to %v m from the osculating orbit", worst)
+	}
+}
+
+func TestHelpFitsTheScreen(t *testing.T) {
+	tests := []struct {
+		width, height int
+		pages         int // 0 if any number of pages is fine
+	}{
+		{1080, 720, 0},
+		{1920, 1080, 1},
+		{640, 480, 0},
+	}
+	for _, test := range tests {
+		g := newGame()
+		g.screenWidth, g.screenHeight = test.width, test.height
+		pages := g.helpPages()
+		if test.pages != 0 && len(pages) != test.pages {
+			t.Errorf("help on a %dx%d screen has %d pages, want %d", test.width, test.height, len(pages), test.pages)
+		}
+
+		shown := map[string]int{}
+		for number, page := range pages {
+			if page.x < 0 || page.y < 0 || page.x+page.width > float64(test.width) || page.y+page.height > float64(test.height) {
+				t.Errorf("panel of page %d at (%v, %v) sized %vx%v does not fit a %dx%d screen",
+					number, page.x, page.y, page.width, page.height, test.width, test.height)
+			}
+			for _, line := range page.texts {
+				if line.position.X < page.x || line.position.Y < page.y ||
+					line.position.X+line.width > page.x+page.width || line.position.Y+helpLineHeight > page.y+page.height {
+					t.Errorf("%q at %v is outside the panel of page %d on a %dx%d screen", line.text, line.position, number, test.width, test.height)
+				}
+				shown[line.text]++
+			}
+		}
+		for action, description := range actionDescriptions {
+			if shown[description] != 1 {
+				t.Errorf("description of %q is shown %d times on a %dx%d screen, want once", action, shown[description], test.width, test.height)
+			}
+		}
+
+		// the help key goes through every page and then hides the help
+		for number := range pages {
+			g.toggleHelp()
+			if !g.showHelp || g.helpPage != number {
+				t.Errorf("after %d presses the help shows %v page %d, want page %d", number+1, g.showHelp, g.helpPage, number)
+			}
+		}
+		if g.toggleHelp(); g.showHelp {
+			t.Error("help is still shown after its last page")
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"log"
+	"os"
+	"sort"
+)
+
+const (
+	fastForwardSpeed float64 = 20 // time steps a fast-forward advances per frame
+	scrubSteps       int     = 10 // number of time steps a scrub jumps forward or back
+)
+
+// reads a recording exported with ExportJSON
+func LoadRecording(path string) (*Recording, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var recording Recording
+	if err := json.Unmarshal(data, &recording); err != nil {
+		return nil, fmt.Errorf("reading recording from %s: %w", path, err)
+	}
+	if len(recording.Frames) == 0 {
+		return nil, fmt.Errorf("recording %s has no frames", path)
+	}
+	return &recording, nil
+}
+
+// returns the time of the first and the last frame
+func (r *Recording) timeRange() (start, end float64) {
+	return r.Frames[0].Time, r.Frames[len(r.Frames)-1].Time
+}
+
+// returns the state of all bodies at the given time, interpolated linearly between the two closest frames
+// bodies are matched by name, a body missing in the later frame keeps its state of the earlier one
+func (r *Recording) StateAt(time float64) []RecordedBody {
+	// index of the first frame after the time
+	next := sort.Search(len(r.Frames), func(i int) bool { return r.Frames[i].Time > time })
+	if next == 0 {
+		return r.Frames[0].Bodies
+	}
+	if next == len(r.Frames) {
+		return r.Frames[len(r.Frames)-1].Bodies
+	}
+
+	from := r.Frames[next-1]
+	to := r.Frames[next]
+	t := (time - from.Time) / (to.Time - from.Time)
+
+	bodies := make([]RecordedBody, len(from.Bodies))
+	for i, body := range from.Bodies {
+		bodies[i] = body
+		for _, other := range to.Bodies {
+			if other.Name != body.Name {
+				continue
+			}
+			bodies[i].Position = body.Position.Lerp(other.Position, t)
+			bodies[i].Velocity = body.Velocity.Lerp(other.Velocity, t)
+			break
+		}
+	}
+	return bodies
+}
+
+// Replay plays back a recording instead of integrating
+// the live simulation is kept and restored when the replay ends
+type Replay struct {
+	recording          *Recording
+	time               float64        // current time of the replay
+	liveSpaceObjects   []*SpaceObject // spaceobjects of the simulation before the replay started
+	liveTime           float64        // time of the simulation before the replay started
+	replaySpaceObjects map[string]*SpaceObject
+
+	speed    float64 // time steps the replay advances per frame
+	autoStop bool    // the replay ends by itself once it reaches stopTime or the end of the recording
+	stopTime float64 // time the replay ends at if autoStop is set
+}
+
+// starts replaying the given recording from its first frame
+func (g *Game) startReplay(recording *Recording) {
+	start, _ := recording.timeRange()
+	g.replay = &Replay{
+		recording:          recording,
+		time:               start,
+		liveSpaceObjects:   g.spaceObjects,
+		liveTime:           g.time,
+		replaySpaceObjects: map[string]*SpaceObject{},
+		speed:              1,
+	}
+	g.clearPaths()
+	g.applyReplay()
+}
+
+// ends the replay and continues the live simulation where it was left
+func (g *Game) stopReplay() {
+	g.spaceObjects = g.replay.liveSpaceObjects
+	g.time = g.replay.liveTime
+	g.replay = nil
+	g.clearPaths()
+}
+
+// moves the replay to the given time, limited to the recorded time range
+func (g *Game) seekReplay(time float64) {
+	start, end := g.replay.recording.timeRange()
+	if time < g.replay.time {
+		// the path drawn so far lies in the future now
+		g.clearPaths()
+	}
+	g.replay.time = max(start, min(end, time))
+	g.applyReplay()
+}
+
+// moves the replay to the given time and redraws the trails through every recorded frame up to it
+// a plain seek only connects the old and the new position, which cuts across a long jump
+func (g *Game) jumpReplay(time float64) {
+	g.clearPaths()
+	for so, points := range g.replayTrailPoints(time) {
+		for _, p := range points {
+			g.extendPath(so, p, g.screenWidth, g.screenHeight)
+		}
+	}
+	g.seekReplay(time)
+	g.camera.jumpTo(g.focusPosition())
+}
+
+// returns the screen positions of the spaceobjects with a trail at every recorded frame before the given time
+// each frame is seen with the camera on the focus of that frame, like the playback would have drawn it
+// leaves the replay at the last of these frames
+func (g *Game) replayTrailPoints(time float64) map[*SpaceObject][]Vector {
+	points := map[*SpaceObject][]Vector{}
+	for _, frame := range g.replay.recording.Frames {
+		if frame.Time >= time {
+			break
+		}
+		g.seekReplay(frame.Time)
+		g.camera.jumpTo(g.focusPosition())
+		for _, so := range g.spaceObjects {
+			if so.showTrail {
+				points[so] = append(points[so], g.worldToScreen(so.position))
+			}
+		}
+	}
+	return points
+}
+
+// sets the spaceobjects to the recorded state at the replay time
+// the looks of bodies are taken from the live simulation if a body with the same name exists there
+func (g *Game) applyReplay() {
+	bodies := g.replay.recording.StateAt(g.replay.time)
+
+	spaceObjects := make([]*SpaceObject, len(bodies))
+	for i, body := range bodies {
+		so, ok := g.replay.replaySpaceObjects[body.Name]
+		if !ok {
+			so = g.replayObject(body.Name)
+			g.replay.replaySpaceObjects[body.Name] = so
+		}
+		so.position = body.Position
+		so.velocity = body.Velocity
+		spaceObjects[i] = so
+	}
+
+	g.spaceObjects = spaceObjects
+	g.time = g.replay.time
+}
+
+// returns a new spaceobject for the replay that looks like the live one with the given name
+func (g *Game) replayObject(name string) *SpaceObject {
+	for _, live := range g.replay.liveSpaceObjects {
+		if live.name == name {
+			copied := *live
+			copied.pathImg = nil
+			copied.hasPathPoint = false
+			return &copied
+		}
+	}
+	return &SpaceObject{
+		name:  name,
+		img:   createEmptyColoredImage(2, 2, color.White),
+		color: color.White,
+	}
+}
+
+// plays the replay forward by one time step and handles the scrubbing keys
+func (g *Game) updateReplay() {
+	switch {
+	case g.keys.JustPressed(actionScrubBack):
+		g.seekReplay(g.replay.time - float64(scrubSteps)*dt)
+	case g.keys.JustPressed(actionScrubForward):
+		g.seekReplay(g.replay.time + float64(scrubSteps)*dt)
+	default:
+		g.seekReplay(g.replay.time + g.replay.speed*dt)
+	}
+
+	// a fast-forward hands control back to the live simulation once it is done
+	_, end := g.replay.recording.timeRange()
+	if g.replay.autoStop && g.replay.time >= min(end, g.replay.stopTime) {
+		g.stopReplay()
+	}
+}
+
+// plays the given recording at fastForwardSpeed up to the given time and then continues the live simulation
+// the live state is restored when the replay ends, so the fast-forward does not change it
+func (g *Game) startFastForward(recording *Recording, until float64) {
+	g.startReplay(recording)
+	g.replay.speed = fastForwardSpeed
+	g.replay.autoStop = true
+	g.replay.stopTime = until
+}
+
+// starts replaying the exported recording, or ends the replay if one is running
+func (g *Game) toggleReplay() {
+	if g.replay != nil {
+		g.stopReplay()
+		return
+	}
+	recording, err := LoadRecording(recordingJSONPath)
+	if err != nil {
+		log.Printf("starting replay failed: %v\n", err)
+		return
+	}
+	g.startReplay(recording)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// returns a recording of the given number of steps of the single flyby scene
+func recordedRun(t *testing.T, steps int) *Recording {
+	g := NewGame()
+	g.recording.Start()
+	for range steps {
+		g.Step()
+	}
+
+	// go through the exported file like a real replay
+	path := filepath.Join(t.TempDir(), "recording.json")
+	if err := g.recording.ExportJSON(path); err != nil {
+		t.Fatal(err)
+	}
+	recording, err := LoadRecording(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return recording
+}
+
+func TestReplayReproducesTheRecordedPositions(t *testing.T) {
+	recording := recordedRun(t, 20)
+
+	g := NewGame()
+	live := g.spaceObjects
+	g.startReplay(recording)
+	for _, frame := range recording.Frames {
+		g.seekReplay(frame.Time)
+		if g.time != frame.Time {
+			t.Errorf("replay time is %v, want %v", g.time, frame.Time)
+		}
+		for i, body := range frame.Bodies {
+			so := g.spaceObjects[i]
+			if so.name != body.Name || so.position != body.Position || so.velocity != body.Velocity {
+				t.Errorf("at %v s %q is at %v moving %v, want %q at %v moving %v",
+					frame.Time, so.name, so.position, so.velocity, body.Name, body.Position, body.Velocity)
+			}
+		}
+	}
+
+	// halfway between two frames the positions are interpolated
+	from, to := recording.Frames[4], recording.Frames[5]
+	g.seekReplay((from.Time + to.Time) / 2)
+	for i, body := range from.Bodies {
+		want := body.Position.Lerp(to.Bodies[i].Position, 0.5)
+		if got := g.spaceObjects[i].position; got != want {
+			t.Errorf("halfway %q is at %v, want %v", body.Name, got, want)
+		}
+	}
+
+	// seeking is limited to the recorded time range
+	start, end := recording.timeRange()
+	g.seekReplay(end + 100*dt)
+	if g.time != end {
+		t.Errorf("seeking past the end went to %v, want %v", g.time, end)
+	}
+	g.seekReplay(start - 100*dt)
+	if g.time != start {
+		t.Errorf("seeking before the start went to %v, want %v", g.time, start)
+	}
+
+	g.stopReplay()
+	if &g.spaceObjects[0] != &live[0] || g.time != 0 {
+		t.Error("the live simulation was not restored after the replay")
+	}
+}
+
+func TestJumpingToARecordedTimeGivesItsSample(t *testing.T) {
+	recording := recordedRun(t, 20)
+	for _, frame := range recording.Frames {
+		for i, body := range recording.StateAt(frame.Time) {
+			if body != frame.Bodies[i] {
+				t.Errorf("state at %v s of %q is %+v, want the recorded %+v", frame.Time, body.Name, body, frame.Bodies[i])
+			}
+		}
+	}
+
+	g := NewGame()
+	// the trails would be drawn into images, which needs a running game
+	for _, so := range g.spaceObjects {
+		so.showTrail = false
+	}
+	g.startReplay(recording)
+	_, end := recording.timeRange()
+	// forwards from the start, then backwards from the end
+	for _, index := range []int{13, 7} {
+		frame := recording.Frames[index]
+		g.jumpReplay(frame.Time)
+		if g.time != frame.Time {
+			t.Errorf("jump to %v s went to %v s", frame.Time, g.time)
+		}
+		for i, body := range frame.Bodies {
+			if so := g.spaceObjects[i]; so.position != body.Position || so.velocity != body.Velocity {
+				t.Errorf("after the jump to %v s %q is at %v moving %v, want %v moving %v",
+					frame.Time, so.name, so.position, so.velocity, body.Position, body.Velocity)
+			}
+		}
+		g.jumpReplay(end)
+	}
+}
+
+func TestJumpRedrawsTheTrailsAroundTheFocus(t *testing.T) {
+	recording := recordedRun(t, 20)
+	g := NewGame()
+	g.screenWidth, g.screenHeight = 800, 600
+	g.startReplay(recording)
+	// the camera follows the spacecraft and lags behind like it does during playback
+	g.focus = 2
+	g.camera.smoothing = 0.1
+	g.updateView()
+
+	frames := recording.Frames
+	jump := frames[15].Time
+	points := g.replayTrailPoints(jump)
+	center := g.viewportCenter()
+	for so, got := range points {
+		if len(got) != 15 {
+			t.Fatalf("%q has %d trail points, want one for each of the 15 frames before the jump", so.name, len(got))
+		}
+		for k, p := range got {
+			// relative to the spacecraft of the same frame, as the playback draws it
+			var position, focus Vector
+			for _, body := range frames[k].Bodies {
+				if body.Name == so.name {
+					position = body.Position
+				}
+				if body.Name == g.spaceObjects[2].name {
+					focus = body.Position
+				}
+			}
+			want := position.Translate(-focus.X, -focus.Y).Scale(XScale, YScale).Translate(center.X, center.Y)
+			if math.Sqrt(p.DistanceSquared(want)) > 1e-6 {
+				t.Errorf("trail point %d of %q is at %v, want %v", k, so.name, p, want)
+			}
+		}
+	}
+
+	// playback continues in the frame of the jump instead of easing over from where the camera was before
+	for _, so := range g.spaceObjects {
+		so.showTrail = false
+	}
+	g.jumpReplay(jump)
+	if got, want := g.cameraFocus(), g.focusPosition(); got != want {
+		t.Errorf("camera is at %v after the jump, want on the spacecraft at %v", got, want)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const (
+	resonanceMaxOrder  int     = 5    // largest integer of a resonance ratio p:q that is looked for
+	resonanceTolerance float64 = 0.02 // relative deviation of the period ratio from p:q that still counts as resonance
+	resonanceFontSize  float64 = 12   // font size of the resonance panel
+	resonancePanelSize float64 = 320  // width of the resonance panel in pixel
+)
+
+// a mean-motion resonance between two planets: the outer one completes q orbits while the inner one completes p
+type Resonance struct {
+	inner, outer *SpaceObject
+	p, q         int
+	ratio        float64 // actual period ratio of the outer to the inner planet
+}
+
+// returns the body all planets orbit: the heaviest one that is not a spacecraft, nil if there is none
+func (g *Game) centralBody() *SpaceObject {
+	var central *SpaceObject
+	for _, so := range g.spaceObjects {
+		if !so.isSpacecraft && (central == nil || so.mass > central.mass) {
+			central = so
+		}
+	}
+	return central
+}
+
+// returns the orbital period of the planet around the central body in s, NaN if the orbit is not bound
+func (g *Game) orbitalPeriod(planet, central *SpaceObject) float64 {
+	r := planet.position.Translate(-central.position.X, -central.position.Y)
+	v := planet.velocity.Translate(-central.velocity.X, -central.velocity.Y)
+	elements := computeOrbitalElements(r, v, g.config.gravitationalConstant()*central.mass)
+	return g.config.OrbitalPeriod(central.mass, elements.semiMajorAxis)
+}
+
+// returns the smallest ratio p:q (p > q) within resonanceTolerance of the given ratio, false if there is none
+func nearestResonance(ratio float64) (p, q int, ok bool) {
+	for q = 1; q <= resonanceMaxOrder; q++ {
+		for p = q + 1; p <= resonanceMaxOrder; p++ {
+			target := float64(p) / float64(q)
+			// p and q with a common divisor were already found as the reduced ratio
+			if gcd(p, q) == 1 && math.Abs(ratio-target)/target <= resonanceTolerance {
+				return p, q, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// returns the greatest common divisor of a and b
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// returns all pairs of planets whose orbital periods around the central body are close to a small integer ratio
+func (g *Game) Resonances() []Resonance {
+	central := g.centralBody()
+	if central == nil {
+		return nil
+	}
+
+	var planets []*SpaceObject
+	var periods []float64
+	for _, so := range g.spaceObjects {
+		if so == central || so.isSpacecraft {
+			continue
+		}
+		if period := g.orbitalPeriod(so, central); !math.IsNaN(period) {
+			planets = append(planets, so)
+			periods = append(periods, period)
+		}
+	}
+
+	var resonances []Resonance
+	for i := range planets {
+		for j := i + 1; j < len(planets); j++ {
+			inner, outer := i, j
+			if periods[outer] < periods[inner] {
+				inner, outer = outer, inner
+			}
+			ratio := periods[outer] / periods[inner]
+			if p, q, ok := nearestResonance(ratio); ok {
+				resonances = append(resonances, Resonance{inner: planets[inner], outer: planets[outer], p: p, q: q, ratio: ratio})
+			}
+		}
+	}
+	return resonances
+}
+
+// draws the detected resonances into a panel at the top right of the viewport
+func (g *Game) drawResonances(screen *ebiten.Image) {
+	lines := "Resonances"
+	resonances := g.Resonances()
+	if len(resonances) == 0 {
+		lines += "\nnone"
+	}
+	for _, r := range resonances {
+		lines += fmt.Sprintf("\n%s - %s  %d:%d (%.3f)", r.inner.name, r.outer.name, r.p, r.q, r.ratio)
+	}
+
+	viewport := g.viewport()
+	op := &text.DrawOptions{}
+	op.LineSpacing = resonanceFontSize * 1.5
+	op.GeoM.Translate(float64(viewport.Max.X)-resonancePanelSize, float64(viewport.Min.Y))
+	text.Draw(screen, lines, &text.GoTextFace{Source: mplusFaceSource, Size: resonanceFontSize}, op)
+}
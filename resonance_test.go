@@ -0,0 +1,50 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestResonances(t *testing.T) {
+	g := circularOrbitGame(testOrbitRadius)
+	// by kepler's third law the period grows with the radius to the power of 3/2
+	planet := func(name string, periodRatio float64) *SpaceObject {
+		radius := testOrbitRadius * math.Pow(periodRatio, 2.0/3)
+		speed := g.config.CircularOrbitVelocity(testStarMass, radius)
+		return &SpaceObject{name: name, mass: 1e24, radius: 1e6, position: Vector{-radius, 0}, velocity: Vector{0, -speed}}
+	}
+	inner := planet("inner", 1)
+	outer := planet("outer", 2)
+	// far is 2.25 times as slow as outer and 4.5 times as slow as inner, more than the tolerance away from any ratio
+	far := planet("far", 4.5)
+	g.spaceObjects = append(g.spaceObjects, inner, outer, far)
+
+	resonances := g.Resonances()
+	if len(resonances) != 1 {
+		t.Fatalf("found %d resonances, want only the 2:1 of inner and outer: %v", len(resonances), resonances)
+	}
+	r := resonances[0]
+	if r.inner != inner || r.outer != outer || r.p != 2 || r.q != 1 || math.Abs(r.ratio-2) > 1e-9 {
+		t.Errorf("found %s - %s %d:%d (%v), want inner - outer 2:1 (2)", r.inner.name, r.outer.name, r.p, r.q, r.ratio)
+	}
+}
+
+func TestNearestResonance(t *testing.T) {
+	tests := []struct {
+		ratio float64
+		p, q  int
+		ok    bool
+	}{
+		{2, 2, 1, true},
+		{2.03, 2, 1, true},
+		{1.5, 3, 2, true},
+		{5.0 / 3, 5, 3, true},
+		{2.25, 0, 0, false},
+		{1.1, 0, 0, false},
+	}
+	for _, test := range tests {
+		if p, q, ok := nearestResonance(test.ratio); p != test.p || q != test.q || ok != test.ok {
+			t.Errorf("nearestResonance(%v) = %d:%d, %v, want %d:%d, %v", test.ratio, p, q, ok, test.p, test.q, test.ok)
+		}
+	}
+}
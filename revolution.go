@@ -0,0 +1,71 @@
+package main
+
+import (
+	"math"
+	"strconv"
+)
+
+// measures the time the spacecraft needs for a full revolution around its dominant body
+// the angle around the body is summed up step by step, so a revolution ends when it swept 2 pi, wherever it started
+// on a keplerian orbit that is the same as the true anomaly returning to its start, but it does not depend on the
+// periapsis, which turns along with the spacecraft on a nearly circular orbit the integrator slowly deforms
+type revolutionTracker struct {
+	body      *SpaceObject // body the revolution is measured around, nil before the first step
+	angle     float64      // direction of the spacecraft seen from the body after the last step in rad
+	swept     float64      // change of the angle since the revolution started in rad
+	startTime float64      // simulated time the revolution started at in s
+
+	measured float64 // duration of the last completed revolution in s, 0 if none was completed yet
+	analytic float64 // period of the osculating keplerian orbit when the last revolution was completed in s
+}
+
+// adds the angle the spacecraft swept around its body in the last step and measures the period once it swept 2 pi
+// the revolution starts over around a new dominant body or when the orbit is no longer bound
+// the time the angle crossed 2 pi is interpolated within the step, called before the time is advanced
+func (g *Game) trackRevolution() {
+	t := &g.revolution
+	elements, body, ok := g.OrbitalElements()
+	if !ok || elements.eccentricity >= 1 {
+		t.body = nil
+		return
+	}
+	craft := g.spaceObjects[g.spacecraftIndex()]
+	now := g.time + dt
+	angle := craft.position.Translate(-body.position.X, -body.position.Y).Angle()
+
+	if body != t.body {
+		*t = revolutionTracker{
+			body:      body,
+			angle:     angle,
+			startTime: now,
+			measured:  t.measured,
+			analytic:  t.analytic,
+		}
+		return
+	}
+
+	delta := angleDifference(angle, t.angle)
+	t.angle = angle
+	t.swept += delta
+	if math.Abs(t.swept) < 2*math.Pi || delta == 0 {
+		return
+	}
+
+	excess := math.Abs(t.swept) - 2*math.Pi
+	end := now - dt*excess/math.Abs(delta)
+	t.measured = end - t.startTime
+	t.analytic = g.config.OrbitalPeriod(body.mass, elements.semiMajorAxis)
+	t.startTime = end
+	t.swept = math.Copysign(excess, t.swept)
+}
+
+// returns the measured and the analytic period of the last revolution as a line of the HUD, empty before the first one
+func (g *Game) revolutionStatus() string {
+	t := g.revolution
+	if t.measured == 0 {
+		return ""
+	}
+	deviation := (t.measured - t.analytic) / t.analytic * 100
+	return "Period: measured " + g.units.FormatTime(t.measured) + ", analytic " + g.units.FormatTime(t.analytic) +
+		" (" + strconv.FormatFloat(deviation, 'f', 3, 64) + " %)"
+}
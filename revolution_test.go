@@ -0,0 +1,42 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRevolutionPeriodOfACircularOrbit(t *testing.T) {
+	// a period takes about 731 steps, the semi-implicit euler gets it right to a few 1e-5
+	tests := []struct {
+		name       string
+		integrator Integrator
+		tolerance  float64 // relative deviation of the measured from the analytic period
+	}{
+		{"euler", integratorEuler, 1e-4},
+		{"rk4", integratorRK4, 1e-9},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := circularOrbitGame(testOrbitRadius)
+			g.config.integrator = test.integrator
+			period := g.config.OrbitalPeriod(testStarMass, testOrbitRadius)
+
+			// every revolution is measured on its own, the next one starts where the last one ended
+			for revolution := 1; revolution <= 3; revolution++ {
+				last := g.revolution.measured
+				for g.revolution.measured == last {
+					if g.time > float64(revolution+1)*period {
+						t.Fatalf("revolution %d not measured after %v s, the period is %v s", revolution, g.time, period)
+					}
+					g.Step()
+				}
+				if got := g.revolution.measured; math.Abs(got/period-1) > test.tolerance {
+					t.Errorf("revolution %d took %v s, want %v s", revolution, got, period)
+				}
+				if got := g.revolution.analytic; math.Abs(got/period-1) > test.tolerance {
+					t.Errorf("analytic period after revolution %d is %v s, want %v s", revolution, got, period)
+				}
+			}
+		})
+	}
+}
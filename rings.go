@@ -0,0 +1,40 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	defaultRingSpacing float64 = 1e9 // distance between two distance rings in m
+	defaultRingCount   int     = 5   // number of distance rings around the selected body
+	ringFontSize       float64 = 11  // font size of the ring labels
+)
+
+var ringColor = color.RGBA{160, 160, 255, 120}
+
+// draws concentric rings every ringSpacing m around the selected body, each labeled with its radius
+// the rings are in world space, so they pan and zoom with everything else
+func (g *Game) drawDistanceRings(screen *ebiten.Image) {
+	so := g.selectedObject()
+	if so == nil || g.ringSpacing <= 0 {
+		return
+	}
+
+	center := g.worldToScreen(so.position)
+	face := &text.GoTextFace{Source: mplusFaceSource, Size: ringFontSize}
+	for i := 1; i <= g.ringCount; i++ {
+		radius := float64(i) * g.ringSpacing
+		screenRadius := g.worldLengthToScreen(radius)
+		vector.StrokeCircle(screen, float32(center.X), float32(center.Y), float32(screenRadius), 1, ringColor, true)
+
+		// the label sits on top of the ring
+		op := &text.DrawOptions{}
+		op.GeoM.Translate(center.X+2, center.Y-screenRadius-ringFontSize-2)
+		op.ColorScale.ScaleWithColor(ringColor)
+		text.Draw(screen, g.units.FormatLength(radius), face, op)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const rulerFontSize float64 = 12 // font size of the measured distance
+
+var rulerColor = color.RGBA{255, 255, 0, 220}
+
+// Ruler measures the distance between two points clicked on screen
+// the points are stored relative to the focus frame, so they move along with the focused object
+type Ruler struct {
+	active   bool   // clicks place the ruler points instead of selecting a body
+	anchor   Vector // first point in m
+	end      Vector // second point in m, only valid once done
+	anchored bool   // the first point is set
+	done     bool   // the second point is set, the measurement stays until it is cleared
+}
+
+// places the next ruler point at the given screen position
+// a click after a finished measurement starts a new one
+func (g *Game) placeRulerPoint(screenPosition Vector) {
+	p := g.screenToWorld(screenPosition)
+	focus := g.cameraFocus()
+	p = p.Translate(-focus.X, -focus.Y)
+
+	if !g.ruler.anchored || g.ruler.done {
+		g.ruler.anchor = p
+		g.ruler.anchored = true
+		g.ruler.done = false
+		return
+	}
+	g.ruler.end = p
+	g.ruler.done = true
+}
+
+// removes the measurement
+func (g *Game) clearRuler() {
+	g.ruler.anchored = false
+	g.ruler.done = false
+}
+
+// draws the line between the ruler points and its length in the configured units
+// while only the anchor is set, the line follows the cursor
+func (g *Game) drawRuler(screen *ebiten.Image) {
+	if !g.ruler.anchored {
+		return
+	}
+
+	focus := g.cameraFocus()
+	from := g.ruler.anchor.Translate(focus.X, focus.Y)
+	var to Vector
+	if g.ruler.done {
+		to = g.ruler.end.Translate(focus.X, focus.Y)
+	} else {
+		x, y := ebiten.CursorPosition()
+		to = g.screenToWorld(Vector{float64(x), float64(y)})
+	}
+
+	fromScreen := g.worldToScreen(from)
+	toScreen := g.worldToScreen(to)
+	vector.StrokeLine(screen, float32(fromScreen.X), float32(fromScreen.Y), float32(toScreen.X), float32(toScreen.Y), 1, rulerColor, true)
+
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(toScreen.X+6, toScreen.Y+6)
+	op.ColorScale.ScaleWithColor(rulerColor)
+	distance := g.units.FormatLength(math.Sqrt(from.DistanceSquared(to)))
+	text.Draw(screen, distance, &text.GoTextFace{Source: mplusFaceSource, Size: rulerFontSize}, op)
+}
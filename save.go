@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"io/fs"
+	"math"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultSavePath string = "save.json"  // file the simulation state is saved to and loaded from
+	crashSavePath   string = "crash.json" // file the simulation state is saved to if the game fails
+
+	// states saved to files with this extension are written in the binary gob format instead of json,
+	// which is a lot smaller and faster for systems with many bodies
+	binarySaveExtension string = ".gob"
+)
+
+// state of a spaceobject in a save file, the images are recreated on load
+type savedSpaceObject struct {
+	Name            string     `json:"name"`
+	Mass            float64    `json:"mass"`
+	Radius          float64    `json:"radius"`
+	Position        Vector     `json:"position"`
+	Velocity        Vector     `json:"velocity"`
+	Color           color.RGBA `json:"color"`
+	IsSpacecraft    bool       `json:"isSpacecraft"`
+	ShowTrail       bool       `json:"showTrail"`
+	Heading         float64    `json:"heading"`
+	Thrust          float64    `json:"thrust"`
+	FuelMass        float64    `json:"fuelMass"`
+	ExhaustVelocity float64    `json:"exhaustVelocity"`
+	Mode            string     `json:"mode,omitempty"`        // integration mode, see rails.go
+	RailsParent     string     `json:"railsParent,omitempty"` // parent of an object on rails
+}
+
+// spring between two spaceobjects in a save file
+type savedSpring struct {
+	A          int     `json:"a"`
+	B          int     `json:"b"`
+	RestLength float64 `json:"restLength"`
+	Stiffness  float64 `json:"stiffness"`
+}
+
+// state of the simulation in a save file
+type savedState struct {
+	Time         float64            `json:"time"`
+	SpaceObjects []savedSpaceObject `json:"spaceObjects"`
+	Springs      []savedSpring      `json:"springs"`
+	Focus        int                `json:"focus"`
+	Selected     int                `json:"selected"`
+	CameraOffset Vector             `json:"cameraOffset"`
+	CameraZoom   float64            `json:"cameraZoom"`
+
+	// state of the random number generator, so random draws after loading continue identically
+	RNG []byte `json:"rng"`
+}
+
+// writes the current simulation state to the given path
+func (g *Game) SaveState(path string) error {
+	rngState, err := g.rngSource.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	state := savedState{
+		Time:         g.time,
+		SpaceObjects: make([]savedSpaceObject, len(g.spaceObjects)),
+		Focus:        g.focus,
+		Selected:     g.selected,
+		CameraOffset: g.camera.offset,
+		CameraZoom:   g.camera.zoom,
+		RNG:          rngState,
+	}
+	for _, spring := range g.springs {
+		state.Springs = append(state.Springs, savedSpring{A: spring.a, B: spring.b, RestLength: spring.restLength, Stiffness: spring.stiffness})
+	}
+	for i, so := range g.spaceObjects {
+		state.SpaceObjects[i] = savedSpaceObject{
+			Name:            so.name,
+			Mass:            so.mass,
+			Radius:          so.radius,
+			Position:        so.position,
+			Velocity:        so.velocity,
+			Color:           color.RGBAModel.Convert(so.color).(color.RGBA),
+			IsSpacecraft:    so.isSpacecraft,
+			ShowTrail:       so.showTrail,
+			Heading:         so.heading,
+			Thrust:          so.thrust,
+			FuelMass:        so.fuelMass,
+			ExhaustVelocity: so.exhaustVelocity,
+			Mode:            so.mode.String(),
+		}
+		if so.rails != nil {
+			state.SpaceObjects[i].RailsParent = so.rails.parent
+		}
+	}
+
+	data, err := encodeState(path, state)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+
+	// the recording of how the state came about is kept next to it for the fast-forward after loading
+	if len(g.recording.Frames) > 0 {
+		return g.recording.ExportJSON(companionRecordingPath(path))
+	}
+	return nil
+}
+
+// returns the path of the recording saved along with the state at the given path
+func companionRecordingPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".recording.json"
+}
+
+// encodes the state in the format belonging to the extension of the path
+func encodeState(path string, state savedState) ([]byte, error) {
+	if filepath.Ext(path) != binarySaveExtension {
+		return json.MarshalIndent(state, "", "  ")
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(state); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodes a state in the format belonging to the extension of the path
+func decodeState(path string, data []byte) (savedState, error) {
+	var state savedState
+	if filepath.Ext(path) != binarySaveExtension {
+		err := json.Unmarshal(data, &state)
+		return state, err
+	}
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&state)
+	return state, err
+}
+
+// replaces the current simulation state with the one saved at the given path
+func (g *Game) LoadState(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	state, err := decodeState(path, data)
+	if err != nil {
+		return err
+	}
+
+	if len(state.SpaceObjects) > g.maxBodies {
+		return fmt.Errorf("%s has %d bodies, more than the maximum of %d (see --max-bodies)", path, len(state.SpaceObjects), g.maxBodies)
+	}
+
+	rngSource := &rand.PCG{}
+	if err := rngSource.UnmarshalBinary(state.RNG); err != nil {
+		return err
+	}
+
+	spaceObjects := make([]*SpaceObject, len(state.SpaceObjects))
+	for i, saved := range state.SpaceObjects {
+		mode, err := parseIntegrationMode(saved.Mode)
+		if err != nil {
+			return fmt.Errorf("spaceobject %q: %w", saved.Name, err)
+		}
+		spaceObjects[i] = &SpaceObject{
+			name:            saved.Name,
+			mass:            saved.Mass,
+			radius:          saved.Radius,
+			position:        saved.Position,
+			velocity:        saved.Velocity,
+			img:             createEmptyColoredImage(2, 2, saved.Color),
+			color:           saved.Color,
+			isSpacecraft:    saved.IsSpacecraft,
+			showTrail:       saved.ShowTrail,
+			heading:         saved.Heading,
+			thrust:          saved.Thrust,
+			fuelMass:        saved.FuelMass,
+			exhaustVelocity: saved.ExhaustVelocity,
+			mode:            mode,
+		}
+		// the orbit on rails is computed again from the saved state
+		if mode == modeRails {
+			spaceObjects[i].rails = &railsOrbit{parent: saved.RailsParent}
+		}
+	}
+
+	springs := make([]Spring, len(state.Springs))
+	for i, saved := range state.Springs {
+		if saved.A < 0 || saved.A >= len(spaceObjects) || saved.B < 0 || saved.B >= len(spaceObjects) {
+			return fmt.Errorf("spring %d connects spaceobjects %d and %d, but there are only %d", i, saved.A, saved.B, len(spaceObjects))
+		}
+		springs[i] = Spring{a: saved.A, b: saved.B, restLength: saved.RestLength, stiffness: saved.Stiffness}
+	}
+
+	// a running replay would put its live state back over the loaded one when it ends
+	g.replay = nil
+	g.time = state.Time
+	g.spaceObjects = spaceObjects
+	g.springs = springs
+	g.focus = state.Focus
+	g.selected = state.Selected
+	g.camera.offset = state.CameraOffset
+	g.camera.zoom = state.CameraZoom
+	g.rngSource = rngSource
+	g.rng = rand.New(rngSource)
+
+	// the loaded state has nothing to do with the previous one
+	g.resetRunState()
+
+	// optionally show how the state came about by replaying its recording up to the saved time
+	if g.fastForwardLoads {
+		recording, err := LoadRecording(companionRecordingPath(path))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		g.startFastForward(recording, state.Time)
+	}
+	return nil
+}
+
+// forgets everything the previous run planned or measured, the trackers keep pointers to spaceobjects that are gone
+func (g *Game) resetRunState() {
+	g.baseline.set = false
+	g.initialEnergySet = false
+	g.nodes = nil
+	g.dragging = nil
+	g.transfer = transferPlan{}
+	g.intercept = interceptPlan{}
+	g.apsis = apsisTracker{}
+	g.revolution = revolutionTracker{}
+	g.hodograph = nil
+	g.events = eventTracker{}
+	g.decay = decayTracker{energy: math.NaN(), orbitsLeft: math.NaN()}
+	g.autoPause = AutoPause{onCrash: g.autoPause.onCrash, onEscape: g.autoPause.onEscape}
+	g.energyHistory.clear()
+	g.substepDots = nil
+}
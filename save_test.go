@@ -0,0 +1,233 @@
+package main
+
+import (
+	"image/color"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	for _, name := range []string{"save.json", "save.gob"} {
+		t.Run(name, func(t *testing.T) {
+			original := NewGame()
+			for range 10 {
+				original.Step()
+			}
+			original.rng.Float64()
+			original.focus = 1
+			original.selected = 2
+
+			path := filepath.Join(t.TempDir(), name)
+			if err := original.SaveState(path); err != nil {
+				t.Fatal(err)
+			}
+
+			loaded := newGame()
+			loaded.rng.Float64()
+			if err := loaded.LoadState(path); err != nil {
+				t.Fatal(err)
+			}
+
+			if loaded.time != original.time || loaded.focus != original.focus || loaded.selected != original.selected {
+				t.Errorf("loaded time %v, focus %d, selected %d, want %v, %d, %d",
+					loaded.time, loaded.focus, loaded.selected, original.time, original.focus, original.selected)
+			}
+			if len(loaded.spaceObjects) != len(original.spaceObjects) {
+				t.Fatalf("loaded %d spaceobjects, want %d", len(loaded.spaceObjects), len(original.spaceObjects))
+			}
+			for i, so := range loaded.spaceObjects {
+				want := original.spaceObjects[i]
+				if so.name != want.name || so.mass != want.mass || so.position != want.position || so.velocity != want.velocity {
+					t.Errorf("spaceobject %d is %q %v kg at %v moving %v, want %q %v kg at %v moving %v",
+						i, so.name, so.mass, so.position, so.velocity, want.name, want.mass, want.position, want.velocity)
+				}
+			}
+			if got, want := loaded.rng.Float64(), original.rng.Float64(); got != want {
+				t.Errorf("random draw after loading is %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadStateResetsThePreviousRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	if err := NewGame().SaveState(path); err != nil {
+		t.Fatal(err)
+	}
+
+	g := circularOrbitGame(testOrbitRadius)
+	g.autoPause.onCrash = true
+	for range 10 {
+		g.Step()
+	}
+	craft := g.spaceObjects[1]
+	g.nodes = []*ManeuverNode{{time: g.time, prograde: 10}}
+	g.transfer = transferPlan{target: 2 * testOrbitRadius}
+	g.intercept = interceptPlan{body: g.spaceObjects[0], target: craft}
+	g.apsis = apsisTracker{body: g.spaceObjects[0], angles: map[*SpaceObject]float64{g.spaceObjects[0]: 1}}
+	g.revolution = revolutionTracker{body: g.spaceObjects[0]}
+	g.hodograph = []Vector{craft.velocity}
+	g.events = eventTracker{dominant: g.spaceObjects[0]}
+	g.decay.body = g.spaceObjects[0]
+	g.autoPause.banner = "crashed"
+	g.energyHistory.push(1)
+	g.startReplay(&Recording{Frames: []RecordedFrame{{Time: 0}}})
+
+	if err := g.LoadState(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if g.replay != nil {
+		t.Error("replay is still running after loading")
+	}
+	if len(g.nodes) != 0 || g.transfer != (transferPlan{}) || g.intercept.target != nil {
+		t.Error("maneuvers of the previous run are still planned")
+	}
+	if g.apsis.body != nil || g.apsis.angles != nil || g.revolution.body != nil || g.hodograph != nil || g.events.dominant != nil || g.decay.body != nil {
+		t.Error("trackers still refer to spaceobjects of the previous run")
+	}
+	if g.autoPause.banner != "" || !g.autoPause.onCrash {
+		t.Errorf("auto pause is %+v, want no banner and onCrash kept", g.autoPause)
+	}
+	if len(g.energyHistory.ordered()) != 0 {
+		t.Error("energy plot still shows the previous run")
+	}
+}
+
+// returns a game with the given number of random bodies, allowed to hold all of them
+func manyBodiesGame(n int) *Game {
+	g := newGame()
+	g.maxBodies = n
+	g.spaceObjects = randomBodies(n, 1e12)
+	for _, so := range g.spaceObjects {
+		so.color = color.RGBA{255, 128, 0, 255}
+	}
+	return g
+}
+
+func TestBinarySaveOfManyBodies(t *testing.T) {
+	original := manyBodiesGame(10000)
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "save.json")
+	gobPath := filepath.Join(dir, "save"+binarySaveExtension)
+	for _, path := range []string{jsonPath, gobPath} {
+		if err := original.SaveState(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loaded := newGame()
+	loaded.maxBodies = 10000
+	if err := loaded.LoadState(gobPath); err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.spaceObjects) != len(original.spaceObjects) {
+		t.Fatalf("loaded %d bodies, want %d", len(loaded.spaceObjects), len(original.spaceObjects))
+	}
+	for i, so := range loaded.spaceObjects {
+		want := original.spaceObjects[i]
+		if so.mass != want.mass || so.radius != want.radius || so.position != want.position || so.velocity != want.velocity || so.color != want.color {
+			t.Fatalf("body %d is %v kg at %v moving %v, want %v kg at %v moving %v", i, so.mass, so.position, so.velocity, want.mass, want.position, want.velocity)
+		}
+	}
+
+	jsonInfo, err := os.Stat(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gobInfo, err := os.Stat(gobPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gobInfo.Size() >= jsonInfo.Size()/2 {
+		t.Errorf("binary save is %d bytes, want less than half of the %d bytes of json", gobInfo.Size(), jsonInfo.Size())
+	}
+}
+
+func BenchmarkSaveState(b *testing.B) {
+	for _, extension := range []string{".json", binarySaveExtension} {
+		b.Run(extension, func(b *testing.B) {
+			g := manyBodiesGame(10000)
+			path := filepath.Join(b.TempDir(), "save"+extension)
+			b.ResetTimer()
+			for range b.N {
+				if err := g.SaveState(path); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+			info, err := os.Stat(path)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportMetric(float64(info.Size()), "bytes/save")
+		})
+	}
+}
+
+func BenchmarkLoadState(b *testing.B) {
+	for _, extension := range []string{".json", binarySaveExtension} {
+		b.Run(extension, func(b *testing.B) {
+			path := filepath.Join(b.TempDir(), "save"+extension)
+			if err := manyBodiesGame(10000).SaveState(path); err != nil {
+				b.Fatal(err)
+			}
+			g := newGame()
+			g.maxBodies = 10000
+			b.ResetTimer()
+			for range b.N {
+				if err := g.LoadState(path); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestFastForwardAfterLoading(t *testing.T) {
+	original := NewGame()
+	original.recording.Start()
+	for range 100 {
+		original.Step()
+	}
+	path := filepath.Join(t.TempDir(), "save.json")
+	if err := original.SaveState(path); err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGame()
+	g.fastForwardLoads = true
+	if err := g.LoadState(path); err != nil {
+		t.Fatal(err)
+	}
+	if g.replay == nil {
+		t.Fatal("loading did not start the fast-forward")
+	}
+
+	// the fast-forward plays the recording up to the saved time and then hands back the loaded state
+	updates := 0
+	reached := 0.0
+	for g.replay != nil {
+		if updates++; updates > 100 {
+			t.Fatal("the fast-forward did not end")
+		}
+		g.updateReplay()
+		reached = max(reached, g.time)
+	}
+	if reached != original.time {
+		t.Errorf("fast-forward reached %v s, want the saved time %v", reached, original.time)
+	}
+	if want := int(math.Ceil(100 / fastForwardSpeed)); updates > want {
+		t.Errorf("fast-forward took %d updates, want at most %d", updates, want)
+	}
+	if g.time != original.time {
+		t.Errorf("time after the fast-forward is %v, want %v", g.time, original.time)
+	}
+	for i, so := range g.spaceObjects {
+		if want := original.spaceObjects[i]; so.position != want.position || so.velocity != want.velocity {
+			t.Errorf("%q is at %v after the fast-forward, want the saved %v", so.name, so.position, want.position)
+		}
+	}
+}
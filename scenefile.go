@@ -0,0 +1,267 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"math"
+	"os"
+	"strings"
+)
+
+// body of a scene file
+// a body with a parent is placed relative to it, either by orbital elements or by position and velocity
+// the velocity is given either as x and y components or as a speed and a heading
+// the mass is given either directly or as the gravitational parameter mu = G*M
+type sceneBody struct {
+	Name       string      `json:"name"`
+	Mass       float64     `json:"mass,omitempty"`   // kg
+	Mu         float64     `json:"mu,omitempty"`     // m^3/s^2, replaces the mass
+	Radius     float64     `json:"radius,omitempty"` // m, derived from the mass if omitted
+	Color      *color.RGBA `json:"color,omitempty"`
+	Spacecraft bool        `json:"spacecraft,omitempty"`
+	Parent     string      `json:"parent,omitempty"`   // name of the body the position, velocity and orbit are relative to
+	Position   Vector      `json:"position"`           // m
+	Velocity   *Vector     `json:"velocity,omitempty"` // m/s
+	Speed      *float64    `json:"speed,omitempty"`    // m/s, replaces the velocity together with the heading
+	Heading    *float64    `json:"heading,omitempty"`  // degrees counterclockwise from the x axis
+	Mode       string      `json:"mode,omitempty"`     // nbody (the default), rails or fixed; rails needs a parent
+	Orbit      *sceneOrbit `json:"orbit,omitempty"`    // replaces position and velocity, needs a parent
+}
+
+// orbital elements of a body in a scene file, angles in degrees
+type sceneOrbit struct {
+	SemiMajorAxis       float64 `json:"semiMajorAxis"` // m
+	Eccentricity        float64 `json:"eccentricity"`
+	ArgumentOfPeriapsis float64 `json:"argumentOfPeriapsis"`
+	TrueAnomaly         float64 `json:"trueAnomaly"`
+	Clockwise           bool    `json:"clockwise,omitempty"`
+}
+
+// camera a scene file opens with, so a shared scene looks the same for everyone
+type sceneCamera struct {
+	Offset Vector  `json:"offset"`           // m, relative to the followed body
+	Zoom   float64 `json:"zoom"`             // magnification on top of XScale and YScale
+	Follow string  `json:"follow,omitempty"` // name of the body the view is centered on, the origin if omitted
+}
+
+// content of a scene file
+// without a camera the scene opens zoomed to fit all bodies
+type sceneFile struct {
+	Bodies []sceneBody  `json:"bodies"`
+	Camera *sceneCamera `json:"camera,omitempty"`
+}
+
+// returns the absolute positions and velocities of all bodies, indexed like the bodies
+// parents are resolved before their children, a cycle of parents is an error
+func resolveSceneBodies(bodies []sceneBody, config SimConfig) ([]Vector, []Vector, error) {
+	index := map[string]int{}
+	for i, body := range bodies {
+		if _, ok := index[body.Name]; ok {
+			return nil, nil, fmt.Errorf("body %q is defined twice", body.Name)
+		}
+		index[body.Name] = i
+	}
+
+	positions := make([]Vector, len(bodies))
+	velocities := make([]Vector, len(bodies))
+	const (
+		unresolved = iota
+		resolving
+		resolved
+	)
+	state := make([]int, len(bodies))
+
+	var resolve func(i int, chain []string) error
+	resolve = func(i int, chain []string) error {
+		body := bodies[i]
+		chain = append(chain, body.Name)
+		switch state[i] {
+		case resolved:
+			return nil
+		case resolving:
+			return fmt.Errorf("cycle in the parents: %s", strings.Join(chain, " -> "))
+		}
+		state[i] = resolving
+
+		var parentPosition, parentVelocity Vector
+		parentMass := 0.0
+		if body.Parent != "" {
+			parent, ok := index[body.Parent]
+			if !ok {
+				return fmt.Errorf("parent %q of %q does not exist", body.Parent, body.Name)
+			}
+			if err := resolve(parent, chain); err != nil {
+				return err
+			}
+			parentPosition, parentVelocity = positions[parent], velocities[parent]
+			parentMass = bodies[parent].Mass
+		}
+
+		velocity, err := body.initialVelocity()
+		if err != nil {
+			return err
+		}
+		position := body.Position
+		if orbit := body.Orbit; orbit != nil {
+			if body.Parent == "" {
+				return fmt.Errorf("%q has an orbit but no parent to orbit", body.Name)
+			}
+			if orbit.SemiMajorAxis <= 0 || orbit.Eccentricity < 0 || orbit.Eccentricity >= 1 {
+				return fmt.Errorf("orbit of %q needs a positive semi-major axis and an eccentricity in [0, 1)", body.Name)
+			}
+			position, velocity = stateFromElements(
+				orbit.SemiMajorAxis,
+				orbit.Eccentricity,
+				orbit.ArgumentOfPeriapsis*math.Pi/180,
+				orbit.TrueAnomaly*math.Pi/180,
+				config.gravitationalConstant()*parentMass,
+				orbit.Clockwise,
+			)
+		}
+
+		positions[i] = parentPosition.Translate(position.X, position.Y)
+		velocities[i] = parentVelocity.Translate(velocity.X, velocity.Y)
+		state[i] = resolved
+		return nil
+	}
+
+	for i := range bodies {
+		if err := resolve(i, nil); err != nil {
+			return nil, nil, err
+		}
+	}
+	return positions, velocities, nil
+}
+
+// returns the velocity of the body relative to its parent from either of the two forms
+// giving both forms, or only one of speed and heading, is an error; giving neither means at rest
+func (body sceneBody) initialVelocity() (Vector, error) {
+	polar := body.Speed != nil || body.Heading != nil
+	switch {
+	case polar && body.Velocity != nil:
+		return Vector{}, fmt.Errorf("%q has a velocity and a speed or heading, give only one form", body.Name)
+	case polar && (body.Speed == nil || body.Heading == nil):
+		return Vector{}, fmt.Errorf("%q needs both a speed and a heading", body.Name)
+	case polar:
+		return VectorFromPolar(*body.Speed, *body.Heading*math.Pi/180), nil
+	case body.Velocity != nil:
+		return *body.Velocity, nil
+	}
+	return Vector{}, nil
+}
+
+// sets the mass of the body from its gravitational parameter if it has one
+// giving both or neither of mass and mu is an error, as is a value that is not positive
+func (body *sceneBody) resolveMass(config SimConfig) error {
+	switch {
+	case body.Mass != 0 && body.Mu != 0:
+		return fmt.Errorf("%q has a mass and a mu, give only one", body.Name)
+	case body.Mu != 0:
+		if body.Mu < 0 {
+			return fmt.Errorf("mu of %q must be positive, got %g", body.Name, body.Mu)
+		}
+		body.Mass = body.Mu / config.gravitationalConstant()
+	case body.Mass <= 0:
+		return fmt.Errorf("mass of %q must be positive, got %g", body.Name, body.Mass)
+	}
+	return nil
+}
+
+// reads a scene from a json file and returns it as a scene that can be started like the built-in ones
+func LoadSceneFile(path string) (Scene, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Scene{}, err
+	}
+	var file sceneFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return Scene{}, fmt.Errorf("%s: %w", path, err)
+	}
+	modes := make([]IntegrationMode, len(file.Bodies))
+	for i := range file.Bodies {
+		if err := file.Bodies[i].resolveMass(DefaultSimConfig()); err != nil {
+			return Scene{}, fmt.Errorf("%s: %w", path, err)
+		}
+		body := file.Bodies[i]
+		if modes[i], err = parseIntegrationMode(body.Mode); err != nil {
+			return Scene{}, fmt.Errorf("%s: %q: %w", path, body.Name, err)
+		}
+		if modes[i] == modeRails && body.Parent == "" {
+			return Scene{}, fmt.Errorf("%s: %q is on rails but has no parent to orbit", path, body.Name)
+		}
+	}
+
+	focus := -1
+	if camera := file.Camera; camera != nil {
+		if camera.Zoom <= 0 {
+			return Scene{}, fmt.Errorf("%s: zoom of the camera must be positive, got %g", path, camera.Zoom)
+		}
+		if focus = sceneBodyIndex(file.Bodies, camera.Follow); camera.Follow != "" && focus < 0 {
+			return Scene{}, fmt.Errorf("%s: camera follows %q, which does not exist", path, camera.Follow)
+		}
+	}
+
+	// the scene is resolved with the default config, setGScale keeps the orbits when the gravity is scaled later
+	positions, velocities, err := resolveSceneBodies(file.Bodies, DefaultSimConfig())
+	if err != nil {
+		return Scene{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	create := func() *Game {
+		game := newGame()
+		for i, body := range file.Bodies {
+			so := newSceneObject(body, positions[i], velocities[i])
+			so.mode = modes[i]
+			if so.mode == modeRails {
+				so.rails = &railsOrbit{parent: body.Parent}
+			}
+			game.spaceObjects = append(game.spaceObjects, so)
+		}
+		if camera := file.Camera; camera != nil {
+			game.camera.offset = camera.Offset
+			game.camera.zoom = camera.Zoom
+			game.focus = focus
+		} else {
+			game.fitPending = true
+		}
+		return game
+	}
+	return Scene{name: path, description: path, create: create}, nil
+}
+
+// returns the index of the body with the given name, -1 if there is none
+func sceneBodyIndex(bodies []sceneBody, name string) int {
+	for i, body := range bodies {
+		if body.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// returns the spaceobject of a body of a scene file at the given absolute position and velocity
+func newSceneObject(body sceneBody, position, velocity Vector) *SpaceObject {
+	var so *SpaceObject
+	if body.Spacecraft {
+		so = newSpacecraft(position, velocity)
+		// the propulsion of the default spacecraft scales with the mass, so a lighter one keeps its share of
+		// propellant and its acceleration instead of carrying more fuel than it weighs
+		scale := body.Mass / so.mass
+		so.fuelMass *= scale
+		so.thrust *= scale
+	} else {
+		so = &SpaceObject{position: position, velocity: velocity, radius: radiusFromMass(body.Mass), color: color.RGBA{200, 200, 200, 255}}
+	}
+
+	so.name = body.Name
+	so.mass = body.Mass
+	if body.Radius > 0 {
+		so.radius = body.Radius
+	}
+	if body.Color != nil {
+		so.color = *body.Color
+	}
+	so.img = createEmptyColoredImage(2, 2, so.color)
+	return so
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveSceneBodiesOfAStarPlanetMoon(t *testing.T) {
+	const (
+		planetMass  = 6e24
+		moonRadius  = 3.84e8
+		planetAngle = 90 // degrees of true anomaly, the planet starts on the y axis
+	)
+	// the moon comes first, so its parent chain has to be resolved before it
+	bodies := []sceneBody{
+		{Name: "moon", Mass: 7e22, Parent: "planet", Orbit: &sceneOrbit{SemiMajorAxis: moonRadius}},
+		{Name: "planet", Mass: planetMass, Parent: "star", Orbit: &sceneOrbit{SemiMajorAxis: testOrbitRadius, TrueAnomaly: planetAngle}},
+		{Name: "star", Mass: testStarMass, Position: Vector{1e9, 0}},
+	}
+	config := DefaultSimConfig()
+	positions, velocities, err := resolveSceneBodies(bodies, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	near := func(got, want Vector, tolerance float64) bool {
+		return math.Sqrt(got.DistanceSquared(want)) <= tolerance
+	}
+	planetSpeed := config.CircularOrbitVelocity(testStarMass, testOrbitRadius)
+	moonSpeed := config.CircularOrbitVelocity(planetMass, moonRadius)
+
+	// the planet is a quarter around the star, moving counterclockwise at the circular speed
+	if want := (Vector{1e9, testOrbitRadius}); !near(positions[1], want, 1e-3) {
+		t.Errorf("planet is at %v, want %v", positions[1], want)
+	}
+	if want := (Vector{-planetSpeed, 0}); !near(velocities[1], want, 1e-9) {
+		t.Errorf("planet moves at %v m/s, want %v", velocities[1], want)
+	}
+
+	// the moon orbits the planet at its own circular speed on top of the planet's velocity
+	if want := positions[1].Translate(moonRadius, 0); !near(positions[0], want, 1e-3) {
+		t.Errorf("moon is at %v, want %v", positions[0], want)
+	}
+	if want := velocities[1].Translate(0, moonSpeed); !near(velocities[0], want, 1e-9) {
+		t.Errorf("moon moves at %v m/s, want %v", velocities[0], want)
+	}
+
+	// relative to the planet the moon stays bound
+	relative := velocities[0].Translate(-velocities[1].X, -velocities[1].Y).Length()
+	if escape := config.EscapeVelocity(planetMass, moonRadius); relative >= escape {
+		t.Errorf("moon moves %v m/s relative to the planet, at or above the escape velocity %v", relative, escape)
+	}
+}
+
+func TestResolveSceneBodiesRejectsBadParents(t *testing.T) {
+	tests := []struct {
+		name    string
+		bodies  []sceneBody
+		wantErr string
+	}{
+		{"cycle", []sceneBody{
+			{Name: "a", Mass: 1, Parent: "b"},
+			{Name: "b", Mass: 1, Parent: "c"},
+			{Name: "c", Mass: 1, Parent: "a"},
+		}, "cycle in the parents: a -> b -> c -> a"},
+		{"own parent", []sceneBody{{Name: "a", Mass: 1, Parent: "a"}}, "cycle"},
+		{"missing parent", []sceneBody{{Name: "a", Mass: 1, Parent: "b"}}, `parent "b" of "a" does not exist`},
+		{"orbit without parent", []sceneBody{{Name: "a", Mass: 1, Orbit: &sceneOrbit{SemiMajorAxis: 1}}}, "no parent"},
+		{"duplicate name", []sceneBody{{Name: "a", Mass: 1}, {Name: "a", Mass: 1}}, "defined twice"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := resolveSceneBodies(test.bodies, DefaultSimConfig())
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("resolveSceneBodies() = %v, want an error containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestSceneVelocityForms(t *testing.T) {
+	tests := []struct {
+		name          string
+		vector, polar string // json of the same velocity in both forms
+		wantX, wantY  float64
+	}{
+		{"down", `"velocity": {"X": 0, "Y": -700}`, `"speed": 700, "heading": 270`, 0, -700},
+		{"diagonal", `"velocity": {"X": 500, "Y": 500}`, `"speed": 707.1067811865476, "heading": 45`, 500, 500},
+		{"backwards", `"velocity": {"X": -3, "Y": 0}`, `"speed": 3, "heading": -180`, -3, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var velocities []Vector
+			for _, form := range []string{test.vector, test.polar} {
+				var body sceneBody
+				if err := json.Unmarshal([]byte(`{"name": "craft", "mass": 1, `+form+`}`), &body); err != nil {
+					t.Fatal(err)
+				}
+				_, v, err := resolveSceneBodies([]sceneBody{body}, DefaultSimConfig())
+				if err != nil {
+					t.Fatal(err)
+				}
+				velocities = append(velocities, v[0])
+			}
+			want := Vector{test.wantX, test.wantY}
+			for i, v := range velocities {
+				if math.Sqrt(v.DistanceSquared(want)) > 1e-9 {
+					t.Errorf("velocity of form %d is %v m/s, want %v", i, v, want)
+				}
+			}
+		})
+	}
+
+	invalid := []struct {
+		name, form, wantErr string
+	}{
+		{"both forms", `"velocity": {"X": 0, "Y": -700}, "speed": 700, "heading": 270`, "only one form"},
+		{"speed only", `"speed": 700`, "both a speed and a heading"},
+		{"heading only", `"heading": 270`, "both a speed and a heading"},
+	}
+	for _, test := range invalid {
+		t.Run(test.name, func(t *testing.T) {
+			var body sceneBody
+			if err := json.Unmarshal([]byte(`{"name": "craft", "mass": 1, `+test.form+`}`), &body); err != nil {
+				t.Fatal(err)
+			}
+			_, _, err := resolveSceneBodies([]sceneBody{body}, DefaultSimConfig())
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("resolveSceneBodies() = %v, want an error containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+// writes the scene file into a temporary directory and loads it
+func loadTestScene(t *testing.T, content string) (Scene, error) {
+	path := filepath.Join(t.TempDir(), "scene.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return LoadSceneFile(path)
+}
+
+func TestSceneCamera(t *testing.T) {
+	const bodies = `"bodies": [
+		{"name": "star", "mass": 2e30},
+		{"name": "planet", "mass": 6e24, "parent": "star", "orbit": {"semiMajorAxis": 1.5e11}}
+	]`
+
+	scene, err := loadTestScene(t, `{`+bodies+`, "camera": {"offset": {"X": 1.25e9, "Y": -3e8}, "zoom": 0.37, "follow": "planet"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := scene.create()
+	if g.camera.offset != (Vector{1.25e9, -3e8}) || g.camera.zoom != 0.37 || g.focus != 1 || g.fitPending {
+		t.Errorf("camera opens at %v with zoom %v following %d (fit %v), want (1.25e9, -3e8) with zoom 0.37 following the planet",
+			g.camera.offset, g.camera.zoom, g.focus, g.fitPending)
+	}
+
+	// without a camera the scene is fitted once the window size is known
+	scene, err = loadTestScene(t, `{`+bodies+`}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g := scene.create(); !g.fitPending {
+		t.Error("scene without a camera is not zoomed to fit")
+	}
+
+	for _, camera := range []string{`{"zoom": 0}`, `{"zoom": 1, "follow": "moon"}`} {
+		if _, err := loadTestScene(t, `{`+bodies+`, "camera": `+camera+`}`); err == nil {
+			t.Errorf("camera %s was accepted", camera)
+		}
+	}
+}
+
+func TestSceneBodyGivenByMu(t *testing.T) {
+	// the sun by its gravitational parameter, which is known far better than its mass
+	const sunMu = 1.32712440018e20
+	sunMass := sunMu / DefaultSimConfig().gravitationalConstant()
+	scene := func(star string) string {
+		return `{"bodies": [
+			{"name": "star", ` + star + `},
+			{"name": "planet", "mass": 6e24, "parent": "star", "orbit": {"semiMajorAxis": 1.5e11, "eccentricity": 0.2}},
+			{"name": "craft", "mass": 1, "parent": "star", "spacecraft": true, "orbit": {"semiMajorAxis": 4e10, "trueAnomaly": 30}}
+		]}`
+	}
+
+	var games []*Game
+	for _, star := range []string{fmt.Sprintf(`"mass": %v`, sunMass), fmt.Sprintf(`"mu": %v`, sunMu)} {
+		loaded, err := loadTestScene(t, scene(star))
+		if err != nil {
+			t.Fatal(err)
+		}
+		games = append(games, loaded.create())
+	}
+	byMass, byMu := games[0], games[1]
+	if got := byMu.spaceObjects[0].mass; math.Abs(got/sunMass-1) > 1e-15 {
+		t.Errorf("star given by mu has %v kg, want %v", got, sunMass)
+	}
+	for i, so := range byMu.spaceObjects {
+		want := byMass.spaceObjects[i].velocity
+		if math.Sqrt(so.velocity.DistanceSquared(want)) > 1e-9*want.Length() {
+			t.Errorf("%q moves at %v m/s around the star given by mu, want %v as with its mass", so.name, so.velocity, want)
+		}
+	}
+
+	for _, star := range []string{`"mass": 2e30, "mu": 1.3e20`, `"radius": 7e8`, `"mu": -1.3e20`} {
+		if _, err := loadTestScene(t, scene(star)); err == nil {
+			t.Errorf("star with %s was accepted", star)
+		}
+	}
+}
+
+func TestLoadedLightSpacecraftKeepsAPositiveMass(t *testing.T) {
+	scene, err := loadTestScene(t, `{"bodies": [{"name": "craft", "mass": 1, "spacecraft": true}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	craft := scene.create().spaceObjects[0]
+	if craft.fuelMass <= 0 || craft.fuelMass >= craft.mass {
+		t.Fatalf("spacecraft of %v kg carries %v kg fuel, want less than its mass", craft.mass, craft.fuelMass)
+	}
+	// it accelerates like the default spacecraft
+	standard := newSpacecraft(Vector{}, Vector{})
+	if got, want := craft.thrust/craft.mass, standard.thrust/standard.mass; math.Abs(got/want-1) > 1e-12 {
+		t.Errorf("thrust accelerates the spacecraft at %v m/s², want %v like the default one", got, want)
+	}
+
+	dryMass := craft.mass - craft.fuelMass
+	craft.thrusting = true
+	for craft.ApplyThrust() {
+		if craft.mass <= 0 {
+			t.Fatalf("spacecraft has %v kg after a thrust step", craft.mass)
+		}
+	}
+	if math.Abs(craft.mass-dryMass) > 1e-9 || craft.fuelMass != 0 {
+		t.Errorf("spacecraft has %v kg with %v kg fuel after burning it all, want its dry mass of %v kg", craft.mass, craft.fuelMass, dryMass)
+	}
+}
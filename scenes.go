@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// a built-in scenario that can be picked from the menu
+type Scene struct {
+	name        string // name used by the --scene flag
+	description string // name shown in the menu
+	create      func() *Game
+}
+
+var scenes = []Scene{
+	{name: "flyby", description: "Single flyby", create: NewGame},
+	{name: "binary", description: "Binary star", create: NewBinaryStarGame},
+	{name: "solar", description: "Solar system", create: NewSolarSystemGame},
+	{name: "tether", description: "Tethered pair", create: NewTetherGame},
+	{name: "slingshot", description: "Gravity assist tutorial", create: NewSlingshotGame},
+}
+
+// returns the scene with the given name
+func sceneByName(name string) (Scene, error) {
+	for _, scene := range scenes {
+		if scene.name == name {
+			return scene, nil
+		}
+	}
+	return Scene{}, fmt.Errorf("unknown scene %q", name)
+}
+
+// returns a spaceobject on a circular orbit around a body at rest in the origin
+// the orbit is counterclockwise and starts on the positive x axis
+func newOrbitingObject(config SimConfig, name string, mass, radius, orbitRadius, centralMass float64, c color.Color) *SpaceObject {
+	return &SpaceObject{
+		name:     name,
+		mass:     mass,
+		radius:   radius,
+		position: Vector{orbitRadius, 0},
+		velocity: Vector{0, -config.CircularOrbitVelocity(centralMass, orbitRadius)},
+		img:      createEmptyColoredImage(2, 2, c),
+		color:    c,
+	}
+}
+
+// returns two equal stars orbiting their common barycenter and a spacecraft circling both
+func NewBinaryStarGame() *Game {
+	game := newGame()
+
+	starMass := 1e24
+	separation := 3e9
+
+	// each star circles the barycenter at half the separation, pulled by the other star at the full separation:
+	// v^2 / (d/2) = G*M / d^2 -> v = sqrt(G*M / (2*d))
+	starVelocity := game.config.CircularOrbitVelocity(starMass, 2*separation)
+
+	game.spaceObjects = []*SpaceObject{
+		{
+			name:     "Star A",
+			mass:     starMass,
+			radius:   7e6,
+			position: Vector{-separation / 2, 0},
+			velocity: Vector{0, starVelocity},
+			img:      createEmptyColoredImage(2, 2, color.RGBA{255, 200, 0, 255}),
+			color:    color.RGBA{255, 200, 0, 255},
+		},
+		{
+			name:     "Star B",
+			mass:     starMass,
+			radius:   7e6,
+			position: Vector{separation / 2, 0},
+			velocity: Vector{0, -starVelocity},
+			img:      createEmptyColoredImage(2, 2, color.RGBA{255, 100, 0, 255}),
+			color:    color.RGBA{255, 100, 0, 255},
+		},
+	}
+
+	// outside of the stars orbit, both stars pull roughly like a single one with both masses
+	craftDistance := 3.4e9
+	craft := newSpacecraft(Vector{0, craftDistance}, Vector{game.config.CircularOrbitVelocity(2*starMass, craftDistance), 0})
+	game.spaceObjects = append(game.spaceObjects, craft)
+
+	return game
+}
+
+// returns a star with four planets on circular orbits and a spacecraft
+func NewSolarSystemGame() *Game {
+	game := newGame()
+
+	starMass := 6e23
+	game.spaceObjects = []*SpaceObject{
+		{
+			name:     "Sun",
+			mass:     starMass,
+			radius:   7e6,
+			position: Vector{0, 0},
+			velocity: Vector{0, 0},
+			img:      createEmptyColoredImage(2, 2, color.RGBA{255, 220, 0, 255}),
+			color:    color.RGBA{255, 220, 0, 255},
+		},
+		newOrbitingObject(game.config, "Mercury", 3e20, 2.4e6, 1.5e9, starMass, color.RGBA{160, 160, 160, 255}),
+		newOrbitingObject(game.config, "Venus", 5e21, 6e6, 2.5e9, starMass, color.RGBA{230, 180, 80, 255}),
+		newOrbitingObject(game.config, "Earth", 6e21, 6.4e6, 3.5e9, starMass, color.RGBA{0, 120, 255, 255}),
+		newOrbitingObject(game.config, "Mars", 6e20, 3.4e6, 5e9, starMass, color.RGBA{255, 60, 0, 255}),
+	}
+
+	craftDistance := 4.2e9
+	craft := newSpacecraft(Vector{-craftDistance, 0}, Vector{0, game.config.CircularOrbitVelocity(starMass, craftDistance)})
+	game.spaceObjects = append(game.spaceObjects, craft)
+
+	return game
+}
+
+// returns two bodies connected by a spring orbiting a planet together with a spacecraft
+func NewTetherGame() *Game {
+	game := newGame()
+
+	planetMass := 5.9722e24
+	orbitRadius := 3e9
+	restLength := 5e8
+
+	// both bodies start at the same speed, so the spring swings and stretches as the inner one orbits faster
+	velocity := game.config.CircularOrbitVelocity(planetMass, orbitRadius)
+
+	game.spaceObjects = []*SpaceObject{
+		{
+			name:     "Planet",
+			mass:     planetMass,
+			radius:   6.371e6,
+			position: Vector{0, 0},
+			velocity: Vector{0, 0},
+			img:      createEmptyColoredImage(2, 2, color.RGBA{255, 0, 0, 255}),
+			color:    color.RGBA{255, 0, 0, 255},
+		},
+		{
+			name:     "Anchor",
+			mass:     1e22,
+			radius:   1e6,
+			position: Vector{orbitRadius, 0},
+			velocity: Vector{0, -velocity},
+			img:      createEmptyColoredImage(2, 2, color.RGBA{0, 255, 0, 255}),
+			color:    color.RGBA{0, 255, 0, 255},
+		},
+		{
+			name:     "Weight",
+			mass:     1e22,
+			radius:   1e6,
+			position: Vector{orbitRadius + restLength, 0},
+			velocity: Vector{0, -velocity},
+			img:      createEmptyColoredImage(2, 2, color.RGBA{0, 255, 255, 255}),
+			color:    color.RGBA{0, 255, 255, 255},
+		},
+		newSpacecraft(Vector{-5e9, 1e9}, Vector{-10, 150}),
+	}
+
+	game.springs = []Spring{{a: 1, b: 2, restLength: restLength, stiffness: 8e7}}
+
+	return game
+}
+
+// returns a gravity assist: a spacecraft on a bound orbit passes behind a giant planet and leaves the star
+// annotations explain the phases of the flyby as they happen
+func NewSlingshotGame() *Game {
+	game := newGame()
+
+	starMass := 2e26
+	planetOrbit := 4e9
+	planetSpeed := game.config.CircularOrbitVelocity(starMass, planetOrbit)
+
+	// the planet starts at this angle so it arrives just ahead of the spacecraft, which then passes behind it
+	planetAngle := -0.505
+	direction := Vector{math.Cos(planetAngle), math.Sin(planetAngle)}
+
+	game.spaceObjects = []*SpaceObject{
+		{
+			name:     "Sun",
+			mass:     starMass,
+			radius:   7e8,
+			position: Vector{0, 0},
+			velocity: Vector{0, 0},
+			img:      createEmptyColoredImage(2, 2, color.RGBA{255, 220, 0, 255}),
+			color:    color.RGBA{255, 220, 0, 255},
+		},
+		{
+			name:     "Jupiter",
+			mass:     2e24,
+			radius:   7e7,
+			position: direction.Scale(planetOrbit, planetOrbit),
+			velocity: Vector{-direction.Y * planetSpeed, direction.X * planetSpeed},
+			img:      createEmptyColoredImage(2, 2, color.RGBA{230, 160, 100, 255}),
+			color:    color.RGBA{230, 160, 100, 255},
+		},
+		newSpacecraft(Vector{0, -2e9}, Vector{3300, 0}),
+	}
+
+	game.addAnnotation(Annotation{text: "The spacecraft is bound to the Sun and climbs towards Jupiter's orbit", trigger: triggerTime})
+	game.addAnnotation(Annotation{text: "Approaching Jupiter: its pull is now stronger than the Sun's", trigger: triggerSOIEntry, body: "Jupiter"})
+	game.addAnnotation(Annotation{text: "Closest approach: passing behind Jupiter, the spacecraft gains speed", trigger: triggerPeriapsis, body: "Jupiter"})
+	game.addAnnotation(Annotation{text: "Departing faster: the spacecraft now has enough energy to escape the Sun", trigger: triggerSOIExit, body: "Jupiter"})
+	return game
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	turnRate        float64 = math.Pi / 60 // rotation of the spacecraft heading per frame in rad
+	standardGravity float64 = 9.80665      // acceleration of 1 g in m/s^2
+	minLockSpeed    float64 = 1e-3         // slowest relative speed in m/s the prograde lock still follows
+
+	defaultStageMass  float64 = 1e22 // mass in kg a staging drops
+	defaultMinDryMass float64 = 1e22 // mass in kg the spacecraft keeps at least after staging
+)
+
+// returns the magnitude of the acceleration gravity (and springs) put on the spacecraft in m/s^2
+// uses the same net force as the integration step
+// returns false if there is no spacecraft
+func (g *Game) SpacecraftAcceleration() (float64, bool) {
+	index := g.spacecraftIndex()
+	if index < 0 {
+		return 0, false
+	}
+	force := netForce(g.spaceObjects, g.springs, index, g.config)
+	return force.Length() / g.spaceObjects[index].mass, true
+}
+
+// direction the spacecraft is pointing at as a unit vector
+func (so *SpaceObject) headingVector() Vector {
+	return Vector{math.Cos(so.heading), math.Sin(so.heading)}
+}
+
+// applies the engine thrust along the heading for one time step and burns the needed propellant
+// returns false if the engine did not fire
+func (so *SpaceObject) ApplyThrust() bool {
+	if !so.thrusting || so.thrust <= 0 || so.fuelMass <= 0 || so.exhaustVelocity <= 0 {
+		return false
+	}
+
+	// mass flow of the engine: dm/dt = F / v_e
+	fuelUsed := so.thrust / so.exhaustVelocity * dt
+	burnFraction := 1.0
+
+	// the engine stops once the fuel is exhausted, so the last step only burns partially
+	if fuelUsed > so.fuelMass {
+		burnFraction = so.fuelMass / fuelUsed
+		fuelUsed = so.fuelMass
+	}
+
+	// accelerate using the current total mass, then drop the burned propellant
+	so.UpdateVelocity(so.headingVector().Scale(so.thrust*burnFraction, so.thrust*burnFraction), dt)
+	so.mass -= fuelUsed
+	so.fuelMass -= fuelUsed
+	return true
+}
+
+// drops a stage of the given mass and pushes the rest of the spacecraft forward along its heading by separation m/s
+// the remaining mass has to be at least minDryMass; propellant that no longer fits above the dry mass was in the
+// dropped stage and is lost with it
+func (so *SpaceObject) jettison(stageMass, minDryMass, separation float64) error {
+	if stageMass <= 0 {
+		return fmt.Errorf("stage mass must be positive, got %g kg", stageMass)
+	}
+	if so.mass-stageMass < minDryMass {
+		return fmt.Errorf("cannot stage %g kg, only %g kg are above the dry mass", stageMass, so.mass-minDryMass)
+	}
+
+	so.mass -= stageMass
+	so.fuelMass = math.Min(so.fuelMass, so.mass-minDryMass)
+	push := so.headingVector()
+	so.velocity = so.velocity.Translate(push.X*separation, push.Y*separation)
+	return nil
+}
+
+// drops the configured stage of the spacecraft, the lighter spacecraft accelerates more from the next step on
+func (g *Game) stageSpacecraft() {
+	craft := g.spacecraftIndex()
+	if craft < 0 {
+		return
+	}
+	so := g.spaceObjects[craft]
+	if err := so.jettison(g.stageMass, g.minDryMass, g.separationSpeed); err != nil {
+		g.notify(err.Error())
+		return
+	}
+	g.notify(fmt.Sprintf("Staged, %s has %g kg left", so.name, so.mass))
+	g.baseline.set = false
+}
+
+// sets the orientation the spacecraft is drawn with: along its velocity relative to the given body with the
+// prograde lock, or along its heading without; below minLockSpeed the velocity has no useful direction,
+// so the last orientation is held
+func (so *SpaceObject) orient(body *SpaceObject, progradeLock bool) {
+	if !progradeLock {
+		so.orientation = so.heading
+		return
+	}
+	v := so.velocity
+	if body != nil {
+		v = v.Translate(-body.velocity.X, -body.velocity.Y)
+	}
+	if v.Length() > minLockSpeed {
+		so.orientation = v.Angle()
+	}
+}
+
+// turns the spacecraft and fires its engine according to the pressed keys
+func (so *SpaceObject) handleControls(keys KeyBindings) {
+	if keys.Pressed(actionTurnLeft) {
+		so.heading -= turnRate
+	}
+	if keys.Pressed(actionTurnRight) {
+		so.heading += turnRate
+	}
+	so.thrusting = keys.Pressed(actionThrust)
+}
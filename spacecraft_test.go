@@ -0,0 +1,168 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestThrustFollowsTheRocketEquation(t *testing.T) {
+	tests := []struct {
+		name                        string
+		mass, fuel, exhaustVelocity float64
+	}{
+		{"half fuel", 1000, 500, 3000},
+		{"little fuel", 1000, 100, 4500},
+		{"mostly fuel", 1000, 900, 2500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			so := &SpaceObject{
+				mass:            tt.mass,
+				fuelMass:        tt.fuel,
+				exhaustVelocity: tt.exhaustVelocity,
+				// one kg of propellant per step, so the steps are fine compared to the change of mass
+				thrust:    tt.exhaustVelocity / dt,
+				thrusting: true,
+			}
+			for so.ApplyThrust() {
+			}
+
+			// delta-v = v_e * ln(m0 / m1)
+			want := tt.exhaustVelocity * math.Log(tt.mass/(tt.mass-tt.fuel))
+			if got := so.velocity.Length(); math.Abs(got-want) > 2e-3*want {
+				t.Errorf("delta-v %g m/s, want %g m/s", got, want)
+			}
+			if so.fuelMass != 0 || math.Abs(so.mass-(tt.mass-tt.fuel)) > 1e-9 {
+				t.Errorf("mass %g kg with %g kg fuel left, want %g kg without fuel", so.mass, so.fuelMass, tt.mass-tt.fuel)
+			}
+		})
+	}
+}
+
+func TestSpacecraftAcceleration(t *testing.T) {
+	for _, radius := range []float64{testOrbitRadius, 1e9} {
+		g := circularOrbitGame(radius)
+		acceleration, ok := g.SpacecraftAcceleration()
+		if !ok {
+			t.Fatal("no acceleration with a spacecraft")
+		}
+		want := g.config.gravitationalConstant() * testStarMass / (radius * radius)
+		if math.Abs(acceleration-want) > 1e-12*want {
+			t.Errorf("acceleration at %v m is %v m/s², want GM/r² = %v", radius, acceleration, want)
+		}
+	}
+
+	g := circularOrbitGame(testOrbitRadius)
+	g.spaceObjects = g.spaceObjects[:1]
+	if _, ok := g.SpacecraftAcceleration(); ok {
+		t.Error("acceleration reported without a spacecraft")
+	}
+}
+
+func TestMassiveSpacecraftPullsThePlanet(t *testing.T) {
+	// a spacecraft as heavy as the planet, 1e9 m away along x
+	setup := func(gravitates bool) *Game {
+		g := newGame()
+		g.config.spacecraftGravitates = gravitates
+		g.spaceObjects = []*SpaceObject{
+			{name: "planet", mass: 6e24, radius: 6.4e6},
+			{name: "craft", mass: 6e24, radius: 1, isSpacecraft: true, position: Vector{1e9, 0}},
+		}
+		return g
+	}
+
+	g := setup(true)
+	g.Step()
+	planet := g.spaceObjects[0]
+	if planet.velocity.X <= 0 {
+		t.Errorf("planet moves at %v m/s, want towards the gravitating spacecraft", planet.velocity)
+	}
+	// both bodies have the same mass, so they fall towards each other equally fast
+	if craft := g.spaceObjects[1]; math.Abs(planet.velocity.X+craft.velocity.X) > 1e-9*planet.velocity.X {
+		t.Errorf("planet moves at %v m/s and the spacecraft at %v, want opposite speeds", planet.velocity, craft.velocity)
+	}
+
+	// as a test particle the spacecraft still falls, but the planet stays at rest
+	g = setup(false)
+	g.Step()
+	if v := g.spaceObjects[0].velocity; v != (Vector{0, 0}) {
+		t.Errorf("planet moves at %v m/s, a test particle should not pull it", v)
+	}
+	if v := g.spaceObjects[1].velocity; v.X >= 0 {
+		t.Errorf("spacecraft moves at %v m/s, want towards the planet", v)
+	}
+}
+
+func TestProgradeLockOrientation(t *testing.T) {
+	body := &SpaceObject{name: "planet", velocity: Vector{100, 0}}
+	tests := []struct {
+		name     string
+		velocity Vector
+		lock     bool
+		want     float64
+	}{
+		{"locked along its velocity", Vector{100, 50}, true, Vector{0, 50}.Angle()},
+		{"locked backwards", Vector{-200, -100}, true, Vector{-300, -100}.Angle()},
+		{"heading without the lock", Vector{100, 50}, false, 0.3},
+		// at rest relative to the body the last orientation is held
+		{"locked at rest", Vector{100, 0}, true, 1},
+		{"locked almost at rest", Vector{100, minLockSpeed / 2}, true, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			craft := &SpaceObject{isSpacecraft: true, velocity: test.velocity, heading: 0.3, orientation: 1}
+			craft.orient(body, test.lock)
+			if math.Abs(craft.orientation-test.want) > 1e-12 {
+				t.Errorf("orientation is %v rad, want %v", craft.orientation, test.want)
+			}
+		})
+	}
+
+	// without a dominant body the velocity is taken as it is
+	craft := &SpaceObject{isSpacecraft: true, velocity: Vector{0, -5}}
+	craft.orient(nil, true)
+	if want := -math.Pi / 2; math.Abs(craft.orientation-want) > 1e-12 {
+		t.Errorf("orientation without a body is %v rad, want %v", craft.orientation, want)
+	}
+}
+
+func TestStaging(t *testing.T) {
+	g := circularOrbitGame(testOrbitRadius)
+	g.stageMass, g.minDryMass, g.separationSpeed = 1000, 1000, 5
+	craft := g.spaceObjects[1]
+	craft.mass, craft.fuelMass = 3500, 2000
+	craft.thrust, craft.exhaustVelocity, craft.thrusting = 1000, 1e6, true // a step burns 43.2 kg
+
+	// the velocity change of one step of thrust, taken on a copy so the spacecraft itself does not burn
+	thrustDeltaV := func() float64 {
+		copied := *craft
+		before := copied.velocity
+		copied.ApplyThrust()
+		return math.Sqrt(copied.velocity.DistanceSquared(before))
+	}
+	before, velocity := thrustDeltaV(), craft.velocity
+
+	g.stageSpacecraft()
+	// the propellant above the new dry mass went with the stage
+	if craft.mass != 2500 || craft.fuelMass != 1500 {
+		t.Errorf("staged spacecraft has %v kg with %v kg fuel, want 2500 kg with 1500 kg", craft.mass, craft.fuelMass)
+	}
+	if want := velocity.Translate(5, 0); craft.velocity != want {
+		t.Errorf("staged spacecraft moves at %v m/s, want %v after the separation along its heading", craft.velocity, want)
+	}
+	// the same thrust accelerates the lighter spacecraft more, in inverse proportion to its mass
+	if after := thrustDeltaV(); math.Abs(after/before-3500.0/2500) > 1e-12 {
+		t.Errorf("thrust changes the velocity by %v m/s after staging and %v before, want %v times as much", after, before, 3500.0/2500)
+	}
+
+	// staging down to the dry mass is fine, below it is refused
+	g.stageSpacecraft()
+	if craft.mass != 1500 {
+		t.Fatalf("spacecraft has %v kg after the second staging, want 1500", craft.mass)
+	}
+	velocity = craft.velocity
+	g.stageSpacecraft()
+	if craft.mass != 1500 || craft.velocity != velocity {
+		t.Errorf("staging below the dry mass left %v kg at %v m/s, want it refused with 1500 kg at %v", craft.mass, craft.velocity, velocity)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"math"
+	"slices"
+)
+
+// below this number of spaceobjects building the grid costs more than the pairs it prunes
+const spatialHashMinBodies int = 64
+
+// cell of the spatial hash grid
+type gridCell struct{ x, y int }
+
+// a uniform grid with cells as large as the cutoff distance, so all bodies within the cutoff of a body
+// are in its own cell or one of the eight around it
+type spatialHash struct {
+	size  float64            // edge length of a cell in m
+	cells map[gridCell][]int // indices of the spaceobjects in each cell, ascending
+}
+
+// sorts the spaceobjects into the cells of a grid with the given cell size
+func newSpatialHash(spaceObjects []*SpaceObject, size float64) spatialHash {
+	h := spatialHash{size: size, cells: map[gridCell][]int{}}
+	for i, so := range spaceObjects {
+		cell := h.cellOf(so.position)
+		h.cells[cell] = append(h.cells[cell], i)
+	}
+	return h
+}
+
+// returns the cell the given position is in
+func (h spatialHash) cellOf(p Vector) gridCell {
+	return gridCell{int(math.Floor(p.X / h.size)), int(math.Floor(p.Y / h.size))}
+}
+
+// returns the indices of the spaceobjects in the cell of the position and the cells around it, ascending
+// the order is the one of the direct summation, so the forces from these candidates are bit for bit the same
+func (h spatialHash) neighbors(p Vector) []int {
+	center := h.cellOf(p)
+	var indices []int
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			indices = append(indices, h.cells[gridCell{center.x + dx, center.y + dy}]...)
+		}
+	}
+	slices.Sort(indices)
+	return indices
+}
+
+// returns true if the forces should be computed from the spatial hash instead of all pairs
+// the grid needs a finite cutoff for its cells and only pays off for many bodies
+func (c SimConfig) useSpatialHash(bodies int) bool {
+	return c.spatialHash && !math.IsInf(c.cutoffDistance, 1) && c.cutoffDistance > 0 && bodies >= spatialHashMinBodies
+}
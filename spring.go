@@ -0,0 +1,66 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+var tetherColor = color.RGBA{200, 200, 200, 255}
+
+// Spring connects two spaceobjects with a tether following Hooke's law
+type Spring struct {
+	a          int     // index of the first spaceobject
+	b          int     // index of the second spaceobject
+	restLength float64 // length of the spring without any force in m
+	stiffness  float64 // spring constant k in N/m
+}
+
+// returns the force the spring puts on its first object, the second one gets the opposite force
+// Hooke's law: F = -k * (length - restLength), pulling the objects together when stretched
+func (s Spring) force(spaceObjects []*SpaceObject) Vector {
+	a := spaceObjects[s.a]
+	b := spaceObjects[s.b]
+
+	distanceVector := Vector{a.position.X - b.position.X, a.position.Y - b.position.Y}
+	length := distanceVector.Length()
+	if length == 0 {
+		return Vector{0, 0}
+	}
+
+	magnitude := -s.stiffness * (length - s.restLength)
+	direction := distanceVector.Normalize()
+	return Vector{magnitude * direction.X, magnitude * direction.Y}
+}
+
+// returns the potential energy 1/2 * k * (length - restLength)^2 stored in the spring
+func (s Spring) potentialEnergy(spaceObjects []*SpaceObject) float64 {
+	length := math.Sqrt(spaceObjects[s.a].position.DistanceSquared(spaceObjects[s.b].position))
+	stretch := length - s.restLength
+	return 0.5 * s.stiffness * stretch * stretch
+}
+
+// updates the springs after the object at index removed was merged into the one at index into
+// springs between the two merged objects are dropped
+func remapSprings(springs []Spring, into, removed int) []Spring {
+	kept := springs[:0]
+	for _, s := range springs {
+		s.a = remapIndex(s.a, into, removed)
+		s.b = remapIndex(s.b, into, removed)
+		if s.a != s.b {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// draws every spring as a line between its objects
+func (g *Game) drawTethers(screen *ebiten.Image) {
+	for _, s := range g.springs {
+		from := g.spaceObjects[s.a].scaledPosition
+		to := g.spaceObjects[s.b].scaledPosition
+		vector.StrokeLine(screen, float32(from.X), float32(from.Y), float32(to.X), float32(to.Y), 1, tetherColor, true)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSpringOscillatesAboutTheRestLength(t *testing.T) {
+	// two light bodies, gravity between them is negligible against the spring
+	const (
+		mass       = 1000.0
+		restLength = 1000.0
+		stretch    = 100.0
+		steps      = 50 // per period of the oscillation
+	)
+	// the bodies oscillate with the reduced mass m/2: omega = sqrt(2k/m)
+	omega := 2 * math.Pi / (steps * dt)
+	stiffness := omega * omega * mass / 2
+
+	g := newGame()
+	g.spaceObjects = []*SpaceObject{
+		{name: "a", mass: mass, radius: 1, position: Vector{-(restLength + stretch) / 2, 0}},
+		{name: "b", mass: mass, radius: 1, position: Vector{(restLength + stretch) / 2, 0}},
+	}
+	g.springs = []Spring{{a: 0, b: 1, restLength: restLength, stiffness: stiffness}}
+
+	shortest, longest := math.Inf(1), math.Inf(-1)
+	crossings := 0
+	stretched := true
+	for range 4 * steps {
+		g.Step()
+		length := math.Sqrt(g.spaceObjects[0].position.DistanceSquared(g.spaceObjects[1].position))
+		shortest = math.Min(shortest, length)
+		longest = math.Max(longest, length)
+		if (length > restLength) != stretched {
+			stretched = !stretched
+			crossings++
+		}
+	}
+
+	// stretched by 100 m the spring swings between 900 and 1100 m and passes the rest length twice per period
+	if math.Abs(longest-(restLength+stretch)) > 0.05*stretch || math.Abs(shortest-(restLength-stretch)) > 0.05*stretch {
+		t.Errorf("length swings between %v and %v m, want %v and %v", shortest, longest, restLength-stretch, restLength+stretch)
+	}
+	if crossings != 8 {
+		t.Errorf("passed the rest length %d times in four periods, want 8", crossings)
+	}
+
+	// the spring pulls symmetrically, so the center of mass stays at the origin
+	center := g.spaceObjects[0].position.Translate(g.spaceObjects[1].position.X, g.spaceObjects[1].position.Y)
+	if math.Abs(center.X) > 1e-6 || math.Abs(center.Y) > 1e-6 {
+		t.Errorf("center of mass moved to %v", center.Scale(0.5, 0.5))
+	}
+}
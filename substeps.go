@@ -0,0 +1,34 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const substepDotRadius float32 = 1.5 // radius of the substep dots in pixel
+
+var substepDotColor = color.RGBA{255, 80, 80, 120}
+
+// remembers the positions of all spaceobjects after a substep, if the dots are shown
+// the dots are cleared at the start of every step, so only the substeps of the last step are drawn
+func (g *Game) recordSubstep() {
+	if !g.showSubsteps {
+		return
+	}
+	for _, so := range g.spaceObjects {
+		g.substepDots = append(g.substepDots, so.position)
+	}
+}
+
+// draws the recorded substep positions as small faint dots
+func (g *Game) drawSubsteps(screen *ebiten.Image) {
+	if !g.showSubsteps {
+		return
+	}
+	for _, dot := range g.substepDots {
+		p := g.worldToScreen(dot)
+		vector.DrawFilledCircle(screen, float32(p.X), float32(p.Y), substepDotRadius, substepDotColor, true)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+func TestSubstepDots(t *testing.T) {
+	for _, substeps := range []int{1, 4, 10} {
+		g := circularOrbitGame(testOrbitRadius)
+		g.config.substeps = substeps
+		g.showSubsteps = true
+		bodies := len(g.spaceObjects)
+
+		// every step starts over, so the dots are always those of the last step
+		for step := 0; step < 3; step++ {
+			g.Step()
+			if len(g.substepDots) != substeps*bodies {
+				t.Fatalf("%d substeps: recorded %d dots in step %d, want %d", substeps, len(g.substepDots), step, substeps*bodies)
+			}
+		}
+		// the dots of the last substep are where the bodies ended up
+		last := g.substepDots[len(g.substepDots)-bodies:]
+		for i, so := range g.spaceObjects {
+			if last[i] != so.position {
+				t.Errorf("%d substeps: last dot of %s is at %v, want %v", substeps, so.name, last[i], so.position)
+			}
+		}
+	}
+
+	g := circularOrbitGame(testOrbitRadius)
+	g.config.substeps = 4
+	g.Step()
+	if len(g.substepDots) != 0 {
+		t.Errorf("recorded %d dots with the dots hidden", len(g.substepDots))
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"image/color"
+	"math"
+)
+
+// determines the color the path pixels are filled with
+type trailColorMode int
+
+const (
+	trailColorBody      trailColorMode = iota // path has the color of the object
+	trailColorSpeed                           // path is colored from blue (slow) to red (fast)
+	trailColorDirection                       // path hue follows the direction of travel around the color wheel
+)
+
+// returns the next trail color mode, wrapping around after the last one
+func (m trailColorMode) next() trailColorMode {
+	if m == trailColorDirection {
+		return trailColorBody
+	}
+	return m + 1
+}
+
+// returns the size of a path image covering a screen of the given size at the trail resolution
+func (g *Game) trailImageSize(screenWidth, screenHeight int) (width, height int) {
+	width = max(1, int(math.Ceil(float64(screenWidth)*g.trailScale)))
+	height = max(1, int(math.Ceil(float64(screenHeight)*g.trailScale)))
+	return width, height
+}
+
+// returns the thickness of the paths in pixel of the path images, at least one pixel
+func (g *Game) trailStampWidth() float64 {
+	return math.Max(1, g.trailWidth*g.trailScale)
+}
+
+// converts a screen position in pixel to the pixel coordinates of the path images
+func (g *Game) trailPoint(screenPosition Vector) Vector {
+	return screenPosition.Scale(g.trailScale, g.trailScale)
+}
+
+// returns true if the current position is far enough from the last path point to extend the path
+func needsPathPoint(last, current Vector, spacing float64) bool {
+	return last.DistanceSquared(current) >= spacing*spacing
+}
+
+// maps t in [0, 1] to a color from blue (0) to red (1)
+func speedColor(t float64) color.Color {
+	t = math.Max(0, math.Min(1, t))
+	return color.RGBA{uint8(255 * t), 0, uint8(255 * (1 - t)), 255}
+}
+
+// maps an angle in rad to a fully saturated color, 0 (moving along +x) is red, going around the hue wheel counterclockwise
+func directionColor(angle float64) color.Color {
+	hue := math.Mod(angle/(2*math.Pi), 1)
+	if hue < 0 {
+		hue++
+	}
+	return hsvColor(hue, 1, 1)
+}
+
+// converts a color from hsv (all components in [0, 1]) to rgb
+func hsvColor(h, s, v float64) color.Color {
+	sector := math.Floor(h * 6)
+	f := h*6 - sector
+	p := v * (1 - s)
+	q := v * (1 - f*s)
+	t := v * (1 - (1-f)*s)
+
+	var r, g, b float64
+	switch int(sector) % 6 {
+	case 0:
+		r, g, b = v, t, p
+	case 1:
+		r, g, b = q, v, p
+	case 2:
+		r, g, b = p, v, t
+	case 3:
+		r, g, b = p, q, v
+	case 4:
+		r, g, b = t, p, v
+	default:
+		r, g, b = v, p, q
+	}
+	return color.RGBA{uint8(255 * r), uint8(255 * g), uint8(255 * b), 255}
+}
+
+// remembers the current speed of the object as part of its observed speed range
+func (so *SpaceObject) trackSpeed() {
+	speed := so.velocity.Length()
+	if !so.speedObserved {
+		so.minSpeed = speed
+		so.maxSpeed = speed
+		so.speedObserved = true
+		return
+	}
+	so.minSpeed = math.Min(so.minSpeed, speed)
+	so.maxSpeed = math.Max(so.maxSpeed, speed)
+}
+
+// returns the color the next path pixel of the object is filled with
+func (g *Game) trailColor(so *SpaceObject) color.Color {
+	switch g.trailColorMode {
+	case trailColorSpeed:
+		// normalize against the observed speeds so the whole gradient is used
+		if so.maxSpeed == so.minSpeed {
+			return speedColor(0.5)
+		}
+		return speedColor((so.velocity.Length() - so.minSpeed) / (so.maxSpeed - so.minSpeed))
+	case trailColorDirection:
+		return directionColor(so.velocity.Angle())
+	default:
+		return so.color
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestNeedsPathPoint(t *testing.T) {
+	tests := []struct {
+		current Vector
+		spacing float64
+		want    bool
+	}{
+		{Vector{0, 0}, 2, false},
+		{Vector{1.9, 0}, 2, false},
+		{Vector{2, 0}, 2, true},
+		{Vector{1.5, 1.5}, 2, true},
+		{Vector{0, -1}, 2, false},
+		{Vector{0, 0}, 0, true},
+	}
+	for _, test := range tests {
+		if got := needsPathPoint(Vector{0, 0}, test.current, test.spacing); got != test.want {
+			t.Errorf("needsPathPoint(0, %v, %v) = %v, want %v", test.current, test.spacing, got, test.want)
+		}
+	}
+}
+
+func TestPathPointSpacing(t *testing.T) {
+	// an object moving half a pixel per frame gets a point every fourth frame with a spacing of 2
+	last := Vector{0, 0}
+	var points []Vector
+	for frame := 1; frame <= 20; frame++ {
+		current := Vector{float64(frame) / 2, 0}
+		if needsPathPoint(last, current, 2) {
+			points = append(points, current)
+			last = current
+		}
+	}
+	if len(points) != 5 {
+		t.Fatalf("got %d points, want 5: %v", len(points), points)
+	}
+	for i, point := range points {
+		want := Vector{float64(2 * (i + 1)), 0}
+		if point != want {
+			t.Errorf("point %d is %v, want %v", i, point, want)
+		}
+	}
+
+	// a fast object gets a point every frame, the line segments between them close the gaps
+	last = Vector{0, 0}
+	for frame := 1; frame <= 5; frame++ {
+		current := Vector{float64(50 * frame), 0}
+		if !needsPathPoint(last, current, 2) {
+			t.Errorf("no point for frame %d of a fast object", frame)
+		}
+		last = current
+	}
+}
+
+func TestDirectionColor(t *testing.T) {
+	tests := []struct {
+		angle float64
+		want  color.RGBA
+	}{
+		{0, color.RGBA{255, 0, 0, 255}},                 // east is red
+		{math.Pi / 2, color.RGBA{127, 255, 0, 255}},     // a quarter around the hue wheel
+		{math.Pi, color.RGBA{0, 255, 255, 255}},         // west is cyan, opposite of red
+		{-math.Pi / 2, color.RGBA{127, 0, 255, 255}},    // the same as 3/2 pi
+		{3 * math.Pi / 2, color.RGBA{127, 0, 255, 255}}, // three quarters around
+		{2 * math.Pi, color.RGBA{255, 0, 0, 255}},       // a full turn is red again
+	}
+	for _, test := range tests {
+		if got := directionColor(test.angle); got != test.want {
+			t.Errorf("directionColor(%v) = %v, want %v", test.angle, got, test.want)
+		}
+	}
+}
+
+func TestTrailResolution(t *testing.T) {
+	tests := []struct {
+		scale         float64
+		width, height int
+	}{
+		{1, 801, 600},
+		{2, 1602, 1200},
+		{0.5, 401, 300},
+		{0.001, 1, 1},
+	}
+	for _, test := range tests {
+		g := newGame()
+		g.trailScale = test.scale
+		if width, height := g.trailImageSize(801, 600); width != test.width || height != test.height {
+			t.Errorf("path image at scale %v is %dx%d, want %dx%d", test.scale, width, height, test.width, test.height)
+		}
+	}
+
+	// a body is stamped at its screen position scaled into the path image, which is drawn back scaled by 1/trailScale
+	g := newGame()
+	g.screenWidth, g.screenHeight = 800, 600
+	g.trailScale = 0.5
+	screen := g.worldToScreen(Vector{1e9, -2e9})
+	point := g.trailPoint(screen)
+	if want := (Vector{(400 + 100) / 2, (300 - 200) / 2}); point != want {
+		t.Errorf("stamped at %v in the path image, want %v", point, want)
+	}
+	if back := point.Scale(1/g.trailScale, 1/g.trailScale); back != screen {
+		t.Errorf("stamp is drawn at %v on screen, want %v", back, screen)
+	}
+}
+
+func TestTrailStampWidth(t *testing.T) {
+	tests := []struct {
+		width, scale float64
+		want         float64
+	}{
+		{1, 1, 1},
+		{3, 1, 3},
+		{3, 2, 6},
+		// the path images at a lower resolution get a thinner stamp, but never below a pixel
+		{3, 0.5, 1.5},
+		{1, 0.5, 1},
+		{0, 1, 1},
+	}
+	for _, test := range tests {
+		g := newGame()
+		g.trailWidth = test.width
+		g.trailScale = test.scale
+		if got := g.trailStampWidth(); got != test.want {
+			t.Errorf("stamp of a %v pixel trail at scale %v is %v pixel, want %v", test.width, test.scale, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	shortTrailLength int     = 60 // number of recent positions a short trail shows
+	trailFadeFrames  float32 = 60 // frames a full trail takes to fade out after losing its detail
+)
+
+// level of detail of the trail of a spaceobject
+type trailLOD int
+
+const (
+	trailFull  trailLOD = iota // the whole path is kept in the path image
+	trailShort                 // only the recent positions are drawn as a line
+	trailNone                  // no trail is drawn
+)
+
+// thresholds of the trail level of detail
+// the selected body and the full nearest bodies to the camera get full trails, the next short ones short trails
+type trailLODConfig struct {
+	full  int // number of bodies nearest to the camera with a full trail, 0 keeps every trail full
+	short int // number of further bodies with a short trail
+}
+
+// returns the trail level of every position by its distance to the center of the view
+// the selected one always gets a full trail, ties in the distance keep the order of the positions
+func trailLevels(positions []Vector, selected int, center Vector, config trailLODConfig) []trailLOD {
+	levels := make([]trailLOD, len(positions))
+	if config.full <= 0 {
+		return levels
+	}
+
+	order := make([]int, len(positions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return positions[order[a]].DistanceSquared(center) < positions[order[b]].DistanceSquared(center)
+	})
+
+	rank := 0
+	for _, i := range order {
+		switch {
+		case i == selected:
+			levels[i] = trailFull
+			continue
+		case rank < config.full:
+			levels[i] = trailFull
+		case rank < config.full+config.short:
+			levels[i] = trailShort
+		default:
+			levels[i] = trailNone
+		}
+		rank++
+	}
+	return levels
+}
+
+// sets the trail level of every spaceobject with a trail and fades out the full trails that lost their detail
+// bodies returning to a full trail start a new path, the old one would connect to it with a straight line
+func (g *Game) updateTrailLOD() {
+	if g.trailLOD.full <= 0 {
+		return
+	}
+	var positions []Vector
+	var objects []*SpaceObject
+	selected := -1
+	for i, so := range g.spaceObjects {
+		if !so.showTrail {
+			continue
+		}
+		if i == g.selected {
+			selected = len(objects)
+		}
+		positions = append(positions, so.position)
+		objects = append(objects, so)
+	}
+
+	focus := g.cameraFocus()
+	center := focus.Translate(g.camera.offset.X, g.camera.offset.Y)
+	for i, level := range trailLevels(positions, selected, center, g.trailLOD) {
+		so := objects[i]
+		switch {
+		case level == trailFull && so.trailLevel != trailFull:
+			if so.pathImg != nil {
+				so.pathImg.Clear()
+			}
+			so.hasPathPoint = false
+		case level != trailFull && so.trailLevel == trailFull:
+			so.trailFade = 1
+		}
+		so.trailLevel = level
+
+		if level != trailFull && so.pathImg != nil {
+			so.trailFade -= 1 / trailFadeFrames
+			if so.trailFade <= 0 {
+				so.pathImg = nil
+			}
+		}
+		if level == trailShort {
+			so.recentPositions = append(so.recentPositions, so.position)
+			if len(so.recentPositions) > shortTrailLength {
+				so.recentPositions = so.recentPositions[len(so.recentPositions)-shortTrailLength:]
+			}
+		} else {
+			so.recentPositions = so.recentPositions[:0]
+		}
+	}
+}
+
+// draws the trail of a spaceobject without a full trail: what is left of its fading path image and its short trail
+func (g *Game) drawReducedTrail(screen *ebiten.Image, so *SpaceObject) {
+	if so.pathImg != nil {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(1/g.trailScale, 1/g.trailScale)
+		op.Filter = ebiten.FilterLinear
+		op.ColorScale.ScaleAlpha(so.trailFade)
+		screen.DrawImage(so.pathImg, op)
+	}
+
+	trailColor := g.trailColor(so)
+	for i := 1; i < len(so.recentPositions); i++ {
+		from, to := g.worldToScreen(so.recentPositions[i-1]), g.worldToScreen(so.recentPositions[i])
+		vector.StrokeLine(screen, float32(from.X), float32(from.Y), float32(to.X), float32(to.Y), float32(g.trailWidth), trailColor, true)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestTrailLevels(t *testing.T) {
+	// bodies along the x axis, 1e9 m apart
+	positions := []Vector{{0, 0}, {1e9, 0}, {2e9, 0}, {3e9, 0}, {4e9, 0}}
+	const (
+		F = trailFull
+		S = trailShort
+		N = trailNone
+	)
+	tests := []struct {
+		name     string
+		selected int
+		center   Vector
+		config   trailLODConfig
+		want     []trailLOD
+	}{
+		{"disabled", -1, Vector{}, trailLODConfig{}, []trailLOD{F, F, F, F, F}},
+		{"nearest to the origin", -1, Vector{}, trailLODConfig{full: 2, short: 1}, []trailLOD{F, F, S, N, N}},
+		{"camera moved away", -1, Vector{4.2e9, 0}, trailLODConfig{full: 2, short: 1}, []trailLOD{N, N, S, F, F}},
+		{"camera in the middle", -1, Vector{2e9, 1e9}, trailLODConfig{full: 1, short: 2}, []trailLOD{N, S, F, S, N}},
+		// the selected body does not take the place of another one
+		{"selected far away", 4, Vector{}, trailLODConfig{full: 2, short: 1}, []trailLOD{F, F, S, N, F}},
+		{"selected nearby", 1, Vector{}, trailLODConfig{full: 2, short: 1}, []trailLOD{F, F, F, S, N}},
+		{"no short trails", -1, Vector{}, trailLODConfig{full: 3}, []trailLOD{F, F, F, N, N}},
+		{"more levels than bodies", -1, Vector{}, trailLODConfig{full: 4, short: 4}, []trailLOD{F, F, F, F, S}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := trailLevels(positions, test.selected, test.center, test.config)
+			for i := range test.want {
+				if got[i] != test.want[i] {
+					t.Errorf("trailLevels() = %v, want %v", got, test.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestTrailLODFollowsTheCamera(t *testing.T) {
+	g := newGame()
+	g.trailLOD = trailLODConfig{full: 1, short: 1}
+	for _, x := range []float64{0, 1e9, 2e9} {
+		g.spaceObjects = append(g.spaceObjects, &SpaceObject{mass: 1, position: Vector{x, 0}, showTrail: true})
+	}
+	levels := func() []trailLOD {
+		var levels []trailLOD
+		for _, so := range g.spaceObjects {
+			levels = append(levels, so.trailLevel)
+		}
+		return levels
+	}
+
+	g.updateTrailLOD()
+	if got := levels(); got[0] != trailFull || got[1] != trailShort || got[2] != trailNone {
+		t.Errorf("levels around the origin are %v, want full, short, none", got)
+	}
+
+	// panning to the last body hands its full trail over, the old one fades instead of vanishing
+	first := g.spaceObjects[0]
+	first.pathImg = ebiten.NewImage(4, 4)
+	g.camera.offset = Vector{2e9, 0}
+	g.updateTrailLOD()
+	if got := levels(); got[0] != trailNone || got[1] != trailShort || got[2] != trailFull {
+		t.Errorf("levels after panning are %v, want none, short, full", got)
+	}
+	if first.pathImg == nil || first.trailFade != 1-1/trailFadeFrames {
+		t.Errorf("trail that lost its detail has fade %v, want %v with its path kept", first.trailFade, 1-1/trailFadeFrames)
+	}
+	for range int(trailFadeFrames) {
+		g.updateTrailLOD()
+	}
+	if first.pathImg != nil {
+		t.Errorf("trail is still kept with fade %v after %v frames", first.trailFade, trailFadeFrames)
+	}
+}
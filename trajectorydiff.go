@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const diffMaxSegments int = 2000 // most line segments a recorded path of the diff overlay is drawn with
+
+var diffColors = [2]color.Color{
+	color.RGBA{255, 140, 40, 255},
+	color.RGBA{40, 200, 255, 255},
+}
+
+// difference of the position of one body between two recordings
+type BodyDiff struct {
+	name    string
+	max     float64 // largest distance between the two positions in m
+	maxTime float64 // simulated time of the largest distance in s
+	mean    float64 // mean distance between the two positions in m
+	samples int     // number of compared times
+}
+
+// compares two recordings of the same scene over the time span both of them cover
+type TrajectoryDiff struct {
+	recordings [2]*Recording
+	start, end float64 // common time span in s
+	bodies     []BodyDiff
+}
+
+// reads the two comma separated recordings and compares them
+func loadTrajectoryDiff(paths string) (*TrajectoryDiff, error) {
+	names := strings.Split(paths, ",")
+	if len(names) != 2 {
+		return nil, fmt.Errorf("a diff needs two comma separated recordings, got %q", paths)
+	}
+	var recordings [2]*Recording
+	for i, name := range names {
+		recording, err := LoadRecording(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		recordings[i] = recording
+	}
+	return DiffRecordings(recordings[0], recordings[1])
+}
+
+// compares the positions of the bodies both recordings have, aligned by the simulated time
+// the positions are compared at the frames of a, b is interpolated to the same times
+func DiffRecordings(a, b *Recording) (*TrajectoryDiff, error) {
+	startA, endA := a.timeRange()
+	startB, endB := b.timeRange()
+	d := &TrajectoryDiff{recordings: [2]*Recording{a, b}, start: max(startA, startB), end: min(endA, endB)}
+	if d.start > d.end {
+		return nil, fmt.Errorf("the recordings do not overlap in time")
+	}
+
+	index := map[string]int{}
+	for _, frame := range a.Frames {
+		if frame.Time < d.start || frame.Time > d.end {
+			continue
+		}
+		other := b.StateAt(frame.Time)
+		for _, body := range frame.Bodies {
+			for _, otherBody := range other {
+				if otherBody.Name != body.Name {
+					continue
+				}
+				i, ok := index[body.Name]
+				if !ok {
+					i = len(d.bodies)
+					index[body.Name] = i
+					d.bodies = append(d.bodies, BodyDiff{name: body.Name})
+				}
+				diff := &d.bodies[i]
+				distance := math.Sqrt(body.Position.DistanceSquared(otherBody.Position))
+				if distance > diff.max || diff.samples == 0 {
+					diff.max, diff.maxTime = distance, frame.Time
+				}
+				diff.mean += distance
+				diff.samples++
+				break
+			}
+		}
+	}
+	if len(d.bodies) == 0 {
+		return nil, fmt.Errorf("the recordings have no body in common")
+	}
+	for i := range d.bodies {
+		d.bodies[i].mean /= float64(d.bodies[i].samples)
+	}
+	return d, nil
+}
+
+// writes the maximum and mean difference of every body
+func (d *TrajectoryDiff) write(w io.Writer, units UnitSystem) {
+	fmt.Fprintf(w, "common time span %s to %s\n", formatDuration(d.start), formatDuration(d.end))
+	for _, body := range d.bodies {
+		fmt.Fprintf(w, "%s: max %s at %s, mean %s over %d samples\n",
+			body.name, units.FormatLength(body.max), formatDuration(body.maxTime), units.FormatLength(body.mean), body.samples)
+	}
+}
+
+// returns the largest difference of any body as a line of the HUD, empty if there is no diff
+func (g *Game) diffStatus() string {
+	if g.diff == nil {
+		return ""
+	}
+	worst := g.diff.bodies[0]
+	for _, body := range g.diff.bodies {
+		if body.max > worst.max {
+			worst = body
+		}
+	}
+	return "Diff: " + worst.name + " max " + g.units.FormatLength(worst.max) + ", mean " + g.units.FormatLength(worst.mean)
+}
+
+// draws the paths of all bodies of both recordings in contrasting colors
+func (g *Game) drawDiff(screen *ebiten.Image) {
+	if g.diff == nil {
+		return
+	}
+	for i, recording := range g.diff.recordings {
+		// long recordings are thinned out, the overlay is drawn every frame
+		stride := max(1, len(recording.Frames)/diffMaxSegments)
+		last := map[string]Vector{}
+		for k := 0; k < len(recording.Frames); k += stride {
+			for _, body := range recording.Frames[k].Bodies {
+				p := g.worldToScreen(body.Position)
+				if from, ok := last[body.Name]; ok {
+					vector.StrokeLine(screen, float32(from.X), float32(from.Y), float32(p.X), float32(p.Y), 1, diffColors[i], true)
+				}
+				last[body.Name] = p
+			}
+		}
+	}
+}
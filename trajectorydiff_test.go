@@ -0,0 +1,75 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// returns a recording of frames at the given step numbers with the bodies placed by the given function
+func syntheticRecording(steps []int, bodies func(time float64) []RecordedBody) *Recording {
+	recording := &Recording{}
+	for _, step := range steps {
+		time := float64(step) * dt
+		recording.Frames = append(recording.Frames, RecordedFrame{Time: time, Bodies: bodies(time)})
+	}
+	return recording
+}
+
+func TestDiffRecordings(t *testing.T) {
+	// every step from 0 to 10
+	a := syntheticRecording([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, func(time float64) []RecordedBody {
+		return []RecordedBody{
+			{Name: "craft", Position: Vector{100 * time, 0}},
+			{Name: "probe", Position: Vector{0, time}},
+		}
+	})
+	// every other step from 5 to 15, so b is interpolated at the odd steps of a
+	// the craft is 5 km off throughout, the probe drifts off by 1 km per step
+	b := syntheticRecording([]int{5, 7, 9, 11, 13, 15}, func(time float64) []RecordedBody {
+		return []RecordedBody{
+			{Name: "moon", Position: Vector{1e9, 0}},
+			{Name: "probe", Position: Vector{1000 * time / dt, time}},
+			{Name: "craft", Position: Vector{100*time + 3000, 4000}},
+		}
+	})
+
+	d, err := DiffRecordings(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.start != 5*dt || d.end != 10*dt {
+		t.Errorf("common time span is %v to %v s, want %v to %v", d.start, d.end, 5*dt, 10*dt)
+	}
+	// the moon is only in b, so it is not compared
+	want := []BodyDiff{
+		{name: "craft", max: 5000, mean: 5000, samples: 6},
+		{name: "probe", max: 10000, maxTime: 10 * dt, mean: 7500, samples: 6},
+	}
+	if len(d.bodies) != len(want) {
+		t.Fatalf("diff has %d bodies, want %d", len(d.bodies), len(want))
+	}
+	for i, got := range d.bodies {
+		w := want[i]
+		if got.name != w.name || math.Abs(got.max-w.max) > 1e-6 || math.Abs(got.mean-w.mean) > 1e-6 || got.samples != w.samples {
+			t.Errorf("diff of %q is max %v m, mean %v m over %d samples, want %q with max %v m, mean %v m over %d samples",
+				got.name, got.max, got.mean, got.samples, w.name, w.max, w.mean, w.samples)
+		}
+		// the craft is equally far off everywhere, so only the probe has a defined time of the largest difference
+		if w.name == "probe" && got.maxTime != w.maxTime {
+			t.Errorf("largest difference of %q is at %v s, want %v", got.name, got.maxTime, w.maxTime)
+		}
+	}
+
+	later := syntheticRecording([]int{11, 12}, func(time float64) []RecordedBody {
+		return []RecordedBody{{Name: "craft"}}
+	})
+	if _, err := DiffRecordings(a, later); err == nil {
+		t.Error("recordings without a common time span were compared")
+	}
+	other := syntheticRecording([]int{0, 10}, func(time float64) []RecordedBody {
+		return []RecordedBody{{Name: "moon"}}
+	})
+	if _, err := DiffRecordings(a, other); err == nil {
+		t.Error("recordings without a common body were compared")
+	}
+}
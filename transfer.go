@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// returns the two burns of a hohmann transfer from a circular orbit of radius r1 to one of radius r2
+// around a body of the given mass, and the time between them (half the period of the transfer ellipse)
+// positive delta-vs are prograde, a transfer to a lower orbit has two retrograde burns
+func (c SimConfig) HohmannTransfer(r1, r2, mass float64) (dv1, dv2, transferTime float64) {
+	mu := c.gravitationalConstant() * mass
+	sum := r1 + r2
+
+	// the transfer ellipse touches both orbits, its semi-major axis is (r1 + r2) / 2
+	dv1 = math.Sqrt(mu/r1) * (math.Sqrt(2*r2/sum) - 1)
+	dv2 = math.Sqrt(mu/r2) * (1 - math.Sqrt(2*r1/sum))
+	transferTime = math.Pi * math.Sqrt(sum*sum*sum/(8*mu))
+	return dv1, dv2, transferTime
+}
+
+// a planned hohmann transfer the autopilot executes
+type transferPlan struct {
+	target   float64      // radius of the target orbit in m
+	dv1      float64      // first burn in m/s, done when the plan starts
+	dv2      float64      // second burn in m/s, done at the opposite side of the transfer ellipse
+	burnTime float64      // simulated time of the second burn in s
+	body     *SpaceObject // body the transfer goes around
+	active   bool         // the second burn is still ahead
+}
+
+// changes the velocity of the spacecraft by dv along its velocity relative to the given body
+// returns an error if the fuel is not enough
+func (so *SpaceObject) applyImpulse(body *SpaceObject, dv float64) error {
+	prograde := so.velocity.Translate(-body.velocity.X, -body.velocity.Y).Normalize()
+	return so.applyDeltaV(Vector{prograde.X * dv, prograde.Y * dv})
+}
+
+// changes the velocity of the spacecraft by dv
+// the change is instantaneous, the propellant it costs follows from the rocket equation m1 = m0 * exp(-dv/ve)
+// returns an error if the fuel is not enough
+func (so *SpaceObject) applyDeltaV(dv Vector) error {
+	fuelUsed := so.mass * (1 - math.Exp(-dv.Length()/so.exhaustVelocity))
+	if so.exhaustVelocity <= 0 || fuelUsed > so.fuelMass {
+		return fmt.Errorf("not enough fuel for a burn of %.1f m/s", dv.Length())
+	}
+
+	so.velocity = so.velocity.Translate(dv.X, dv.Y)
+	so.mass -= fuelUsed
+	so.fuelMass -= fuelUsed
+	return nil
+}
+
+// plans a hohmann transfer of the spacecraft to a circular orbit of the given radius around its dominant body
+// and does the first burn right away; the transfer assumes the spacecraft is on a circular orbit now
+func (g *Game) startTransfer(target float64) error {
+	craft, body := g.spacecraftAndDominantBody()
+	if craft == nil || body == nil {
+		return fmt.Errorf("there is no spacecraft orbiting a body")
+	}
+	if target <= 0 {
+		return fmt.Errorf("target radius must be positive, got %g", target)
+	}
+
+	r1 := math.Sqrt(craft.position.DistanceSquared(body.position))
+	dv1, dv2, transferTime := g.config.HohmannTransfer(r1, target, body.mass)
+	if err := craft.applyImpulse(body, dv1); err != nil {
+		return err
+	}
+
+	g.transfer = transferPlan{target: target, dv1: dv1, dv2: dv2, burnTime: g.time + transferTime, body: body, active: true}
+	g.baseline.set = false
+	return nil
+}
+
+// asks for the target radius of a hohmann transfer and starts it
+func (g *Game) planTransfer() {
+	g.startTextInput("Target orbit radius (m): ", func(value string) {
+		target, err := strconv.ParseFloat(value, 64)
+		if err == nil {
+			err = g.startTransfer(target)
+		}
+		if err != nil {
+			g.notify("transfer failed: " + err.Error())
+		}
+	})
+}
+
+// does the second burn of the transfer once its time has come
+func (g *Game) updateTransfer() {
+	if !g.transfer.active || g.time < g.transfer.burnTime {
+		return
+	}
+	g.transfer.active = false
+
+	index := g.spacecraftIndex()
+	if index < 0 || !g.containsObject(g.transfer.body) {
+		return
+	}
+	if err := g.spaceObjects[index].applyImpulse(g.transfer.body, g.transfer.dv2); err != nil {
+		g.notify("transfer failed: " + err.Error())
+	}
+	g.baseline.set = false
+}
+
+// returns true if the given spaceobject is still part of the simulation
+func (g *Game) containsObject(so *SpaceObject) bool {
+	for _, other := range g.spaceObjects {
+		if other == so {
+			return true
+		}
+	}
+	return false
+}
+
+// returns the transfer plan as a line of the HUD, empty if no transfer is running
+func (g *Game) transferStatus() string {
+	if !g.transfer.active {
+		return ""
+	}
+	return fmt.Sprintf("Transfer to %s: dv1 %s, dv2 %s in %s",
+		g.units.FormatLength(g.transfer.target),
+		g.units.FormatSpeed(g.transfer.dv1),
+		g.units.FormatSpeed(g.transfer.dv2),
+		formatDuration(g.transfer.burnTime-g.time))
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHohmannTransfer(t *testing.T) {
+	// low earth orbit at 300 km altitude to geostationary orbit: 2.426 and 1.467 km/s, 5.27 hours
+	config := DefaultSimConfig()
+	earthMass := 3.986004418e14 / config.gravitationalConstant()
+	dv1, dv2, transferTime := config.HohmannTransfer(6678e3, 42164e3, earthMass)
+	if math.Abs(dv1-2425.8) > 0.1 || math.Abs(dv2-1466.8) > 0.1 {
+		t.Errorf("burns are %v and %v m/s, want 2425.8 and 1466.8", dv1, dv2)
+	}
+	if math.Abs(transferTime/3600-5.275) > 0.001 {
+		t.Errorf("transfer takes %v h, want 5.275", transferTime/3600)
+	}
+
+	// the way back has the same burns in reverse order, done retrograde
+	back1, back2, backTime := config.HohmannTransfer(42164e3, 6678e3, earthMass)
+	if math.Abs(back1+dv2) > 1e-9 || math.Abs(back2+dv1) > 1e-9 || backTime != transferTime {
+		t.Errorf("burns back are %v and %v m/s over %v s, want %v and %v over %v", back1, back2, backTime, -dv2, -dv1, transferTime)
+	}
+
+	// a transfer to the same orbit needs no burns
+	if dv1, dv2, _ := config.HohmannTransfer(6678e3, 6678e3, earthMass); dv1 != 0 || dv2 != 0 {
+		t.Errorf("burns to the same orbit are %v and %v m/s, want 0", dv1, dv2)
+	}
+}
+
+func TestTransferAutopilot(t *testing.T) {
+	g := circularOrbitGame(testOrbitRadius)
+	g.config.integrator = integratorRK4
+	craft := g.spaceObjects[1]
+	craft.fuelMass = 0.9
+	craft.exhaustVelocity = 1e5
+
+	target := 2 * testOrbitRadius
+	if err := g.startTransfer(target); err != nil {
+		t.Fatal(err)
+	}
+	for steps := 0; g.transfer.active; steps++ {
+		if steps > 2000 {
+			t.Fatal("the second burn never happened")
+		}
+		g.Step()
+	}
+
+	// the second burn is done at the end of the step that reached the burn time, so the orbit is nearly circular
+	elements, _, ok := g.OrbitalElements()
+	if !ok {
+		t.Fatal("no orbit after the transfer")
+	}
+	if math.Abs(elements.semiMajorAxis/target-1) > 0.01 || elements.eccentricity > 0.01 {
+		t.Errorf("orbit after the transfer has a = %v m and e = %v, want a circle of %v m", elements.semiMajorAxis, elements.eccentricity, target)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+const (
+	astronomicalUnit float64 = 1.495978707e11         // length of one astronomical unit in m
+	secondsPerDay    float64 = 60 * 60 * 24           // length of one day in s
+	secondsPerYear   float64 = 365.25 * secondsPerDay // length of one julian year in s
+)
+
+// converts a distance from astronomical units to meters
+func auToMeters(au float64) float64 {
+	return au * astronomicalUnit
+}
+
+// converts a distance from meters to astronomical units
+func metersToAU(m float64) float64 {
+	return m / astronomicalUnit
+}
+
+// converts a speed from km/s to m/s
+func kmsToMs(kms float64) float64 {
+	return kms * 1000
+}
+
+// converts a speed from m/s to km/s
+func msToKms(ms float64) float64 {
+	return ms / 1000
+}
+
+// converts a duration from seconds to days
+func secondsToDays(s float64) float64 {
+	return s / secondsPerDay
+}
+
+// A UnitSystem describes how SI values are shown in the HUD.
+// The physics always runs in SI, only the displayed numbers are converted.
+type UnitSystem struct {
+	name     string
+	length   string                   // unit symbol for distances
+	time     string                   // unit symbol for durations
+	speed    string                   // unit symbol for speeds
+	toLength func(m float64) float64  // converts a distance in m to the display unit
+	toTime   func(s float64) float64  // converts a duration in s to the display unit
+	toSpeed  func(ms float64) float64 // converts a speed in m/s to the display unit
+}
+
+var unitSystems = map[string]UnitSystem{
+	"si": {
+		name:     "si",
+		length:   "m",
+		time:     "s",
+		speed:    "m/s",
+		toLength: func(m float64) float64 { return m },
+		toTime:   func(s float64) float64 { return s },
+		toSpeed:  func(ms float64) float64 { return ms },
+	},
+	"astro": {
+		name:     "astro",
+		length:   "AU",
+		time:     "d",
+		speed:    "km/s",
+		toLength: metersToAU,
+		toTime:   secondsToDays,
+		toSpeed:  msToKms,
+	},
+}
+
+// returns the unit system with the given name
+func unitSystemByName(name string) (UnitSystem, error) {
+	units, ok := unitSystems[name]
+	if !ok {
+		return UnitSystem{}, fmt.Errorf("unknown unit system %q (available: si, astro)", name)
+	}
+	return units, nil
+}
+
+// formats a distance given in m
+func (u UnitSystem) FormatLength(m float64) string {
+	return strconv.FormatFloat(u.toLength(m), 'g', 4, 64) + " " + u.length
+}
+
+// formats a duration given in s
+func (u UnitSystem) FormatTime(s float64) string {
+	return strconv.FormatFloat(u.toTime(s), 'f', 2, 64) + " " + u.time
+}
+
+// formats a speed given in m/s
+func (u UnitSystem) FormatSpeed(ms float64) string {
+	return strconv.FormatFloat(u.toSpeed(ms), 'f', 2, 64) + " " + u.speed
+}
+
+// units a duration is shown in, from the largest to the smallest
+var durationUnits = []struct {
+	symbol  string
+	seconds float64
+}{
+	{"y", secondsPerYear},
+	{"d", secondsPerDay},
+	{"h", 60 * 60},
+	{"min", 60},
+	{"s", 1},
+}
+
+// formats a duration given in s in the largest unit it is at least one of, e.g. "30.00 d" or "2.46 y"
+func formatDuration(seconds float64) string {
+	for _, unit := range durationUnits {
+		if math.Abs(seconds) >= unit.seconds {
+			return strconv.FormatFloat(seconds/unit.seconds, 'f', 2, 64) + " " + unit.symbol
+		}
+	}
+	return strconv.FormatFloat(seconds, 'f', 2, 64) + " s"
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUnitConversions(t *testing.T) {
+	tests := []struct {
+		name      string
+		convert   func(float64) float64
+		back      func(float64) float64
+		value     float64
+		converted float64
+	}{
+		{"au to m", auToMeters, metersToAU, 1, 1.495978707e11},
+		{"au to m, fraction", auToMeters, metersToAU, 0.5, 7.479893535e10},
+		{"km/s to m/s", kmsToMs, msToKms, 29.78, 29780},
+		{"km/s to m/s, negative", kmsToMs, msToKms, -1.5, -1500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.convert(tt.value)
+			if math.Abs(got-tt.converted) > 1e-12*math.Abs(tt.converted) {
+				t.Errorf("converted %g to %g, want %g", tt.value, got, tt.converted)
+			}
+			if back := tt.back(got); math.Abs(back-tt.value) > 1e-12*math.Abs(tt.value) {
+				t.Errorf("converted %g back to %g, want %g", got, back, tt.value)
+			}
+		})
+	}
+}
+
+func TestSpeedStatus(t *testing.T) {
+	planet := &SpaceObject{name: "planet", velocity: Vector{3, 4}}
+	craft := &SpaceObject{name: "craft", isSpacecraft: true, velocity: Vector{6, 8}}
+
+	tests := []struct {
+		name         string
+		spaceObjects []*SpaceObject
+		focus        int
+		want         string
+	}{
+		{"spacecraft not first", []*SpaceObject{planet, craft}, -1, "10.00 m/s"},
+		{"focus without spacecraft", []*SpaceObject{planet}, 0, "5.00 m/s"},
+		{"no focus", []*SpaceObject{planet}, -1, "Speed: -"},
+		{"no objects", nil, -1, "Speed: -"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGame()
+			g.spaceObjects = tt.spaceObjects
+			g.focus = tt.focus
+			if got := g.speedStatus(); got != tt.want {
+				t.Errorf("speed %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		seconds float64
+		want    string
+	}{
+		{0, "0.00 s"},
+		{0.5, "0.50 s"},
+		{59.99, "59.99 s"},
+		{60, "1.00 min"},
+		{3599, "59.98 min"},
+		{3600, "1.00 h"},
+		{secondsPerDay - 36, "23.99 h"},
+		{secondsPerDay, "1.00 d"},
+		{dt, "12.00 h"},
+		{secondsPerYear - secondsPerDay, "364.25 d"},
+		{secondsPerYear, "1.00 y"},
+		{100 * secondsPerYear, "100.00 y"},
+		{-2 * secondsPerDay, "-2.00 d"},
+	}
+	for _, test := range tests {
+		if got := formatDuration(test.seconds); got != test.want {
+			t.Errorf("formatDuration(%v) = %q, want %q", test.seconds, got, test.want)
+		}
+	}
+}